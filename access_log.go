@@ -0,0 +1,142 @@
+package ndn
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// AccessLogEntry describes how an interest is answered by a handler.
+type AccessLogEntry struct {
+	// Time is when the interest is received.
+	Time     time.Time
+	Interest *Interest
+	// Data is the data sent, or nil if there is none.
+	Data *Data
+	// Nack is the reason of the nack sent, or NackReasonNone.
+	Nack uint64
+	// Latency is from receiving the interest to sending data or nack,
+	// or to the handler returning without either.
+	Latency time.Duration
+	// CacheHit is true if data is answered from AccessLogOptions.Cache.
+	CacheHit bool
+	// Size is the encoded size of Data.
+	Size int
+}
+
+// AccessLogger records access log entries.
+type AccessLogger interface {
+	LogAccess(AccessLogEntry)
+}
+
+// AccessLoggerFunc is an adapter to use a function as AccessLogger.
+type AccessLoggerFunc func(AccessLogEntry)
+
+// LogAccess calls fn(ent).
+func (fn AccessLoggerFunc) LogAccess(ent AccessLogEntry) {
+	fn(ent)
+}
+
+// NewSlogAccessLogger creates an AccessLogger that logs entries to l at info level.
+func NewSlogAccessLogger(l *slog.Logger) AccessLogger {
+	return AccessLoggerFunc(func(ent AccessLogEntry) {
+		attrs := []slog.Attr{
+			slog.String("name", ent.Interest.Name.String()),
+			slog.Duration("latency", ent.Latency),
+			slog.Bool("cache_hit", ent.CacheHit),
+			slog.Int("size", ent.Size),
+		}
+		if ent.Data != nil {
+			attrs = append(attrs, slog.String("data", ent.Data.Name.String()))
+		}
+		if ent.Nack != NackReasonNone {
+			attrs = append(attrs, slog.Uint64("nack", ent.Nack))
+		}
+		l.LogAttrs(context.Background(), slog.LevelInfo, "ndn access", attrs...)
+	})
+}
+
+// AccessLogOptions controls AccessLogMiddleware.
+type AccessLogOptions struct {
+	// Logger records an entry for every interest.
+	Logger AccessLogger
+
+	// If Cache is not nil, interests are answered from Cache before handlers,
+	// and data sent by handlers is added to Cache.
+	Cache Cache
+}
+
+// AccessLogMiddleware logs how every interest is answered, like an HTTP access log.
+//
+// An entry is logged when the first data or nack is sent, or when the handler
+// returns without either; later packets for the same interest are not logged.
+// It should be the outermost middleware, so that latency includes other middleware.
+func AccessLogMiddleware(opts AccessLogOptions) Middleware {
+	return func(next InterestHandler) InterestHandler {
+		return InterestHandlerFunc(func(w Sender, i *Interest) {
+			s := &accessLogSender{
+				Sender: w,
+				opts:   opts,
+				ent: AccessLogEntry{
+					Time:     time.Now(),
+					Interest: i,
+				},
+			}
+			if opts.Cache != nil {
+				if d := opts.Cache.Get(i); d != nil {
+					s.ent.CacheHit = true
+					s.SendData(d)
+					return
+				}
+			}
+			next.ServeInterest(s, i)
+			s.log()
+		})
+	}
+}
+
+// accessLogSender logs the answer of a handler of AccessLogMiddleware.
+type accessLogSender struct {
+	Sender
+	opts AccessLogOptions
+	once sync.Once
+	ent  AccessLogEntry
+}
+
+func (s *accessLogSender) SendData(d *Data) {
+	if s.opts.Cache != nil && !s.ent.CacheHit {
+		s.opts.Cache.Add(d)
+	}
+	s.Sender.SendData(d)
+	s.once.Do(func() {
+		s.ent.Data = d
+		if bufs, err := d.Buffers(); err == nil {
+			for _, b := range bufs {
+				s.ent.Size += len(b)
+			}
+		}
+		s.logOnce()
+	})
+}
+
+// SendNack forwards the nack if the Sender implements NackSender.
+func (s *accessLogSender) SendNack(i *Interest, reason uint64) {
+	if ns, ok := s.Sender.(NackSender); ok {
+		ns.SendNack(i, reason)
+	}
+	s.once.Do(func() {
+		s.ent.Nack = reason
+		s.logOnce()
+	})
+}
+
+// log logs the entry if it is not logged yet.
+func (s *accessLogSender) log() {
+	s.once.Do(s.logOnce)
+}
+
+func (s *accessLogSender) logOnce() {
+	s.ent.Latency = time.Since(s.ent.Time)
+	s.opts.Logger.LogAccess(s.ent)
+}
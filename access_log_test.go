@@ -0,0 +1,72 @@
+package ndn
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAccessLogMiddleware(t *testing.T) {
+	mux := NewMux()
+	mux.HandleFunc("/A", func(w Sender, i *Interest) {
+		time.Sleep(20 * time.Millisecond)
+		w.SendData(&Data{Name: i.Name, Content: []byte("hello")})
+	})
+	mux.HandleFunc("/B", func(w Sender, i *Interest) {})
+	mux.HandleFunc("/C", func(w Sender, i *Interest) {
+		w.(NackSender).SendNack(i, NackReasonNoRoute)
+	})
+	var buf bytes.Buffer
+	slogger := NewSlogAccessLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+	entries := make(chan AccessLogEntry, 8)
+	h := ChainHandler(mux, AccessLogMiddleware(AccessLogOptions{
+		Logger: AccessLoggerFunc(func(ent AccessLogEntry) {
+			slogger.LogAccess(ent)
+			entries <- ent
+		}),
+		Cache: NewCache(8),
+	}))
+	c1, c2 := net.Pipe()
+	producer := NewFace(c2, WithInterestHandler(h))
+	defer producer.Close()
+	consumer := NewFace(c1)
+	defer consumer.Close()
+
+	for _, test := range []struct {
+		name     string
+		data     bool
+		nack     uint64
+		cacheHit bool
+		latency  time.Duration
+	}{
+		{name: "/A", data: true, latency: 20 * time.Millisecond},
+		{name: "/A", data: true, cacheHit: true},
+		{name: "/B"},
+		{name: "/C", nack: NackReasonNoRoute},
+	} {
+		SendInterestContext(context.Background(), consumer, &Interest{
+			Name:     NewName(test.name),
+			LifeTime: 50,
+		})
+		ent := <-entries
+		if ent.Interest.Name.String() != test.name {
+			t.Fatalf("expect %v, got %v", test.name, ent.Interest.Name)
+		}
+		if (ent.Data != nil) != test.data || ent.Nack != test.nack || ent.CacheHit != test.cacheHit {
+			t.Fatalf("%s: expect data %v, nack %v, cache hit %v, got %+v", test.name, test.data, test.nack, test.cacheHit, ent)
+		}
+		if test.data && ent.Size == 0 {
+			t.Fatalf("%s: expect size", test.name)
+		}
+		if ent.Latency < test.latency || (test.cacheHit && ent.Latency >= 20*time.Millisecond) {
+			t.Fatalf("%s: expect latency %v, got %v", test.name, test.latency, ent.Latency)
+		}
+	}
+	if !strings.Contains(buf.String(), "name=/A") || !strings.Contains(buf.String(), "cache_hit=true") {
+		t.Fatalf("expect slog output, got %s", buf.String())
+	}
+}
@@ -1,9 +1,11 @@
 package ndn
 
 import (
+	"bytes"
 	"container/list"
 	"crypto/sha256"
 	"fmt"
+	"io"
 	"sync"
 	"time"
 
@@ -12,6 +14,9 @@ import (
 )
 
 // Cache stores data packet and finds data packet by interest
+//
+// The name of an interest matches data packets with the same name prefix,
+// and ChildSelector picks the leftmost or rightmost child among them.
 type Cache interface {
 	Add(*Data)
 	Get(*Interest) *Data
@@ -19,46 +24,199 @@ type Cache interface {
 
 // NewCache creates a new thread-safe in-memory LRU content store
 func NewCache(size int) Cache {
-	return &cache{
-		List: list.New(),
-		size: size,
-	}
+	return NewLRUCache(CacheOptions{MaxEntries: size})
+}
+
+// CacheOptions controls NewLRUCache and NewPriorityFIFOCache.
+type CacheOptions struct {
+	// MaxEntries is the maximum number of data packets.
+	// If it is 0 and MaxBytes is set, only MaxBytes applies.
+	MaxEntries int
+
+	// MaxBytes is the maximum total size of encoded data packets.
+	// If it is 0, only MaxEntries applies.
+	MaxBytes int
+
+	// Admit decides whether a data packet is added, for example to skip
+	// encrypted or single-use data.
+	// If it is nil, all data packets are added.
+	//
+	// Data packets with CacheHintNoCache are never added.
+	Admit func(*Data) bool
+}
+
+// NewLRUCache creates a new thread-safe in-memory content store, which evicts
+// the least recently used data packets when either limit of opts is exceeded.
+func NewLRUCache(opts CacheOptions) Cache {
+	return newCache(opts, &lruPolicy{List: list.New()})
+}
+
+// NewPriorityFIFOCache creates a new thread-safe in-memory content store with
+// the priority-FIFO policy of NFD.
+//
+// When either limit of opts is exceeded, unsolicited data packets are evicted first,
+// then stale ones, and then fresh ones, each in the order they are added.
+func NewPriorityFIFOCache(opts CacheOptions) Cache {
+	c := newCache(opts, nil)
+	c.policy = newPriorityFIFOPolicy(&c.Mutex)
+	return c
+}
+
+// UnsolicitedCache is a cache that stores unsolicited data packets
+// at the lowest priority, such as caches created by NewPriorityFIFOCache.
+type UnsolicitedCache interface {
+	Cache
+	AddUnsolicited(*Data)
 }
 
 type cache struct {
 	cacheMatcher
-	*list.List
-	size int
+	policy cachePolicy
+	opts   CacheOptions
 	sync.Mutex
+
+	// bytes is the total size of data packets.
+	bytes        int
+	hits, misses uint64
+}
+
+func newCache(opts CacheOptions, policy cachePolicy) *cache {
+	return &cache{
+		policy: policy,
+		opts:   opts,
+	}
+}
+
+// CacheStats is a snapshot of cache counters.
+type CacheStats struct {
+	Entries int
+	Bytes   int
+	Hits    uint64
+	Misses  uint64
+
+	// Stale is the number of stale data packets.
+	Stale int
+}
+
+// StatsCache is a cache that counts lookups, such as caches created by NewCache.
+type StatsCache interface {
+	Cache
+	Stats() CacheStats
+}
+
+func (c *cache) Stats() CacheStats {
+	c.Lock()
+	defer c.Unlock()
+	now := time.Now()
+	var stale int
+	c.Match(nil, func(m map[string]*cacheEntry) {
+		for _, ent := range m {
+			if !ent.fresh(now) {
+				stale++
+			}
+		}
+	}, true)
+	return CacheStats{
+		Entries: c.policy.len(),
+		Bytes:   c.bytes,
+		Hits:    c.hits,
+		Misses:  c.misses,
+		Stale:   stale,
+	}
 }
 
 type cacheEntry struct {
 	*Data
 	time.Time
-	remove func()
+	// size is the size of the encoded data packet.
+	size int
+	// components is the full name with the implicit digest, and
+	// key identifies the entry in the index.
+	components []lpm.Component
+	key        string
+	// unsolicited is set if the data packet is not requested.
+	unsolicited bool
+	// elem is the position in a queue of the replacement policy.
+	elem *list.Element
+	// queue and timer are used by priorityFIFOPolicy.
+	queue int
+	timer *time.Timer
 }
 
-func (c *cache) Add(d *Data) {
+// freshnessPeriod returns how long the data packet stays fresh after it is added.
+//
+// Data without FreshnessPeriod is stale as soon as it is added.
+func (ent *cacheEntry) freshnessPeriod() time.Duration {
+	return time.Duration(ent.MetaInfo.FreshnessPeriod) * time.Millisecond
+}
+
+// fresh returns whether the data packet is still fresh at now.
+// Stale data stays in the cache, and can still satisfy interests without MustBeFresh.
+func (ent *cacheEntry) fresh(now time.Time) bool {
+	return now.Sub(ent.Time) < ent.freshnessPeriod()
+}
+
+// dataDigest computes the implicit SHA256 digest of a data packet.
+func dataDigest(d *Data) ([]byte, error) {
+	b, _, err := dataDigestSize(d)
+	return b, err
+}
+
+// dataDigestSize is like dataDigest, but also returns the size of the encoded data packet.
+func dataDigestSize(d *Data) ([]byte, int, error) {
 	h := sha256.New()
-	err := d.WriteTo(tlv.NewWriter(h))
+	w := &sizeWriter{Writer: h}
+	err := d.WriteTo(tlv.NewWriter(w))
+	if err != nil {
+		return nil, 0, err
+	}
+	return h.Sum(nil), w.n, nil
+}
+
+// sizeWriter counts bytes written to Writer.
+type sizeWriter struct {
+	io.Writer
+	n int
+}
+
+func (w *sizeWriter) Write(b []byte) (int, error) {
+	n, err := w.Writer.Write(b)
+	w.n += n
+	return n, err
+}
+
+func (c *cache) Add(d *Data) {
+	c.add(d, false)
+}
+
+func (c *cache) AddUnsolicited(d *Data) {
+	c.add(d, true)
+}
+
+func (c *cache) add(d *Data, unsolicited bool) {
+	if d.MetaInfo.CacheHint == CacheHintNoCache || (c.opts.Admit != nil && !c.opts.Admit(d)) {
+		return
+	}
+	b, size, err := dataDigestSize(d)
 	if err != nil {
 		return
 	}
-	digest := lpm.Component(h.Sum(nil))
+	digest := lpm.Component(b)
 
-	components := append(d.Name.Components, digest)
+	components := append(d.Name.Components[:len(d.Name.Components):len(d.Name.Components)], digest)
 	key := fmt.Sprintf("%s/%s", d.Name, digest)
 
 	c.Lock()
 	defer c.Unlock()
-	// check for existing element
+	// check for existing entry
 	var exist bool
-	c.Match(components, func(m map[string]*list.Element) {
-		if m == nil {
-			return
-		}
-		if elem, ok := m[key]; ok {
-			c.MoveToFront(elem)
+	c.Match(components, func(m map[string]*cacheEntry) {
+		if ent, ok := m[key]; ok {
+			// the same data arrives again, so it is fresh again
+			c.policy.remove(ent)
+			ent.Time = time.Now()
+			ent.unsolicited = ent.unsolicited && unsolicited
+			c.policy.insert(ent)
 			exist = true
 		}
 	}, false)
@@ -66,80 +224,244 @@ func (c *cache) Add(d *Data) {
 		return
 	}
 
-	// add new element
-	elem := c.PushFront(cacheEntry{
-		Data: d,
-		Time: time.Now(),
-		remove: func() {
-			c.UpdateAll(components, func(_ []lpm.Component, m map[string]*list.Element) map[string]*list.Element {
-				if m == nil {
-					return nil
-				}
-				delete(m, key)
-				if len(m) == 0 {
-					return nil
-				}
-				return m
-			}, false)
-		},
-	})
-	c.UpdateAll(components, func(_ []lpm.Component, m map[string]*list.Element) map[string]*list.Element {
+	// add new entry
+	ent := &cacheEntry{
+		Data:        d,
+		Time:        time.Now(),
+		size:        size,
+		components:  components,
+		key:         key,
+		unsolicited: unsolicited,
+	}
+	c.policy.insert(ent)
+	c.UpdateAll(components, func(_ []lpm.Component, m map[string]*cacheEntry) map[string]*cacheEntry {
 		if m == nil {
-			m = make(map[string]*list.Element)
+			m = make(map[string]*cacheEntry)
 		}
-		m[key] = elem
+		m[key] = ent
 		return m
 	}, false)
+	c.bytes += size
 
-	// evict oldest element
-	if c.Len() <= c.size {
-		return
+	// evict entries chosen by the policy
+	for c.full() {
+		ent := c.policy.evict()
+		if ent == nil {
+			return
+		}
+		c.remove(ent)
 	}
-	elem = c.Back()
-	if elem == nil {
-		return
+}
+
+// full returns true if either limit of opts is exceeded.
+func (c *cache) full() bool {
+	if c.opts.MaxBytes > 0 {
+		return c.bytes > c.opts.MaxBytes ||
+			(c.opts.MaxEntries > 0 && c.policy.len() > c.opts.MaxEntries)
 	}
-	c.Remove(elem).(cacheEntry).remove()
+	return c.policy.len() > c.opts.MaxEntries
+}
+
+// remove removes ent from the index; ent is already removed from the policy.
+func (c *cache) remove(ent *cacheEntry) {
+	c.UpdateAll(ent.components, func(_ []lpm.Component, m map[string]*cacheEntry) map[string]*cacheEntry {
+		delete(m, ent.key)
+		if len(m) == 0 {
+			return nil
+		}
+		return m
+	}, true)
+	c.bytes -= ent.size
 }
 
 func (c *cache) Get(i *Interest) *Data {
 	components := i.Name.Components
 	if len(i.Name.ImplicitDigestSHA256) != 0 {
-		components = append(components, i.Name.ImplicitDigestSHA256)
+		components = append(components[:len(components):len(components)], i.Name.ImplicitDigestSHA256)
 	}
 
 	c.Lock()
 	defer c.Unlock()
-	var match *list.Element
-	c.Match(components, func(m map[string]*list.Element) {
-		for _, elem := range m {
-			ent := elem.Value.(cacheEntry)
+	now := time.Now()
+	var match *cacheEntry
+	c.Match(components, func(m map[string]*cacheEntry) {
+		for _, ent := range m {
 			if !i.Selectors.Match(ent.Data, i.Name.Len()) {
 				continue
 			}
-			if i.Selectors.MustBeFresh && time.Since(ent.Time) > time.Duration(ent.MetaInfo.FreshnessPeriod)*time.Millisecond {
+			if i.Selectors.MustBeFresh && !ent.fresh(now) {
 				continue
 			}
-			if match == nil {
-				match = elem
-			} else {
-				cmp := ent.Name.Compare(match.Value.(cacheEntry).Name)
-				switch i.Selectors.ChildSelector {
-				case 0:
-					if cmp < 0 {
-						match = elem
-					}
-				case 1:
-					if cmp > 0 {
-						match = elem
-					}
-				}
+			if match == nil || ent.prefer(match, i.Name.Len(), i.Selectors.ChildSelector) {
+				match = ent
 			}
 		}
 	}, false)
 	if match != nil {
-		c.MoveToFront(match)
-		return match.Value.(cacheEntry).Data
+		c.hits++
+		c.policy.hit(match)
+		return match.Data
 	}
+	c.misses++
 	return nil
 }
+
+// prefer returns whether ent is preferred to other for an interest
+// with a name of length n.
+//
+// The leftmost child after the interest name is preferred if childSelector is 0,
+// or the rightmost child if it is 1; the data with the interest name itself comes
+// before any child, because its only child is the implicit digest.
+// Under the same child, the leftmost data is preferred.
+func (ent *cacheEntry) prefer(other *cacheEntry, n int, childSelector uint64) bool {
+	if cmp := compareChild(ent.Name, other.Name, n); cmp != 0 {
+		if childSelector == 1 {
+			return cmp > 0
+		}
+		return cmp < 0
+	}
+	if cmp := ent.Name.Compare(other.Name); cmp != 0 {
+		return cmp < 0
+	}
+	return ent.key < other.key
+}
+
+// compareChild compares the components of two names at n.
+// A name of length n has no child, which comes first.
+func compareChild(n1, n2 Name, n int) int {
+	switch {
+	case n1.Len() <= n && n2.Len() <= n:
+		return 0
+	case n1.Len() <= n:
+		return -1
+	case n2.Len() <= n:
+		return 1
+	}
+	return bytes.Compare(n1.Components[n], n2.Components[n])
+}
+
+// cachePolicy decides which entry is evicted from a cache.
+//
+// It is called with the cache locked.
+type cachePolicy interface {
+	insert(ent *cacheEntry)
+	remove(ent *cacheEntry)
+	// hit is called when ent satisfies an interest.
+	hit(ent *cacheEntry)
+	// evict removes the next entry to evict, or returns nil if there is none.
+	evict() *cacheEntry
+	len() int
+}
+
+// lruPolicy evicts the least recently used entry.
+type lruPolicy struct {
+	*list.List
+}
+
+func (p *lruPolicy) insert(ent *cacheEntry) {
+	ent.elem = p.PushFront(ent)
+}
+
+func (p *lruPolicy) remove(ent *cacheEntry) {
+	p.Remove(ent.elem)
+}
+
+func (p *lruPolicy) hit(ent *cacheEntry) {
+	p.MoveToFront(ent.elem)
+}
+
+func (p *lruPolicy) evict() *cacheEntry {
+	elem := p.Back()
+	if elem == nil {
+		return nil
+	}
+	return p.Remove(elem).(*cacheEntry)
+}
+
+func (p *lruPolicy) len() int {
+	return p.Len()
+}
+
+const (
+	queueUnsolicited = iota
+	queueStale
+	queueFresh
+)
+
+// priorityFIFOPolicy evicts unsolicited entries first, then stale entries,
+// and then fresh entries; each queue is in FIFO order.
+type priorityFIFOPolicy struct {
+	// mu is the lock of the cache, which is held by timers that mark entries stale.
+	mu     *sync.Mutex
+	queues [3]*list.List
+}
+
+func newPriorityFIFOPolicy(mu *sync.Mutex) *priorityFIFOPolicy {
+	p := &priorityFIFOPolicy{mu: mu}
+	for i := range p.queues {
+		p.queues[i] = list.New()
+	}
+	return p
+}
+
+func (p *priorityFIFOPolicy) insert(ent *cacheEntry) {
+	freshness := ent.freshnessPeriod()
+	switch {
+	case ent.unsolicited:
+		ent.queue = queueUnsolicited
+	case freshness > 0:
+		ent.queue = queueFresh
+		var timer *time.Timer
+		timer = time.AfterFunc(freshness, func() {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			if ent.timer == timer {
+				p.markStale(ent)
+			}
+		})
+		ent.timer = timer
+	default:
+		ent.queue = queueStale
+	}
+	ent.elem = p.queues[ent.queue].PushBack(ent)
+}
+
+// markStale moves ent from the fresh queue to the stale queue.
+func (p *priorityFIFOPolicy) markStale(ent *cacheEntry) {
+	p.queues[queueFresh].Remove(ent.elem)
+	ent.timer = nil
+	ent.queue = queueStale
+	ent.elem = p.queues[queueStale].PushBack(ent)
+}
+
+func (p *priorityFIFOPolicy) remove(ent *cacheEntry) {
+	p.queues[ent.queue].Remove(ent.elem)
+	ent.elem = nil
+	if ent.timer != nil {
+		ent.timer.Stop()
+		ent.timer = nil
+	}
+}
+
+func (p *priorityFIFOPolicy) hit(*cacheEntry) {}
+
+func (p *priorityFIFOPolicy) evict() *cacheEntry {
+	for _, q := range p.queues {
+		elem := q.Front()
+		if elem == nil {
+			continue
+		}
+		ent := elem.Value.(*cacheEntry)
+		p.remove(ent)
+		return ent
+	}
+	return nil
+}
+
+func (p *priorityFIFOPolicy) len() int {
+	var n int
+	for _, q := range p.queues {
+		n += q.Len()
+	}
+	return n
+}
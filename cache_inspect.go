@@ -0,0 +1,65 @@
+package ndn
+
+import (
+	"sort"
+	"time"
+)
+
+// CacheEntry describes a data packet in a cache.
+type CacheEntry struct {
+	// Name is the full name with ImplicitDigestSHA256.
+	Name Name
+	// Added is when the data packet is added, or added again.
+	Added time.Time
+	// Stale is set if FreshnessPeriod has passed since Added.
+	Stale bool
+	// Size is the size of the encoded data packet.
+	Size int
+	// Unsolicited is set if the data packet is not requested.
+	Unsolicited bool
+}
+
+// InspectCache is a cache whose contents can be listed, such as caches created by NewCache.
+type InspectCache interface {
+	Cache
+	// Entries lists data packets under prefix in name order.
+	Entries(prefix Name) []CacheEntry
+}
+
+func (c *cache) Entries(prefix Name) []CacheEntry {
+	components := prefix.Components
+	if len(prefix.ImplicitDigestSHA256) != 0 {
+		components = append(components[:len(components):len(components)], prefix.ImplicitDigestSHA256)
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	now := time.Now()
+	var ents []*cacheEntry
+	c.Match(components, func(m map[string]*cacheEntry) {
+		for _, ent := range m {
+			ents = append(ents, ent)
+		}
+	}, false)
+	sort.Slice(ents, func(i, j int) bool {
+		if cmp := ents[i].Name.Compare(ents[j].Name); cmp != 0 {
+			return cmp < 0
+		}
+		return ents[i].key < ents[j].key
+	})
+
+	list := make([]CacheEntry, len(ents))
+	for i, ent := range ents {
+		list[i] = CacheEntry{
+			Name: Name{
+				Components:           ent.Name.Components,
+				ImplicitDigestSHA256: ent.components[len(ent.components)-1],
+			},
+			Added:       ent.Time,
+			Stale:       !ent.fresh(now),
+			Size:        ent.size,
+			Unsolicited: ent.unsolicited,
+		}
+	}
+	return list
+}
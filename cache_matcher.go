@@ -1,18 +1,15 @@
 package ndn
 
-import (
-	"container/list"
-	"github.com/go-ndn/lpm"
-)
+import "github.com/go-ndn/lpm"
 
 type cacheMatcher struct {
 	cacheNode
 }
 
-var cacheNodeValEmpty func(map[string]*list.Element) bool
+var cacheNodeValEmpty func(map[string]*cacheEntry) bool
 
 type cacheNode struct {
-	val   map[string]*list.Element
+	val   map[string]*cacheEntry
 	table map[string]cacheNode
 }
 
@@ -20,7 +17,7 @@ func (n *cacheNode) empty() bool {
 	return cacheNodeValEmpty(n.val) && len(n.table) == 0
 }
 
-func (n *cacheNode) update(key []lpm.Component, depth int, f func([]lpm.Component, map[string]*list.Element) map[string]*list.Element, exist, all bool) {
+func (n *cacheNode) update(key []lpm.Component, depth int, f func([]lpm.Component, map[string]*cacheEntry) map[string]*cacheEntry, exist, all bool) {
 	try := func() {
 		if !exist || !cacheNodeValEmpty(n.val) {
 			n.val = f(key[:depth], n.val)
@@ -59,7 +56,7 @@ func (n *cacheNode) update(key []lpm.Component, depth int, f func([]lpm.Componen
 	}
 }
 
-func (n *cacheNode) match(key []lpm.Component, depth int, f func(map[string]*list.Element), exist bool) {
+func (n *cacheNode) match(key []lpm.Component, depth int, f func(map[string]*cacheEntry), exist bool) {
 	try := func() {
 		if !exist || !cacheNodeValEmpty(n.val) {
 			f(n.val)
@@ -88,7 +85,7 @@ func (n *cacheNode) match(key []lpm.Component, depth int, f func(map[string]*lis
 	v.match(key, depth+1, f, exist)
 }
 
-func (n *cacheNode) visit(key []lpm.Component, f func([]lpm.Component, map[string]*list.Element) map[string]*list.Element) {
+func (n *cacheNode) visit(key []lpm.Component, f func([]lpm.Component, map[string]*cacheEntry) map[string]*cacheEntry) {
 	if !cacheNodeValEmpty(n.val) {
 		n.val = f(key, n.val)
 	}
@@ -102,21 +99,21 @@ func (n *cacheNode) visit(key []lpm.Component, f func([]lpm.Component, map[strin
 	}
 }
 
-func (n *cacheNode) Update(key []lpm.Component, f func(map[string]*list.Element) map[string]*list.Element, exist bool) {
-	n.update(key, 0, func(_ []lpm.Component, v map[string]*list.Element) map[string]*list.Element {
+func (n *cacheNode) Update(key []lpm.Component, f func(map[string]*cacheEntry) map[string]*cacheEntry, exist bool) {
+	n.update(key, 0, func(_ []lpm.Component, v map[string]*cacheEntry) map[string]*cacheEntry {
 		return f(v)
 	}, exist, false)
 }
 
-func (n *cacheNode) UpdateAll(key []lpm.Component, f func([]lpm.Component, map[string]*list.Element) map[string]*list.Element, exist bool) {
+func (n *cacheNode) UpdateAll(key []lpm.Component, f func([]lpm.Component, map[string]*cacheEntry) map[string]*cacheEntry, exist bool) {
 	n.update(key, 0, f, exist, true)
 }
 
-func (n *cacheNode) Match(key []lpm.Component, f func(map[string]*list.Element), exist bool) {
+func (n *cacheNode) Match(key []lpm.Component, f func(map[string]*cacheEntry), exist bool) {
 	n.match(key, 0, f, exist)
 }
 
-func (n *cacheNode) Visit(f func([]lpm.Component, map[string]*list.Element) map[string]*list.Element) {
+func (n *cacheNode) Visit(f func([]lpm.Component, map[string]*cacheEntry) map[string]*cacheEntry) {
 	key := make([]lpm.Component, 0, 16)
 	n.visit(key, f)
 }
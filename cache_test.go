@@ -1,6 +1,10 @@
 package ndn
 
-import "testing"
+import (
+	"fmt"
+	"testing"
+	"time"
+)
 
 func TestCache(t *testing.T) {
 	c := NewCache(5)
@@ -71,4 +75,276 @@ func TestCache(t *testing.T) {
 			t.Fatalf("Get(%v) == %v, got %v", test.in, test.want, got)
 		}
 	}
+
+	// /A/B is evicted
+	var bytes int
+	for _, name := range []string{"/A", "/A/B/C", "/A/C", "/BB", "/D/E"} {
+		_, n, err := dataDigestSize(&Data{Name: NewName(name)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		bytes += n
+	}
+	want := CacheStats{Entries: 5, Bytes: bytes, Hits: 5, Misses: 2, Stale: 5}
+	if got := c.(StatsCache).Stats(); got != want {
+		t.Fatalf("expect %+v, got %+v", want, got)
+	}
+}
+
+func TestLRUCacheBytes(t *testing.T) {
+	size := func(d *Data) int {
+		_, n, err := dataDigestSize(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+	small := &Data{Name: NewName("/A/small")}
+	large := &Data{Name: NewName("/A/large"), Content: make([]byte, 1000)}
+	c := NewLRUCache(CacheOptions{
+		MaxEntries: 10,
+		MaxBytes:   size(large) + size(small),
+	})
+	c.Add(small)
+	c.Add(large)
+	if got := c.(StatsCache).Stats().Bytes; got != size(large)+size(small) {
+		t.Fatalf("expect %d bytes, got %d", size(large)+size(small), got)
+	}
+	// the least recently used data is evicted
+	c.Get(&Interest{Name: NewName("/A/small")})
+	c.Add(&Data{Name: NewName("/A/LARGE"), Content: make([]byte, 1000)})
+	if c.Get(&Interest{Name: NewName("/A/large")}) != nil {
+		t.Fatal("expect /A/large evicted")
+	}
+	if c.Get(&Interest{Name: NewName("/A/small")}) == nil {
+		t.Fatal("expect /A/small cached")
+	}
+	if got := c.(StatsCache).Stats().Bytes; got > size(large)+size(small) {
+		t.Fatalf("expect at most %d bytes, got %d", size(large)+size(small), got)
+	}
+}
+
+func TestCacheMaxBytesOnly(t *testing.T) {
+	d := &Data{Name: NewName("/A/1"), Content: make([]byte, 100)}
+	_, size, err := dataDigestSize(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []Cache{
+		NewLRUCache(CacheOptions{MaxBytes: 2 * size}),
+		NewPriorityFIFOCache(CacheOptions{MaxBytes: 2 * size}),
+	} {
+		for n := 1; n <= 3; n++ {
+			c.Add(&Data{Name: NewName(fmt.Sprintf("/A/%d", n)), Content: make([]byte, 100)})
+		}
+		if got := c.(StatsCache).Stats().Entries; got != 2 {
+			t.Fatalf("expect 2 entries, got %d", got)
+		}
+		if c.Get(&Interest{Name: NewName("/A/3")}) == nil {
+			t.Fatal("expect /A/3 cached")
+		}
+		if c.Get(&Interest{Name: NewName("/A/1")}) != nil {
+			t.Fatal("expect /A/1 evicted")
+		}
+	}
+}
+
+func TestPriorityFIFOCache(t *testing.T) {
+	c := NewPriorityFIFOCache(CacheOptions{MaxEntries: 3})
+	fresh := func(name string, ms uint64) *Data {
+		d := &Data{Name: NewName(name)}
+		d.MetaInfo.FreshnessPeriod = ms
+		return d
+	}
+	c.Add(fresh("/A/1", 3600000))
+	c.Add(fresh("/A/2", 0))
+	c.(UnsolicitedCache).AddUnsolicited(fresh("/A/3", 3600000))
+	c.Add(fresh("/A/4", 50))
+
+	// unsolicited data is evicted first
+	if c.Get(&Interest{Name: NewName("/A/3")}) != nil {
+		t.Fatal("expect /A/3 evicted")
+	}
+	// then stale data
+	c.Add(fresh("/A/5", 3600000))
+	if c.Get(&Interest{Name: NewName("/A/2")}) != nil {
+		t.Fatal("expect /A/2 evicted")
+	}
+	// /A/4 becomes stale
+	time.Sleep(100 * time.Millisecond)
+	c.Add(fresh("/A/6", 3600000))
+	if c.Get(&Interest{Name: NewName("/A/4")}) != nil {
+		t.Fatal("expect /A/4 evicted")
+	}
+	// then fresh data in FIFO order
+	c.Add(fresh("/A/7", 3600000))
+	if c.Get(&Interest{Name: NewName("/A/1")}) != nil {
+		t.Fatal("expect /A/1 evicted")
+	}
+	for _, name := range []string{"/A/5", "/A/6", "/A/7"} {
+		if c.Get(&Interest{Name: NewName(name)}) == nil {
+			t.Fatalf("expect %s cached", name)
+		}
+	}
+	if got := c.(StatsCache).Stats().Entries; got != 3 {
+		t.Fatalf("expect %v, got %v", 3, got)
+	}
+}
+
+func TestCacheFreshness(t *testing.T) {
+	for _, c := range []Cache{
+		NewLRUCache(CacheOptions{MaxEntries: 10}),
+		NewPriorityFIFOCache(CacheOptions{MaxEntries: 10}),
+	} {
+		d := &Data{Name: NewName("/A/fresh")}
+		d.MetaInfo.FreshnessPeriod = 50
+		c.Add(d)
+		c.Add(&Data{Name: NewName("/A/stale")})
+
+		get := func(name string, mustBeFresh bool) bool {
+			return c.Get(&Interest{
+				Name:      NewName(name),
+				Selectors: Selectors{MustBeFresh: mustBeFresh},
+			}) != nil
+		}
+		for _, test := range []struct {
+			name        string
+			mustBeFresh bool
+			want        bool
+		}{
+			{"/A/fresh", true, true},
+			{"/A/fresh", false, true},
+			{"/A/stale", true, false},
+			{"/A/stale", false, true},
+		} {
+			if got := get(test.name, test.mustBeFresh); got != test.want {
+				t.Fatalf("%s MustBeFresh=%v: expect %v, got %v", test.name, test.mustBeFresh, test.want, got)
+			}
+		}
+
+		// stale data is kept for interests without MustBeFresh
+		time.Sleep(100 * time.Millisecond)
+		if get("/A/fresh", true) {
+			t.Fatal("expect /A/fresh stale")
+		}
+		if !get("/A/fresh", false) {
+			t.Fatal("expect /A/fresh cached")
+		}
+
+		// data added again is fresh again
+		c.Add(d)
+		if !get("/A/fresh", true) {
+			t.Fatal("expect /A/fresh fresh")
+		}
+		if got := c.(StatsCache).Stats().Entries; got != 2 {
+			t.Fatalf("expect %v, got %v", 2, got)
+		}
+	}
+}
+
+func TestCachePrefixMatch(t *testing.T) {
+	c := NewCache(10)
+	for _, name := range []string{
+		"/a/b/v1/s0",
+		"/a/b/v2/s0",
+		"/a/b/v2/s1",
+		"/a/c",
+		"/a/c/z",
+	} {
+		c.Add(&Data{Name: NewName(name)})
+	}
+	for _, test := range []struct {
+		in            string
+		childSelector uint64
+		maxComponents uint64
+		want          string
+	}{
+		{in: "/a", want: "/a/b/v1/s0"},
+		{in: "/a", childSelector: 1, want: "/a/c"},
+		{in: "/a/b", childSelector: 1, want: "/a/b/v2/s0"},
+		{in: "/a/c", want: "/a/c"},
+		{in: "/a/c", childSelector: 1, want: "/a/c/z"},
+		{in: "/a/b/v2", want: "/a/b/v2/s0"},
+		{in: "/a/b", maxComponents: 2},
+	} {
+		d := c.Get(&Interest{
+			Name: NewName(test.in),
+			Selectors: Selectors{
+				ChildSelector: test.childSelector,
+				MaxComponents: test.maxComponents,
+			},
+		})
+		var got string
+		if d != nil {
+			got = d.Name.String()
+		}
+		if got != test.want {
+			t.Fatalf("Get(%v) == %v, got %v", test.in, test.want, got)
+		}
+	}
+}
+
+func TestCacheAdmit(t *testing.T) {
+	c := NewLRUCache(CacheOptions{
+		MaxEntries: 10,
+		Admit: func(d *Data) bool {
+			return d.MetaInfo.EncryptionType == EncryptionTypeNone
+		},
+	})
+	for _, test := range []struct {
+		d    *Data
+		want bool
+	}{
+		{&Data{Name: NewName("/A")}, true},
+		{&Data{Name: NewName("/B"), MetaInfo: MetaInfo{EncryptionType: EncryptionTypeAESWithCTR}}, false},
+		{&Data{Name: NewName("/C"), MetaInfo: MetaInfo{CacheHint: CacheHintNoCache}}, false},
+	} {
+		c.Add(test.d)
+		if got := c.Get(&Interest{Name: test.d.Name}) != nil; got != test.want {
+			t.Fatalf("%v: expect %v, got %v", test.d.Name, test.want, got)
+		}
+	}
+}
+
+func TestCacheEntries(t *testing.T) {
+	c := NewPriorityFIFOCache(CacheOptions{MaxEntries: 10})
+	fresh := &Data{Name: NewName("/A/B/1")}
+	fresh.MetaInfo.FreshnessPeriod = 3600000
+	c.Add(fresh)
+	c.Add(&Data{Name: NewName("/A/B/0")})
+	c.(UnsolicitedCache).AddUnsolicited(&Data{Name: NewName("/A/C")})
+	c.Add(&Data{Name: NewName("/B")})
+
+	ents := c.(InspectCache).Entries(NewName("/A/B"))
+	var names []string
+	for _, ent := range ents {
+		names = append(names, ent.Name.String())
+		if len(ent.Name.ImplicitDigestSHA256) != 32 {
+			t.Fatalf("expect implicit digest, got %v", ent.Name.ImplicitDigestSHA256)
+		}
+		if ent.Size == 0 || ent.Added.IsZero() {
+			t.Fatalf("expect size and time, got %+v", ent)
+		}
+	}
+	if fmt.Sprint(names) != "[/A/B/0 /A/B/1]" {
+		t.Fatalf("expect %v, got %v", "[/A/B/0 /A/B/1]", names)
+	}
+	if !ents[0].Stale || ents[1].Stale {
+		t.Fatalf("expect only /A/B/0 stale, got %+v", ents)
+	}
+
+	ents = c.(InspectCache).Entries(NewName("/A/C"))
+	if len(ents) != 1 || !ents[0].Unsolicited {
+		t.Fatalf("expect unsolicited /A/C, got %+v", ents)
+	}
+	// the full name is also a prefix
+	if got := len(c.(InspectCache).Entries(ents[0].Name)); got != 1 {
+		t.Fatalf("expect %v, got %v", 1, got)
+	}
+	if got := len(c.(InspectCache).Entries(Name{})); got != 4 {
+		t.Fatalf("expect %v, got %v", 4, got)
+	}
+	if got := c.(StatsCache).Stats().Stale; got != 3 {
+		t.Fatalf("expect %v, got %v", 3, got)
+	}
 }
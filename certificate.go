@@ -0,0 +1,137 @@
+package ndn
+
+import (
+	"time"
+
+	"github.com/go-ndn/lpm"
+)
+
+// Certificate contains the fields of NDN certificate format v2.0.
+//
+// The certificate data packet is named /<identity>/KEY/<key-id>/<issuer-id>/<version>.
+// Its content is the public key in SubjectPublicKeyInfo, and
+// its SignatureInfo contains ValidityPeriod.
+//
+// See https://named-data.net/doc/ndn-cxx/current/specs/certificate-format.html.
+type Certificate struct {
+	// Name is the key name.
+	Name      Name
+	IssuerID  lpm.Component
+	Version   uint64
+	PublicKey []byte
+	NotBefore time.Time
+	NotAfter  time.Time
+}
+
+// ContentTypeKey is the content type of certificates.
+const ContentTypeKey uint64 = 2
+
+// Certificate freshness period in milliseconds.
+const certificateFreshnessPeriod = 3600000 // 1 hour
+
+// IssueCertificate creates a certificate data packet signed by issuer.
+func IssueCertificate(cert *Certificate, issuer Signer) (d *Data, err error) {
+	d = &Data{
+		Name: CertificateName(cert.Name, cert.IssuerID, cert.Version),
+		MetaInfo: MetaInfo{
+			ContentType:     ContentTypeKey,
+			FreshnessPeriod: certificateFreshnessPeriod,
+		},
+		Content: cert.PublicKey,
+		SignatureInfo: SignatureInfo{
+			ValidityPeriod: ValidityPeriod{
+				NotBefore: cert.NotBefore.UTC().Format(ISO8601),
+				NotAfter:  cert.NotAfter.UTC().Format(ISO8601),
+			},
+		},
+	}
+	err = SignData(issuer, d)
+	return
+}
+
+// selfSignedValidity is the validity period of self-signed certificates,
+// which is the same as ndnsec key-gen.
+const selfSignedValidity = 20 * 365 * 24 * time.Hour
+
+// SelfSign creates a self-signed certificate like ndnsec key-gen.
+//
+// The certificate is named /<key name>/self/<version>, where version is
+// the current timestamp in milliseconds. It is valid from 1 second ago for 20 years.
+// Symmetric keys are not supported because their public part is the secret.
+func SelfSign(key Key) (*Data, error) {
+	if key.SignatureType() == SignatureTypeSHA256WithHMAC {
+		return nil, ErrNotSupported
+	}
+	public, err := key.Public()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return IssueCertificate(&Certificate{
+		Name:      key.Locator(),
+		IssuerID:  lpm.Component("self"),
+		Version:   uint64(now.UnixNano() / int64(time.Millisecond)),
+		PublicKey: public,
+		NotBefore: now.Add(-time.Second),
+		NotAfter:  now.Add(selfSignedValidity),
+	}, key)
+}
+
+// ParseCertificate decodes a certificate data packet.
+//
+// Signature will not be verified.
+// If the name does not follow certificate format v2.0,
+// the whole name is considered as the key name.
+func ParseCertificate(d *Data) (cert *Certificate, err error) {
+	if d.MetaInfo.ContentType != ContentTypeKey {
+		err = ErrNotSupported
+		return
+	}
+	cert = &Certificate{
+		Name:      d.Name,
+		PublicKey: d.Content,
+	}
+	if key, issuerID, version, ok := ParseCertificateName(d.Name); ok {
+		cert.Name = key
+		cert.IssuerID = issuerID
+		cert.Version = version
+	}
+	if d.SignatureInfo.ValidityPeriod.NotBefore != "" {
+		cert.NotBefore, err = time.Parse(ISO8601, d.SignatureInfo.ValidityPeriod.NotBefore)
+		if err != nil {
+			return
+		}
+	}
+	if d.SignatureInfo.ValidityPeriod.NotAfter != "" {
+		cert.NotAfter, err = time.Parse(ISO8601, d.SignatureInfo.ValidityPeriod.NotAfter)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// checkValidity checks whether now is within vp, which is extended by skew on both ends.
+//
+// Empty NotBefore or NotAfter is not checked.
+func checkValidity(vp *ValidityPeriod, now time.Time, skew time.Duration) error {
+	if vp.NotBefore != "" {
+		t, err := time.Parse(ISO8601, vp.NotBefore)
+		if err != nil {
+			return ErrInvalidSignature
+		}
+		if now.Before(t.Add(-skew)) {
+			return ErrCertificateExpired
+		}
+	}
+	if vp.NotAfter != "" {
+		t, err := time.Parse(ISO8601, vp.NotAfter)
+		if err != nil {
+			return ErrInvalidSignature
+		}
+		if now.After(t.Add(skew)) {
+			return ErrCertificateExpired
+		}
+	}
+	return nil
+}
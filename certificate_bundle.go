@@ -0,0 +1,162 @@
+package ndn
+
+import (
+	"bytes"
+
+	"github.com/go-ndn/lpm"
+	"github.com/go-ndn/tlv"
+)
+
+// bundleComponent marks the name of a certificate bundle.
+const bundleComponent = "BUNDLE"
+
+// CertificateBundleName returns /<prefix>/BUNDLE, where a producer
+// publishes the certificate chain of data packets under prefix.
+func CertificateBundleName(prefix Name) Name {
+	return appendName(prefix, lpm.Component(bundleComponent))
+}
+
+// NewCertificateBundle creates a data packet named name whose content
+// is the concatenation of certs.
+//
+// The bundle is not signed. Certificates in a bundle are only hints, and
+// they are still validated up to a trust anchor when they are used.
+func NewCertificateBundle(name Name, certs []*Data) (*Data, error) {
+	buf := new(bytes.Buffer)
+	w := tlv.NewWriter(buf)
+	for _, cert := range certs {
+		err := cert.WriteTo(w)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &Data{
+		Name: name,
+		MetaInfo: MetaInfo{
+			FreshnessPeriod: certificateFreshnessPeriod,
+		},
+		Content: buf.Bytes(),
+	}, nil
+}
+
+// ParseCertificateBundle decodes certificates from the content of a bundle.
+//
+// Data packets that are not certificates are skipped.
+func ParseCertificateBundle(d *Data) ([]*Data, error) {
+	var certs []*Data
+	r := tlv.NewReader(bytes.NewReader(d.Content))
+	for r.Peek() != 0 {
+		cert := new(Data)
+		err := cert.ReadFrom(r)
+		if err != nil {
+			return nil, err
+		}
+		if cert.MetaInfo.ContentType != ContentTypeKey {
+			continue
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
+
+// FetchCertificateBundle retrieves a certificate bundle with s, and adds
+// its certificates to c.
+//
+// Afterwards, FetchCertificate(s, c) answers lookups of the chain from c
+// instead of sending one interest for each certificate.
+func FetchCertificateBundle(s Sender, name Name, c Cache) ([]*Data, error) {
+	d, ok := <-s.SendInterest(&Interest{
+		Name: name,
+		Selectors: Selectors{
+			MustBeFresh: true,
+		},
+	})
+	if !ok {
+		return nil, ErrTimeout
+	}
+	certs, err := ParseCertificateBundle(d)
+	if err != nil {
+		return nil, err
+	}
+	for _, cert := range certs {
+		c.Add(cert)
+	}
+	return certs, nil
+}
+
+// CertificateChain collects the certificate chain of a key from PIB.
+//
+// The chain starts from the default certificate of the key, and follows
+// KeyLocator until a self-signed certificate or a certificate that is not in PIB.
+func (kc *KeyChain) CertificateChain(key Name) ([]*Data, error) {
+	cert, err := kc.Certificate(key)
+	if err != nil {
+		return nil, err
+	}
+	chain := []*Data{cert}
+	for {
+		locator := cert.SignatureInfo.KeyLocator.Name
+		if locator.Len() == 0 {
+			return chain, nil
+		}
+		for _, c := range chain {
+			if isPrefix(locator, c.Name) {
+				return chain, nil
+			}
+		}
+		cert, err = kc.Certificate(locator)
+		if err == ErrCertificateNotFound {
+			return chain, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, cert)
+	}
+}
+
+// CertificateMiddleware answers interests for certificates in PIB, so that
+// verifiers can retrieve the certificate chains of the producer without
+// a handler for them.
+//
+// The interest name must contain a key name, /<identity>/KEY/<key-id>, and
+// the default certificate of the key is preferred. Other interests are
+// passed to the handler.
+func (kc *KeyChain) CertificateMiddleware() Middleware {
+	return func(next InterestHandler) InterestHandler {
+		return InterestHandlerFunc(func(w Sender, i *Interest) {
+			if cert := kc.matchCertificate(i); cert != nil {
+				w.SendData(cert)
+				return
+			}
+			next.ServeInterest(w, i)
+		})
+	}
+}
+
+// matchCertificate returns a certificate in PIB that matches i, or nil.
+func (kc *KeyChain) matchCertificate(i *Interest) *Data {
+	var key Name
+	for k := i.Name.Len() - 2; k >= 0; k-- {
+		if string(i.Name.Components[k]) == keyComponent {
+			key = Name{Components: i.Name.Components[:k+2]}
+			break
+		}
+	}
+	if key.Len() == 0 {
+		return nil
+	}
+	if cert, err := kc.Certificate(key); err == nil && i.Match(cert) {
+		return cert
+	}
+	certs, err := kc.PIB.Certificates(key)
+	if err != nil {
+		return nil
+	}
+	for _, cert := range certs {
+		if i.Match(cert) {
+			return cert
+		}
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package ndn
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestCertificateBundle(t *testing.T) {
+	public, err := ecdsaKey.Public()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	cert, err := IssueCertificate(&Certificate{
+		Name:      ecdsaKey.Locator(),
+		IssuerID:  []byte("alice"),
+		Version:   1,
+		PublicKey: public,
+		NotBefore: now.Add(-time.Hour),
+		NotAfter:  now.Add(time.Hour),
+	}, rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kc := NewKeyChain(nil, nil)
+	err = kc.AddKey(rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = kc.addKey(ecdsaKey, cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, err := kc.CertificateChain(ecdsaKey.Locator())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chain) != 2 {
+		t.Fatalf("expect 2 certificates, got %d", len(chain))
+	}
+
+	name := CertificateBundleName(NewName("/hello"))
+	bundle, err := NewCertificateBundle(name, chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// only the bundle is served
+	consumer, producer := newPipe(func(i *Interest) *Data {
+		if isPrefix(i.Name, bundle.Name) {
+			return bundle
+		}
+		return nil
+	})
+	defer producer.Close()
+	defer consumer.Close()
+
+	c := NewCache(16)
+	certs, err := FetchCertificateBundle(consumer, name, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(certs) != 2 {
+		t.Fatalf("expect 2 certificates, got %d", len(certs))
+	}
+
+	v := NewValidatorConfig()
+	err = v.Load([]byte(`
+rule
+{
+  for data
+  checker
+  {
+    type customized
+    key-locator
+    {
+      type name
+      regex ^<>*$
+    }
+  }
+}
+`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = v.AddTrustAnchor(chain[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.Fetch = FetchCertificate(consumer, c)
+
+	d := &Data{Name: NewName("/hello/world")}
+	err = SignData(ecdsaKey, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = v.ValidateData(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCertificateMiddleware(t *testing.T) {
+	public, err := ecdsaKey.Public()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	cert, err := IssueCertificate(&Certificate{
+		Name:      ecdsaKey.Locator(),
+		IssuerID:  []byte("alice"),
+		Version:   1,
+		PublicKey: public,
+		NotBefore: now.Add(-time.Hour),
+		NotAfter:  now.Add(time.Hour),
+	}, rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kc := NewKeyChain(nil, nil)
+	err = kc.AddKey(rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = kc.addKey(ecdsaKey, cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := ChainHandler(InterestHandlerFunc(func(w Sender, i *Interest) {
+		w.SendData(&Data{Name: i.Name, Content: []byte("handler")})
+	}), kc.CertificateMiddleware())
+	c1, c2 := net.Pipe()
+	producer := NewFace(c2, WithInterestHandler(h))
+	defer producer.Close()
+	consumer := NewFace(c1)
+	defer consumer.Close()
+
+	// the chain is retrieved from the producer
+	fetch := FetchCertificate(consumer, nil)
+	d, err := fetch(ecdsaKey.Locator())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Name.Compare(cert.Name) != 0 {
+		t.Fatalf("expect %v, got %v", cert.Name, d.Name)
+	}
+	d, err = fetch(d.SignatureInfo.KeyLocator.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isPrefix(rsaKey.Locator(), d.Name) {
+		t.Fatalf("expect certificate of %v, got %v", rsaKey.Locator(), d.Name)
+	}
+	d, err = fetch(cert.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Name.Compare(cert.Name) != 0 {
+		t.Fatalf("expect %v, got %v", cert.Name, d.Name)
+	}
+
+	for _, name := range []string{
+		"/hello",
+		keyIdentity(ecdsaKey.Locator()).String() + "/KEY",
+		keyIdentity(ecdsaKey.Locator()).String() + "/KEY/unknown",
+	} {
+		d, err := SendInterestContext(context.Background(), consumer, &Interest{Name: NewName(name)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(d.Content) != "handler" {
+			t.Fatalf("%s: expect %v, got %s", name, "handler", d.Content)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package ndn
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrContentTooLarge is returned if decompressed content exceeds the maximum size.
+var ErrContentTooLarge = errors.New("decompressed content is too large")
+
+// maxDecompressedSize limits the content size after decompression.
+const maxDecompressedSize = 1 << 24
+
+// CompressOptions controls CompressData and CompressMiddleware.
+type CompressOptions struct {
+	// MinSize is the minimum content size to compress.
+	// If it is 0, 256 bytes is used.
+	MinSize int
+
+	// Level is the gzip compression level.
+	// If it is 0, gzip.DefaultCompression is used.
+	Level int
+
+	// OnError is called by CompressMiddleware with the data sent by the handler
+	// and the error, if the data cannot be compressed. The data is not sent.
+	OnError func(*Data, error)
+}
+
+// CompressData compresses the content of d with gzip, and sets CompressionType.
+//
+// Content that is already compressed or encrypted, smaller than opts.MinSize,
+// or not smaller after compression is unchanged.
+// It should be invoked before d is signed.
+func CompressData(d *Data, opts CompressOptions) error {
+	if opts.MinSize <= 0 {
+		opts.MinSize = 256
+	}
+	if opts.Level == 0 {
+		opts.Level = gzip.DefaultCompression
+	}
+	if d.MetaInfo.CompressionType != CompressionTypeNone ||
+		d.MetaInfo.EncryptionType != EncryptionTypeNone ||
+		len(d.Content) < opts.MinSize {
+		return nil
+	}
+	var buf bytes.Buffer
+	w, err := gzip.NewWriterLevel(&buf, opts.Level)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(d.Content)
+	if err != nil {
+		return err
+	}
+	err = w.Close()
+	if err != nil {
+		return err
+	}
+	if buf.Len() >= len(d.Content) {
+		return nil
+	}
+	d.Content = buf.Bytes()
+	d.MetaInfo.CompressionType = CompressionTypeGZIP
+	return nil
+}
+
+// DecompressData decompresses the content of d, and clears CompressionType.
+//
+// Only CompressionTypeGZIP is supported, and
+// ErrContentTooLarge is returned if the content exceeds 16 MiB after decompression.
+func DecompressData(d *Data) error {
+	switch d.MetaInfo.CompressionType {
+	case CompressionTypeNone:
+		return nil
+	case CompressionTypeGZIP:
+	default:
+		return ErrNotSupported
+	}
+	r, err := gzip.NewReader(bytes.NewReader(d.Content))
+	if err != nil {
+		return err
+	}
+	content, err := io.ReadAll(io.LimitReader(r, maxDecompressedSize+1))
+	if err != nil {
+		return err
+	}
+	if len(content) > maxDecompressedSize {
+		return ErrContentTooLarge
+	}
+	d.Content = content
+	d.MetaInfo.CompressionType = CompressionTypeNone
+	return nil
+}
+
+// CompressMiddleware compresses the content of data sent by handlers with CompressData.
+//
+// Every data packet is compressed separately, so it can be outside SegmentMiddleware.
+// It should be inside signing middleware, such as KeyChain.SignMiddleware,
+// so that compressed data is signed.
+func CompressMiddleware(opts CompressOptions) Middleware {
+	return func(next InterestHandler) InterestHandler {
+		return InterestHandlerFunc(func(w Sender, i *Interest) {
+			next.ServeInterest(&compressSender{Sender: w, opts: opts}, i)
+		})
+	}
+}
+
+// compressSender compresses data sent by a handler of CompressMiddleware.
+type compressSender struct {
+	Sender
+	opts CompressOptions
+}
+
+func (s *compressSender) SendData(d *Data) {
+	compressed := *d
+	err := CompressData(&compressed, s.opts)
+	if err != nil {
+		if s.opts.OnError != nil {
+			s.opts.OnError(d, err)
+		}
+		return
+	}
+	s.Sender.SendData(&compressed)
+}
+
+// NewDecompressingFace creates a face that delivers data packets with content
+// decompressed by DecompressData.
+//
+// Because the content is replaced, signatures must be validated before decompression,
+// for example, with NewValidatingFace inside the returned face.
+func NewDecompressingFace(f Face) Face {
+	return &decompressingFace{Face: f}
+}
+
+type decompressingFace struct {
+	Face
+}
+
+func (f *decompressingFace) SendInterest(i *Interest) <-chan *Data {
+	return f.SendInterestContext(context.Background(), i)
+}
+
+func (f *decompressingFace) SendInterestContext(ctx context.Context, i *Interest) <-chan *Data {
+	return f.ExpressInterest(ctx, i).ch
+}
+
+// ExpressInterest reports the decompression error in Response.Err.
+func (f *decompressingFace) ExpressInterest(ctx context.Context, i *Interest) *Response {
+	resp, ch := newResponse(ctx)
+	pending := ExpressInterest(ctx, f.Face, i)
+	go func() {
+		d := pending.Data()
+		if d == nil {
+			resp.err = pending.Err()
+		} else {
+			decompressed := *d
+			if err := DecompressData(&decompressed); err != nil {
+				resp.err = err
+			} else {
+				ch <- &decompressed
+			}
+		}
+		close(ch)
+	}()
+	return resp
+}
@@ -0,0 +1,90 @@
+package ndn
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+)
+
+func TestCompressMiddleware(t *testing.T) {
+	text := bytes.Repeat([]byte("text-heavy dataset "), 1000)
+	mux := NewMux()
+	mux.HandleFunc("/small", func(w Sender, i *Interest) {
+		w.SendData(&Data{Name: i.Name, Content: []byte("small")})
+	})
+	mux.HandleFunc("/text", func(w Sender, i *Interest) {
+		w.SendData(&Data{Name: i.Name, Content: text})
+	})
+	mux.Handle("/file", ChainHandler(InterestHandlerFunc(func(w Sender, i *Interest) {
+		w.SendData(&Data{Name: i.Name, MetaInfo: MetaInfo{FreshnessPeriod: 1000}, Content: text})
+	}), SegmentMiddleware(SegmentOptions{Size: 1000})))
+	mux.Use(CompressMiddleware(CompressOptions{}))
+
+	c1, c2 := net.Pipe()
+	producer := NewFace(c2, WithInterestHandler(mux))
+	defer producer.Close()
+	consumer := NewFace(c1)
+	defer consumer.Close()
+	f := NewDecompressingFace(consumer)
+
+	for _, test := range []struct {
+		name            string
+		want            []byte
+		compressionType uint64
+	}{
+		{"/small", []byte("small"), CompressionTypeNone},
+		{"/text", text, CompressionTypeGZIP},
+	} {
+		d, err := SendInterestContext(context.Background(), consumer, &Interest{Name: NewName(test.name)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.MetaInfo.CompressionType != test.compressionType {
+			t.Fatalf("%s: expect %v, got %v", test.name, test.compressionType, d.MetaInfo.CompressionType)
+		}
+		d, err = SendInterestContext(context.Background(), f, &Interest{Name: NewName(test.name)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(d.Content, test.want) || d.MetaInfo.CompressionType != CompressionTypeNone {
+			t.Fatalf("%s: expect %d bytes, got %d", test.name, len(test.want), len(d.Content))
+		}
+	}
+
+	// segments are compressed separately
+	got, err := Fetch(context.Background(), f, NewName("/file"), FetchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, text) {
+		t.Fatalf("expect %d bytes, got %d", len(text), len(got))
+	}
+}
+
+func TestCompressMiddlewareError(t *testing.T) {
+	failed := make(chan error, 1)
+	h := CompressMiddleware(CompressOptions{
+		// an invalid level
+		Level: 100,
+		OnError: func(d *Data, err error) {
+			failed <- err
+		},
+	})(InterestHandlerFunc(func(w Sender, i *Interest) {
+		w.SendData(&Data{Name: i.Name, Content: bytes.Repeat([]byte("text"), 1000)})
+	}))
+
+	c1, c2 := net.Pipe()
+	producer := NewFace(c2, WithInterestHandler(h))
+	defer producer.Close()
+	consumer := NewFace(c1)
+	defer consumer.Close()
+
+	consumer.SendInterest(&Interest{Name: NewName("/text")})
+	if err := <-failed; err == nil {
+		t.Fatal("expect error")
+	}
+	if n := producer.(StatsFace).Stats().OutData; n != 0 {
+		t.Fatalf("expect 0, got %v", n)
+	}
+}
@@ -0,0 +1,185 @@
+package ndn
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+// ErrInvalidConfig is returned if a configuration file cannot be parsed.
+var ErrInvalidConfig = errors.New("invalid config")
+
+// confNode is a node in boost property tree INFO format,
+// which is used by ndn-cxx configuration files.
+//
+//	key value ; comment
+//	key "quoted value"
+//	key
+//	{
+//	  child value
+//	}
+type confNode struct {
+	Key      string
+	Value    string
+	Children []*confNode
+}
+
+// Get returns the value of the first child with key.
+func (n *confNode) Get(key string) string {
+	child := n.Child(key)
+	if child == nil {
+		return ""
+	}
+	return child.Value
+}
+
+// Child returns the first child with key.
+func (n *confNode) Child(key string) *confNode {
+	for _, child := range n.Children {
+		if child.Key == key {
+			return child
+		}
+	}
+	return nil
+}
+
+// All returns all children with key.
+func (n *confNode) All(key string) (children []*confNode) {
+	for _, child := range n.Children {
+		if child.Key == key {
+			children = append(children, child)
+		}
+	}
+	return
+}
+
+type confToken struct {
+	s       string
+	quoted  bool
+	newline bool
+}
+
+func tokenizeConf(r io.Reader) ([]confToken, error) {
+	var tokens []confToken
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		for i := 0; i < len(line); {
+			switch c := line[i]; c {
+			case ' ', '\t', '\r':
+				i++
+			case ';':
+				i = len(line)
+			case '{', '}':
+				tokens = append(tokens, confToken{s: string(c)})
+				i++
+			case '"':
+				var s []byte
+				i++
+				for ; i < len(line) && line[i] != '"'; i++ {
+					if line[i] == '\\' && i+1 < len(line) {
+						i++
+						switch line[i] {
+						case 'n':
+							s = append(s, '\n')
+						case 't':
+							s = append(s, '\t')
+						default:
+							s = append(s, line[i])
+						}
+						continue
+					}
+					s = append(s, line[i])
+				}
+				if i == len(line) {
+					return nil, ErrInvalidConfig
+				}
+				i++
+				tokens = append(tokens, confToken{s: string(s), quoted: true})
+			default:
+				start := i
+				for i < len(line) && !strings.ContainsRune(" \t\r;{}\"", rune(line[i])) {
+					i++
+				}
+				tokens = append(tokens, confToken{s: line[start:i]})
+			}
+		}
+		tokens = append(tokens, confToken{newline: true})
+	}
+	err := scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// parseConf parses configuration in INFO format.
+func parseConf(r io.Reader) (*confNode, error) {
+	tokens, err := tokenizeConf(r)
+	if err != nil {
+		return nil, err
+	}
+	root := new(confNode)
+	rest, err := parseConfChildren(root, tokens, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 {
+		return nil, ErrInvalidConfig
+	}
+	return root, nil
+}
+
+func parseConfChildren(parent *confNode, tokens []confToken, nested bool) ([]confToken, error) {
+	for len(tokens) > 0 {
+		tok := tokens[0]
+		switch {
+		case tok.newline:
+			tokens = tokens[1:]
+			continue
+		case !tok.quoted && tok.s == "}":
+			if !nested {
+				return nil, ErrInvalidConfig
+			}
+			return tokens[1:], nil
+		case !tok.quoted && tok.s == "{":
+			return nil, ErrInvalidConfig
+		}
+		node := &confNode{Key: tok.s}
+		tokens = tokens[1:]
+		// optional value on the same line
+		if len(tokens) > 0 && !tokens[0].newline &&
+			(tokens[0].quoted || tokens[0].s != "{" && tokens[0].s != "}") {
+			node.Value = tokens[0].s
+			tokens = tokens[1:]
+		}
+		// optional children
+		next := tokens
+		for len(next) > 0 && next[0].newline {
+			next = next[1:]
+		}
+		if len(next) > 0 && !next[0].quoted && next[0].s == "{" {
+			var err error
+			tokens, err = parseConfChildren(node, next[1:], true)
+			if err != nil {
+				return nil, err
+			}
+		}
+		parent.Children = append(parent.Children, node)
+	}
+	if nested {
+		return nil, ErrInvalidConfig
+	}
+	return nil, nil
+}
+
+// parseConfDuration parses durations like "1h", "30m", "10s" or "1d".
+func parseConfDuration(s string) (time.Duration, error) {
+	if strings.HasSuffix(s, "d") {
+		d, err := time.ParseDuration(strings.TrimSuffix(s, "d") + "h")
+		return d * 24, err
+	}
+	return time.ParseDuration(s)
+}
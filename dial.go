@@ -0,0 +1,239 @@
+package ndn
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-ndn/tlv"
+)
+
+const (
+	// dialTimeout bounds connection establishment of Dial.
+	dialTimeout = 10 * time.Second
+	// happyEyeballsDelay is the delay before the next address is tried,
+	// as recommended by RFC 8305.
+	happyEyeballsDelay = 250 * time.Millisecond
+	// probeTimeout is how long a UDP address is waited for.
+	probeTimeout = 2 * time.Second
+)
+
+// probeName is the name of probe interests, which a hub answers
+// with a nack if it has no route.
+var probeName = NewName("/localhop/ndn-probe")
+
+// Dial connects to a face uri, and returns the face over the right transport:
+//
+//	unix:///run/nfd/nfd.sock	stream face over a unix socket
+//	tcp://hub.example.net:6363	stream face over TCP; tcp4 and tcp6 are also accepted
+//	udp://hub.example.net:6363	datagram face over UDP; udp4 and udp6 are also accepted
+//	ws://localhost:9696		datagram face over WebSocket; see DialWebSocket
+//	wss://hub.example.net/ws/	WebSocket over TLS
+//	quic://hub.example.net:6367	stream face over QUIC, verified with system roots
+//
+// If the port of a tcp or udp uri is missing, 6363 is used.
+// If a hostname has both IPv6 and IPv4 addresses, they are tried in parallel, and
+// the first one that works is used, because some hubs publish IPv6 addresses
+// that are unreachable. A UDP address works if it answers a probe interest
+// with data or a nack.
+//
+// opts are passed to NewFace or NewDatagramFace.
+func Dial(uri string, opts ...FaceOption) (Face, error) {
+	scheme, _, ok := strings.Cut(uri, "://")
+	if !ok {
+		return nil, ErrInvalidFaceURI
+	}
+	switch scheme {
+	case "unix", "tcp", "tcp4", "tcp6", "udp", "udp4", "udp6", "ws", "wss", "quic":
+	default:
+		return nil, ErrNotSupported
+	}
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, ErrInvalidFaceURI
+	}
+	switch scheme {
+	case "unix":
+		path := u.Path
+		if path == "" {
+			// unix://relative/path
+			path = u.Host + u.Path
+		}
+		if path == "" {
+			return nil, ErrInvalidFaceURI
+		}
+		conn, err := net.Dial("unix", path)
+		if err != nil {
+			return nil, err
+		}
+		return NewFace(conn, opts...), nil
+	case "tcp", "tcp4", "tcp6", "udp", "udp4", "udp6":
+		if u.Hostname() == "" || strings.Trim(u.Path, "/") != "" {
+			return nil, ErrInvalidFaceURI
+		}
+		if strings.HasPrefix(u.Scheme, "udp") {
+			conn, err := dialUDP(u.Scheme, u.Hostname(), websocketHost(u, "6363"))
+			if err != nil {
+				return nil, err
+			}
+			return NewDatagramFace(conn, opts...), nil
+		}
+		// net.Dialer tries IPv6 and IPv4 in parallel.
+		d := net.Dialer{Timeout: dialTimeout}
+		conn, err := d.Dial(u.Scheme, websocketHost(u, "6363"))
+		if err != nil {
+			return nil, err
+		}
+		return NewFace(conn, opts...), nil
+	case "ws", "wss":
+		return DialWebSocket(uri, opts...)
+	case "quic":
+		if u.Hostname() == "" || u.Port() == "" {
+			return nil, ErrInvalidFaceURI
+		}
+		return DialQUIC(u.Host, &tls.Config{ServerName: u.Hostname()}, opts...)
+	}
+	return nil, ErrNotSupported
+}
+
+// dialUDP connects to address, and probes every address of host if it has both
+// IPv6 and IPv4 addresses.
+func dialUDP(network, host, address string) (net.Conn, error) {
+	if net.ParseIP(host) != nil || network != "udp" {
+		return net.Dial(network, address)
+	}
+	_, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
+	defer cancel()
+	ips, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	var ip6, ip4 []string
+	for _, ip := range ips {
+		addr := net.JoinHostPort(ip.String(), port)
+		if ip.IP.To4() != nil {
+			ip4 = append(ip4, addr)
+		} else {
+			ip6 = append(ip6, addr)
+		}
+	}
+	if len(ip6) == 0 || len(ip4) == 0 {
+		return net.Dial(network, address)
+	}
+	conn, err := dialFirst(ctx, interleaveAddrs(ip6, ip4), probeUDP)
+	if err != nil {
+		// no address answers; IPv4 is used, because IPv6 is more likely to be unreachable.
+		return net.Dial("udp", ip4[0])
+	}
+	return conn, nil
+}
+
+// interleaveAddrs alternates between address families, starting with the first.
+func interleaveAddrs(a, b []string) []string {
+	var addrs []string
+	for i := 0; i < len(a) || i < len(b); i++ {
+		if i < len(a) {
+			addrs = append(addrs, a[i])
+		}
+		if i < len(b) {
+			addrs = append(addrs, b[i])
+		}
+	}
+	return addrs
+}
+
+// dialFirst dials addrs in order, and returns the first connection.
+//
+// The next address is tried after happyEyeballsDelay, or after the previous one fails.
+// Other connections are closed.
+func dialFirst(ctx context.Context, addrs []string, dial func(context.Context, string) (net.Conn, error)) (net.Conn, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	type result struct {
+		conn net.Conn
+		err  error
+	}
+	results := make(chan result, len(addrs))
+	var started int
+	start := func() {
+		addr := addrs[started]
+		started++
+		go func() {
+			conn, err := dial(ctx, addr)
+			results <- result{conn: conn, err: err}
+		}()
+	}
+	start()
+	timer := time.NewTimer(happyEyeballsDelay)
+	defer timer.Stop()
+	var firstErr error
+	for done := 0; done < len(addrs); {
+		select {
+		case <-timer.C:
+		case r := <-results:
+			done++
+			if r.err == nil {
+				// the losers are closed after they return.
+				go func(n int) {
+					for ; n > 0; n-- {
+						if r := <-results; r.conn != nil {
+							r.conn.Close()
+						}
+					}
+				}(started - done)
+				return r.conn, nil
+			}
+			if firstErr == nil {
+				firstErr = r.err
+			}
+		}
+		if started < len(addrs) {
+			start()
+			timer.Reset(happyEyeballsDelay)
+		}
+	}
+	return nil, firstErr
+}
+
+// probeUDP connects to addr, and waits until it answers a probe interest.
+func probeUDP(ctx context.Context, addr string) (net.Conn, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	buf := new(bytes.Buffer)
+	err = (&Interest{
+		Name: probeName,
+		Selectors: Selectors{
+			MustBeFresh: true,
+		},
+		LifeTime: uint64(probeTimeout / time.Millisecond),
+	}).WriteTo(tlv.NewWriter(buf))
+	if err == nil {
+		_, err = conn.Write(buf.Bytes())
+	}
+	if err == nil {
+		conn.SetReadDeadline(time.Now().Add(probeTimeout))
+		stop := context.AfterFunc(ctx, func() {
+			conn.SetReadDeadline(time.Now())
+		})
+		// any answer tells that addr is reachable.
+		_, err = conn.Read(make([]byte, maxPacketSize))
+		stop()
+		conn.SetReadDeadline(time.Time{})
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
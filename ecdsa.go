@@ -1,9 +1,10 @@
 package ndn
 
 import (
+	"crypto"
 	"crypto/ecdsa"
 	"crypto/rand"
-	"crypto/sha256"
+	_ "crypto/sha512" // register SHA-384 and SHA-512
 	"crypto/x509"
 	"encoding/asn1"
 	"math/big"
@@ -12,9 +13,28 @@ import (
 )
 
 // ECDSAKey implements Key.
+//
+// Hash is the digest algorithm, which is SHA-256 by default.
+// SHA-384 and SHA-512 are suggested for P-384 and P-521 keys,
+// but peers that use ndn-cxx only support SHA-256.
 type ECDSAKey struct {
 	Name
 	*ecdsa.PrivateKey
+	Hash crypto.Hash
+}
+
+// ecdsaHashes maps signature types to digest algorithms.
+var ecdsaHashes = map[uint64]crypto.Hash{
+	SignatureTypeSHA256WithECDSA: crypto.SHA256,
+	SignatureTypeSHA384WithECDSA: crypto.SHA384,
+	SignatureTypeSHA512WithECDSA: crypto.SHA512,
+}
+
+func (key *ECDSAKey) hash() crypto.Hash {
+	if key.Hash == 0 {
+		return crypto.SHA256
+	}
+	return key.Hash
 }
 
 // Locator returns public key locator.
@@ -34,6 +54,12 @@ func (key *ECDSAKey) Public() ([]byte, error) {
 
 // SignatureType returns signature type generated from the key.
 func (key *ECDSAKey) SignatureType() uint64 {
+	h := key.hash()
+	for sigType, sigHash := range ecdsaHashes {
+		if sigHash == h {
+			return sigType
+		}
+	}
 	return SignatureTypeSHA256WithECDSA
 }
 
@@ -43,7 +69,10 @@ type ecdsaSignature struct {
 
 // Sign creates signature.
 func (key *ECDSAKey) Sign(v interface{}) ([]byte, error) {
-	digest, err := tlv.Hash(sha256.New, v)
+	if ecdsaHashes[key.SignatureType()] != key.hash() {
+		return nil, ErrNotSupported
+	}
+	digest, err := tlv.Hash(key.hash().New, v)
 	if err != nil {
 		return nil, err
 	}
@@ -57,7 +86,7 @@ func (key *ECDSAKey) Sign(v interface{}) ([]byte, error) {
 
 // Verify checks signature.
 func (key *ECDSAKey) Verify(v interface{}, signature []byte) error {
-	digest, err := tlv.Hash(sha256.New, v)
+	digest, err := tlv.Hash(key.hash().New, v)
 	if err != nil {
 		return err
 	}
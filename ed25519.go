@@ -0,0 +1,99 @@
+package ndn
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/x509"
+	"hash"
+
+	"github.com/go-ndn/tlv"
+)
+
+// Ed25519Key implements Key.
+//
+// If PrivateKey is nil, the key can only verify.
+type Ed25519Key struct {
+	Name
+	PrivateKey ed25519.PrivateKey
+	PublicKey  ed25519.PublicKey
+}
+
+// Locator returns public key locator.
+func (key *Ed25519Key) Locator() Name {
+	return key.Name
+}
+
+// Private encodes private key.
+func (key *Ed25519Key) Private() ([]byte, error) {
+	if key.PrivateKey == nil {
+		return nil, ErrNotSupported
+	}
+	return x509.MarshalPKCS8PrivateKey(key.PrivateKey)
+}
+
+func (key *Ed25519Key) public() ed25519.PublicKey {
+	if key.PublicKey == nil && key.PrivateKey != nil {
+		return key.PrivateKey.Public().(ed25519.PublicKey)
+	}
+	return key.PublicKey
+}
+
+// Public encodes public key.
+func (key *Ed25519Key) Public() ([]byte, error) {
+	return x509.MarshalPKIXPublicKey(key.public())
+}
+
+// SignatureType returns signature type generated from the key.
+func (key *Ed25519Key) SignatureType() uint64 {
+	return SignatureTypeEd25519
+}
+
+// messageHash is a hash.Hash that returns the message itself.
+//
+// Ed25519 signs the whole message instead of its digest.
+type messageHash struct {
+	bytes.Buffer
+}
+
+func (h *messageHash) Sum(b []byte) []byte {
+	return append(b, h.Bytes()...)
+}
+
+func (h *messageHash) Size() int {
+	return h.Len()
+}
+
+func (h *messageHash) BlockSize() int {
+	return 1
+}
+
+// signedPortion returns the encoded bytes that are covered by signature.
+func signedPortion(v interface{}) ([]byte, error) {
+	return tlv.Hash(func() hash.Hash {
+		return new(messageHash)
+	}, v)
+}
+
+// Sign creates signature.
+func (key *Ed25519Key) Sign(v interface{}) ([]byte, error) {
+	if key.PrivateKey == nil {
+		return nil, ErrNotSupported
+	}
+	msg, err := signedPortion(v)
+	if err != nil {
+		return nil, err
+	}
+	return ed25519.Sign(key.PrivateKey, msg), nil
+}
+
+// Verify checks signature.
+func (key *Ed25519Key) Verify(v interface{}, signature []byte) error {
+	msg, err := signedPortion(v)
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(key.public(), msg, signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
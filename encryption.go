@@ -0,0 +1,219 @@
+package ndn
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+
+	"github.com/go-ndn/tlv"
+)
+
+// ErrDecryptionFailed is returned if ciphertext cannot be decrypted.
+var ErrDecryptionFailed = errors.New("decryption failed")
+
+// EncryptedContent is the content of encrypted data packets.
+//
+// Payload is the ciphertext, and IV is the initialization vector.
+// PayloadKey is the encrypted key that decrypts Payload, or
+// KeyName is the name of this key.
+type EncryptedContent struct {
+	Payload    []byte `tlv:"132"`
+	IV         []byte `tlv:"133?"`
+	PayloadKey []byte `tlv:"134?"`
+	KeyName    Name   `tlv:"7?"`
+}
+
+// WriteTo implements tlv.WriteTo.
+func (ec *EncryptedContent) WriteTo(w tlv.Writer) error {
+	return w.Write(ec, 130)
+}
+
+// ReadFrom implements tlv.ReadFrom.
+func (ec *EncryptedContent) ReadFrom(r tlv.Reader) error {
+	return r.Read(ec, 130)
+}
+
+func marshalEncryptedContent(ec *EncryptedContent) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	err := ec.WriteTo(tlv.NewWriter(buf))
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func unmarshalEncryptedContent(b []byte) (*EncryptedContent, error) {
+	ec := new(EncryptedContent)
+	err := ec.ReadFrom(tlv.NewReader(bytes.NewReader(b)))
+	if err != nil {
+		return nil, err
+	}
+	return ec, nil
+}
+
+// EncryptCBC encrypts plaintext with AES-CBC and PKCS#7 padding.
+//
+// A random IV is generated for every invocation.
+// The key must be 16, 24 or 32 bytes.
+func EncryptCBC(key, plaintext []byte) (*EncryptedContent, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	iv := make([]byte, aes.BlockSize)
+	_, err = rand.Read(iv)
+	if err != nil {
+		return nil, err
+	}
+	pad := aes.BlockSize - len(plaintext)%aes.BlockSize
+	ciphertext := make([]byte, len(plaintext)+pad)
+	copy(ciphertext, plaintext)
+	for i := len(plaintext); i < len(ciphertext); i++ {
+		ciphertext[i] = byte(pad)
+	}
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, ciphertext)
+	return &EncryptedContent{
+		Payload: ciphertext,
+		IV:      iv,
+	}, nil
+}
+
+// DecryptCBC decrypts content encrypted by EncryptCBC.
+func DecryptCBC(key []byte, ec *EncryptedContent) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ec.IV) != aes.BlockSize || len(ec.Payload) == 0 || len(ec.Payload)%aes.BlockSize != 0 {
+		return nil, ErrDecryptionFailed
+	}
+	plaintext := make([]byte, len(ec.Payload))
+	cipher.NewCBCDecrypter(block, ec.IV).CryptBlocks(plaintext, ec.Payload)
+	pad := int(plaintext[len(plaintext)-1])
+	if pad == 0 || pad > aes.BlockSize {
+		return nil, ErrDecryptionFailed
+	}
+	for _, b := range plaintext[len(plaintext)-pad:] {
+		if int(b) != pad {
+			return nil, ErrDecryptionFailed
+		}
+	}
+	return plaintext[:len(plaintext)-pad], nil
+}
+
+// EncryptGCM encrypts plaintext with AES-GCM.
+//
+// A random 12-byte IV is generated for every invocation, and
+// the authentication tag is appended to Payload.
+// additionalData is authenticated but not encrypted, and
+// the same additionalData must be given to DecryptGCM.
+func EncryptGCM(key, plaintext, additionalData []byte) (*EncryptedContent, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	iv := make([]byte, aead.NonceSize())
+	_, err = rand.Read(iv)
+	if err != nil {
+		return nil, err
+	}
+	return &EncryptedContent{
+		Payload: aead.Seal(nil, iv, plaintext, additionalData),
+		IV:      iv,
+	}, nil
+}
+
+// DecryptGCM decrypts content encrypted by EncryptGCM.
+func DecryptGCM(key []byte, ec *EncryptedContent, additionalData []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ec.IV) != aead.NonceSize() {
+		return nil, ErrDecryptionFailed
+	}
+	plaintext, err := aead.Open(nil, ec.IV, ec.Payload, additionalData)
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// keyWrapIV is the default initial value of AES key wrap.
+var keyWrapIV = []byte{0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6}
+
+// WrapKey encrypts key with kek in AES key wrap.
+//
+// The key must be a multiple of 8 bytes, and at least 16 bytes.
+//
+// See https://tools.ietf.org/html/rfc3394.
+func WrapKey(kek, key []byte) ([]byte, error) {
+	if len(key) < 16 || len(key)%8 != 0 {
+		return nil, ErrNotSupported
+	}
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+	n := len(key) / 8
+	out := make([]byte, 8+len(key))
+	a := out[:8]
+	copy(a, keyWrapIV)
+	copy(out[8:], key)
+	b := make([]byte, aes.BlockSize)
+	for j := 0; j < 6; j++ {
+		for i := 1; i <= n; i++ {
+			r := out[i*8 : i*8+8]
+			copy(b, a)
+			copy(b[8:], r)
+			block.Encrypt(b, b)
+			t := uint64(n*j + i)
+			binary.BigEndian.PutUint64(a, binary.BigEndian.Uint64(b[:8])^t)
+			copy(r, b[8:])
+		}
+	}
+	return out, nil
+}
+
+// UnwrapKey decrypts a key wrapped by WrapKey.
+func UnwrapKey(kek, wrapped []byte) ([]byte, error) {
+	if len(wrapped) < 24 || len(wrapped)%8 != 0 {
+		return nil, ErrDecryptionFailed
+	}
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+	n := len(wrapped)/8 - 1
+	out := make([]byte, len(wrapped))
+	copy(out, wrapped)
+	a := out[:8]
+	b := make([]byte, aes.BlockSize)
+	for j := 5; j >= 0; j-- {
+		for i := n; i >= 1; i-- {
+			r := out[i*8 : i*8+8]
+			t := uint64(n*j + i)
+			binary.BigEndian.PutUint64(b, binary.BigEndian.Uint64(a)^t)
+			copy(b[8:], r)
+			block.Decrypt(b, b)
+			copy(a, b[:8])
+			copy(r, b[8:])
+		}
+	}
+	if subtle.ConstantTimeCompare(a, keyWrapIV) != 1 {
+		return nil, ErrDecryptionFailed
+	}
+	return out[8:], nil
+}
@@ -0,0 +1,86 @@
+package ndn
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestEncryption(t *testing.T) {
+	key := make([]byte, 16)
+	plaintext := []byte("named-data networking")
+
+	for _, test := range []struct {
+		encrypt func([]byte) (*EncryptedContent, error)
+		decrypt func(*EncryptedContent) ([]byte, error)
+	}{
+		{
+			encrypt: func(b []byte) (*EncryptedContent, error) {
+				return EncryptCBC(key, b)
+			},
+			decrypt: func(ec *EncryptedContent) ([]byte, error) {
+				return DecryptCBC(key, ec)
+			},
+		},
+		{
+			encrypt: func(b []byte) (*EncryptedContent, error) {
+				return EncryptGCM(key, b, []byte("ad"))
+			},
+			decrypt: func(ec *EncryptedContent) ([]byte, error) {
+				return DecryptGCM(key, ec, []byte("ad"))
+			},
+		},
+	} {
+		ec, err := test.encrypt(plaintext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := marshalEncryptedContent(ec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ec, err = unmarshalEncryptedContent(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err = test.decrypt(ec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(b, plaintext) {
+			t.Fatalf("expect %s, got %s", plaintext, b)
+		}
+		ec.Payload[0] ^= 1
+		b, err = test.decrypt(ec)
+		if err == nil && bytes.Equal(b, plaintext) {
+			t.Fatal("modified payload should not decrypt to plaintext")
+		}
+	}
+}
+
+func TestWrapKey(t *testing.T) {
+	// RFC 3394 section 4.1
+	kek, _ := hex.DecodeString("000102030405060708090A0B0C0D0E0F")
+	key, _ := hex.DecodeString("00112233445566778899AABBCCDDEEFF")
+	want, _ := hex.DecodeString("1FA68B0A8112B447AEF34BD8FB5A7B829D3E862371D2CFE5")
+
+	wrapped, err := WrapKey(kek, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(wrapped, want) {
+		t.Fatalf("expect %x, got %x", want, wrapped)
+	}
+	unwrapped, err := UnwrapKey(kek, wrapped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(unwrapped, key) {
+		t.Fatalf("expect %x, got %x", key, unwrapped)
+	}
+	wrapped[0] ^= 1
+	_, err = UnwrapKey(kek, wrapped)
+	if err != ErrDecryptionFailed {
+		t.Fatalf("expect %v, got %v", ErrDecryptionFailed, err)
+	}
+}
@@ -0,0 +1,182 @@
+package ndn
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strconv"
+)
+
+// newPipe connects a consumer face to a producer face with an in-memory link.
+//
+// Every incoming interest on the producer side is answered by handler.
+// If handler returns nil, the interest is ignored.
+func newPipe(handler func(*Interest) *Data) (consumer, producer Face) {
+	c, p := net.Pipe()
+	recv := make(chan *Interest)
+	producer = NewFace(p, WithInterestQueue(recv))
+	consumer = NewFace(c)
+	go func() {
+		for i := range recv {
+			d := handler(i)
+			if d == nil {
+				continue
+			}
+			producer.SendData(d)
+		}
+	}()
+	return
+}
+
+func Example_sequenceFetch() {
+	// every message is published under a sequence number, and
+	// fetched in order until the next one is not found
+	messages := []string{"hello", "how are you?", "bye"}
+	consumer, producer := newPipe(func(i *Interest) *Data {
+		seq, err := strconv.Atoi(i.Name.Components[i.Name.Len()-1].String())
+		if err != nil || seq >= len(messages) {
+			return nil
+		}
+		return &Data{
+			Name:    i.Name,
+			Content: []byte(messages[seq]),
+		}
+	})
+	defer producer.Close()
+	defer consumer.Close()
+
+	for seq := 0; ; seq++ {
+		d, ok := <-consumer.SendInterest(&Interest{
+			Name:     NewName(fmt.Sprintf("/alice/msg/%d", seq)),
+			LifeTime: 100,
+		})
+		if !ok {
+			break
+		}
+		fmt.Printf("alice: %s\n", d.Content)
+	}
+	// Output:
+	// alice: hello
+	// alice: how are you?
+	// alice: bye
+}
+
+func Example_fileTransfer() {
+	const segmentSize = 4
+	file := []byte("named-data networking")
+	final := (len(file) - 1) / segmentSize
+	consumer, producer := newPipe(func(i *Interest) *Data {
+		seg, err := strconv.Atoi(i.Name.Components[i.Name.Len()-1].String())
+		if err != nil || seg > final {
+			return nil
+		}
+		end := (seg + 1) * segmentSize
+		if end > len(file) {
+			end = len(file)
+		}
+		d := &Data{
+			Name:    i.Name,
+			Content: file[seg*segmentSize : end],
+		}
+		d.MetaInfo.FinalBlockID.Component = []byte(strconv.Itoa(final))
+		return d
+	})
+	defer producer.Close()
+	defer consumer.Close()
+
+	buf := new(bytes.Buffer)
+	for seg := 0; ; seg++ {
+		d, ok := <-consumer.SendInterest(&Interest{
+			Name: NewName(fmt.Sprintf("/file/readme/%d", seg)),
+		})
+		if !ok {
+			fmt.Println("timeout")
+			return
+		}
+		buf.Write(d.Content)
+		if d.MetaInfo.FinalBlockID.Component.String() == strconv.Itoa(seg) {
+			break
+		}
+	}
+	fmt.Println(buf.String())
+	// Output:
+	// named-data networking
+}
+
+func Example_sensorTelemetry() {
+	readings := []int{21, 22, 24}
+	consumer, producer := newPipe(func(i *Interest) *Data {
+		seq, err := strconv.Atoi(i.Name.Components[i.Name.Len()-1].String())
+		if err != nil || seq >= len(readings) {
+			return nil
+		}
+		return &Data{
+			Name: i.Name,
+			MetaInfo: MetaInfo{
+				FreshnessPeriod: 1000,
+			},
+			Content: []byte(strconv.Itoa(readings[seq])),
+			SignatureInfo: SignatureInfo{
+				SignatureType: SignatureTypeDigestCRC32C,
+			},
+		}
+	})
+	defer producer.Close()
+	defer consumer.Close()
+
+	for seq := range readings {
+		d, ok := <-consumer.SendInterest(&Interest{
+			Name: NewName(fmt.Sprintf("/sensor/temperature/%d", seq)),
+		})
+		if !ok {
+			fmt.Println("timeout")
+			return
+		}
+		fmt.Printf("%s: %s\n", d.Name, d.Content)
+	}
+	// Output:
+	// /sensor/temperature/0: 21
+	// /sensor/temperature/1: 22
+	// /sensor/temperature/2: 24
+}
+
+func Example_certificateIssuance() {
+	// the issuer (rsaKey) signs the public key of the requester (ecdsaKey)
+	consumer, producer := newPipe(func(i *Interest) *Data {
+		if i.Name.Compare(ecdsaKey.Locator()) != 0 {
+			return nil
+		}
+		d, err := CertificateToData(ecdsaKey)
+		if err != nil {
+			return nil
+		}
+		err = SignData(rsaKey, d)
+		if err != nil {
+			return nil
+		}
+		return d
+	})
+	defer producer.Close()
+	defer consumer.Close()
+
+	d, ok := <-consumer.SendInterest(&Interest{
+		Name: ecdsaKey.Locator(),
+	})
+	if !ok {
+		fmt.Println("timeout")
+		return
+	}
+	err := VerifyData(rsaKey, d)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	key, err := CertificateFromData(d)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(key.Locator())
+	// Output:
+	// /ndn/guest/alice/1434508996774/KEY/%00%00
+}
@@ -0,0 +1,67 @@
+package ndn
+
+import (
+	"sync"
+	"time"
+)
+
+// expiryCache is a thread-safe map whose entries expire.
+type expiryCache struct {
+	entries map[string]expiryEntry
+	size    int
+	sync.Mutex
+}
+
+type expiryEntry struct {
+	value  interface{}
+	expire time.Time
+}
+
+func newExpiryCache(size int) *expiryCache {
+	return &expiryCache{
+		entries: make(map[string]expiryEntry),
+		size:    size,
+	}
+}
+
+// Get returns the value if it has not expired.
+func (c *expiryCache) Get(key string) (interface{}, bool) {
+	c.Lock()
+	defer c.Unlock()
+	ent, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(ent.expire) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return ent.value, true
+}
+
+// Add adds a value that expires after ttl.
+//
+// If the cache is full, expired entries are removed first,
+// and then an arbitrary entry is removed.
+func (c *expiryCache) Add(key string, value interface{}, ttl time.Duration) {
+	c.Lock()
+	defer c.Unlock()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.size {
+		now := time.Now()
+		for k, ent := range c.entries {
+			if now.After(ent.expire) {
+				delete(c.entries, k)
+			}
+		}
+		for k := range c.entries {
+			if len(c.entries) < c.size {
+				break
+			}
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = expiryEntry{
+		value:  value,
+		expire: time.Now().Add(ttl),
+	}
+}
@@ -1,15 +1,46 @@
 package ndn
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
 	"net"
 	"reflect"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-ndn/lpm"
 	"github.com/go-ndn/tlv"
 )
 
+// Errors introduced by Face.
+var (
+	ErrFaceClosed     = errors.New("face closed")
+	ErrPrefixListened = errors.New("prefix already listened")
+)
+
+// PacketError is the error of a malformed or unsupported packet.
+//
+// A stream face stops after a PacketError, because the next packet cannot be found.
+type PacketError struct {
+	Type uint64
+	Err  error
+}
+
+func (e *PacketError) Error() string {
+	return fmt.Sprintf("packet type %d: %v", e.Type, e.Err)
+}
+
+func (e *PacketError) Unwrap() error {
+	return e.Err
+}
+
 // Sender sends interest and data packets.
 // This is the minimum abstraction for NDN nodes.
 type Sender interface {
@@ -17,7 +48,46 @@ type Sender interface {
 	SendData(*Data)
 }
 
+// ContextSender is a Sender that can cancel pending interests with context.
+type ContextSender interface {
+	Sender
+	// SendInterestContext is like SendInterest, but the returned channel is
+	// also closed when ctx is done, before InterestLifetime.
+	SendInterestContext(ctx context.Context, i *Interest) <-chan *Data
+}
+
+// NackSender is a Sender that can reject interests with network nacks,
+// such as faces created by NewFace.
+type NackSender interface {
+	Sender
+	// SendNack rejects i with reason, like NackReasonCongestion.
+	SendNack(i *Interest, reason uint64)
+}
+
+// sendInterest uses SendInterestContext if s implements ContextSender.
+func sendInterest(ctx context.Context, s Sender, i *Interest) <-chan *Data {
+	if cs, ok := s.(ContextSender); ok {
+		return cs.SendInterestContext(ctx, i)
+	}
+	return s.SendInterest(i)
+}
+
+// SendInterestContext sends i with s, and waits for data until ctx is done.
+//
+// The error is the same as Response.Err.
+// If s implements ContextSender, the pending interest is also removed when ctx is done.
+func SendInterestContext(ctx context.Context, s Sender, i *Interest) (*Data, error) {
+	r := ExpressInterest(ctx, s, i)
+	return r.Data(), r.Err()
+}
+
 // Face implements Sender.
+//
+// Faces created by this package are safe for concurrent use.
+// Every packet is written to the transport in one piece, so concurrent
+// senders never interleave their packets.
+// A packet must not be modified while it is being sent; SendInterest
+// populates the nonce of the interest, and SendData may populate the signature of the data.
 type Face interface {
 	Sender
 	LocalAddr() net.Addr
@@ -25,144 +95,668 @@ type Face interface {
 	Close() error
 }
 
+// DoneFace is a face that tells why it stops, such as faces created by NewFace.
+type DoneFace interface {
+	Face
+	// Done is closed after the face stops, and its background goroutines exit.
+	Done() <-chan struct{}
+	// Err returns nil if Done is not yet closed.
+	// Otherwise, it returns ErrFaceClosed if the face is closed by Close,
+	// io.EOF if the peer closes the transport, *PacketError if a stream face
+	// receives a malformed packet, ErrTimeout if KeepAlive detects a dead peer,
+	// or the error of the transport.
+	Err() error
+}
+
+// ContextCloser is a face that can stop waiting for its background goroutines
+// when ctx is done, such as faces created by NewFace.
+type ContextCloser interface {
+	Face
+	CloseWithContext(ctx context.Context) error
+}
+
 type face struct {
 	net.Conn
 	tlv.Reader // read
 
-	tlv.Writer            // write
-	wm         sync.Mutex // writer mutex
+	wm sync.Mutex // writer mutex
+
+	pit    *pit
+	expiry *pitExpiry
+	// closed is set before the pit is drained; new interests are rejected.
+	closed atomic.Bool
+	cm     sync.Mutex // orders closed with wg.Add
+	// wg tracks background goroutines other than the read loop.
+	wg sync.WaitGroup
+
+	recv    chan<- *Interest
+	handler InterestHandler
+	// handlerSem limits running handlers if it is not nil.
+	handlerSem  chan struct{}
+	maxHandlers int
+	// queue is not nil if the overflow policy drops interests.
+	queue     *interestQueue
+	queueOpts InterestQueueOptions
+	// dedup is not nil if duplicate interests are suppressed.
+	dedup *dedupTable
+	// negative is not nil if failed outgoing interests are cached.
+	negative *negativeCache
+
+	handlers handlerMatcher // handlers of Listen
+	hm       sync.Mutex     // handler mutex
 
-	pitMatcher            // pit
-	pitm       sync.Mutex // pit mutex
+	// datagram is true if every packet is sent and received in one datagram.
+	datagram bool
 
-	recv chan<- *Interest
+	stats faceCounters
+
+	// closing is closed when the face starts closing.
+	closing   chan struct{}
+	closeOnce sync.Once
+	// err is why the face stops, and it is set before closing is closed.
+	err error
+	// done is closed after all background goroutines exit.
+	done chan struct{}
+
+	logger         *log.Logger
+	readBufferSize int
+	mtu            int
+	cache          Cache
+	key            Signer
+	keepAliveOpts  *KeepAliveOptions
 }
 
+func (f *face) logf(format string, v ...interface{}) {
+	if f.logger != nil {
+		f.logger.Printf(format, v...)
+	}
+}
+
+// maxPacketSize is the maximum size of NDN packets.
+const maxPacketSize = 8800
+
+// defaultMaxHandlers is the number of interest handlers that run at the same
+// time if WithMaxHandlers is not set.
+const defaultMaxHandlers = 256
+
 type pitEntry struct {
 	*Selectors
-	timer *time.Timer
+	// digest is the implicit digest of the interest name.
+	digest lpm.Component
+	timer  *pitTimer
+	// stop unregisters the callback on context cancellation.
+	stop  func() bool
+	resp  *Response
+	nonce uint64
 }
 
 // NewFace creates a face from net.Conn.
 //
-// recv is the incoming interest queue.
-// If it is nil, incoming interests will be ignored.
-// Otherwise, this queue must be handled before it is full.
-func NewFace(transport net.Conn, recv chan<- *Interest) Face {
-	f := &face{
-		Conn:   transport,
-		Reader: tlv.NewReader(transport),
-		Writer: tlv.NewWriter(transport),
-		recv:   recv,
+// Without options, incoming interests are ignored; see WithInterestQueue and WithInterestHandler.
+func NewFace(transport net.Conn, opts ...FaceOption) Face {
+	f := newFace(transport, opts)
+	var r io.Reader = transport
+	if f.readBufferSize > 0 {
+		r = bufio.NewReaderSize(transport, f.readBufferSize)
 	}
+	f.Reader = tlv.NewReader(countingReader{Reader: r, n: &f.stats.inBytes})
 	go func() {
 		for {
-			switch f.Peek() {
-			case 5:
-				i := new(Interest)
-				err := i.ReadFrom(f.Reader)
-				if err != nil {
-					goto IDLE
-				}
-				f.recvInterest(i)
-			case 6:
-				d := new(Data)
-				err := d.ReadFrom(f.Reader)
-				if err != nil {
-					goto IDLE
-				}
-				f.recvData(d)
-			default:
-				goto IDLE
+			err := f.readPacket(f.Reader, nil)
+			if err != nil {
+				f.stop(err)
+				return
 			}
 		}
-	IDLE:
-		if f.recv != nil {
-			close(f.recv)
+	}()
+	f.start()
+	return f
+}
+
+// NewDatagramFace creates a face from datagram-oriented net.Conn, such as
+// a connected UDP socket to a testbed hub.
+//
+// Every packet is sent in one datagram, and every datagram carries one packet,
+// so a malformed datagram is dropped without affecting later packets.
+// opts have the same meaning as NewFace.
+func NewDatagramFace(transport net.Conn, opts ...FaceOption) Face {
+	f := newFace(transport, opts)
+	f.datagram = true
+	go func() {
+		buf := make([]byte, f.mtu)
+		for {
+			n, err := f.Conn.Read(buf)
+			if err != nil {
+				f.stop(err)
+				return
+			}
+			f.stats.inBytes.Add(uint64(n))
+			b := make([]byte, n)
+			copy(b, buf)
+			f.readPacket(tlv.NewReader(bytes.NewReader(b)), nil)
 		}
 	}()
+	f.start()
 	return f
 }
 
+func newFace(transport net.Conn, opts []FaceOption) *face {
+	f := &face{
+		Conn:    transport,
+		pit:     newPIT(),
+		expiry:  newPITExpiry(),
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	if f.mtu <= 0 {
+		f.mtu = maxPacketSize
+	}
+	if f.maxHandlers == 0 {
+		f.maxHandlers = defaultMaxHandlers
+	}
+	if f.maxHandlers > 0 {
+		f.handlerSem = make(chan struct{}, f.maxHandlers)
+	}
+	f.goroutine(f.expirePendingInterests)
+	if f.recv != nil && f.queueOpts.Policy != OverflowBlock {
+		f.queue = newInterestQueue(f.queueOpts)
+		f.goroutine(f.deliverInterests)
+	}
+	return f
+}
+
+// start starts optional tasks after the read loop.
+func (f *face) start() {
+	if f.keepAliveOpts != nil {
+		KeepAlive(f, *f.keepAliveOpts)
+	}
+}
+
+// stop is called when the read loop exits with err.
+func (f *face) stop(err error) {
+	f.shutdown(err)
+	f.wg.Wait()
+	// the queue is closed after deliverInterests exits.
+	if f.recv != nil {
+		close(f.recv)
+	}
+	close(f.done)
+}
+
+// goroutine starts fn in a background goroutine, which must return
+// after closing is closed.
+func (f *face) goroutine(fn func()) error {
+	f.cm.Lock()
+	defer f.cm.Unlock()
+	if f.closed.Load() {
+		return ErrFaceClosed
+	}
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+		fn()
+	}()
+	return nil
+}
+
+// shutdown closes the transport, and rejects pending interests with ErrFaceClosed.
+// cause is reported by Err if the face is not yet closed.
+//
+// Unlike Close, it does not wait, so it can be called by background goroutines.
+func (f *face) shutdown(cause error) (err error) {
+	f.closeOnce.Do(func() {
+		f.err = cause
+		close(f.closing)
+		err = f.Conn.Close()
+
+		f.cm.Lock()
+		f.closed.Store(true)
+		f.cm.Unlock()
+		f.pit.visit(func(m map[chan<- *Data]pitEntry) map[chan<- *Data]pitEntry {
+			for ch, e := range m {
+				e.resp.err = ErrFaceClosed
+				close(ch)
+				f.expiry.remove(e.timer)
+				e.stop()
+			}
+			return nil
+		})
+	})
+	return
+}
+
+// Close closes the transport, and rejects pending interests with ErrFaceClosed.
+// It returns after all background goroutines exit, and
+// the incoming interest queue is closed.
+func (f *face) Close() error {
+	return f.CloseWithContext(context.Background())
+}
+
+// CloseWithContext is like Close, but returns ctx.Err() if ctx is done
+// before background goroutines exit.
+func (f *face) CloseWithContext(ctx context.Context) error {
+	err := f.shutdown(ErrFaceClosed)
+	select {
+	case <-f.done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (f *face) Done() <-chan struct{} {
+	return f.done
+}
+
+func (f *face) Err() error {
+	select {
+	case <-f.done:
+		return f.err
+	default:
+		return nil
+	}
+}
+
+// readPacket reads one interest or data packet from r.
+//
+// lp is the NDNLPv2 packet that carries r, or nil.
+func (f *face) readPacket(r tlv.Reader, lp *lpPacket) error {
+	t := r.Peek()
+	if t == 0 {
+		// end of stream
+		return io.EOF
+	}
+	err := f.decodePacket(r, t, lp)
+	if err != nil {
+		f.stats.decodeErrors.Add(1)
+		f.logf("ndn: drop packet type %d from %v: %v", t, f.RemoteAddr(), err)
+		return &PacketError{Type: t, Err: err}
+	}
+	return nil
+}
+
+func (f *face) decodePacket(r tlv.Reader, t uint64, lp *lpPacket) error {
+	switch t {
+	case 5:
+		i := new(Interest)
+		err := i.ReadFrom(r)
+		if err != nil {
+			return err
+		}
+		f.stats.inInterests.Add(1)
+		f.recvInterest(i)
+	case 6:
+		d := new(Data)
+		err := d.ReadFrom(r)
+		if err != nil {
+			return err
+		}
+		f.stats.inData.Add(1)
+		f.recvData(d, lp)
+	case 100:
+		p := new(lpPacket)
+		err := r.Read(p, 100)
+		if err != nil {
+			return err
+		}
+		f.recvLpPacket(p)
+	default:
+		return ErrNotSupported
+	}
+	return nil
+}
+
 func (f *face) SendData(d *Data) {
+	if f.key != nil && len(d.SignatureValue) == 0 {
+		err := SignData(f.key, d)
+		if err != nil {
+			f.logf("ndn: drop data %v: %v", d.Name, err)
+			return
+		}
+	}
+	if f.cache != nil {
+		f.cache.Add(d)
+	}
+	f.writeData(d)
+}
+
+func (f *face) writeData(d *Data) {
+	bufs, err := d.Buffers()
+	if err != nil {
+		return
+	}
+	err = f.writePacket(bufs)
+	if err != nil {
+		f.logf("ndn: drop data %v: %v", d.Name, err)
+		return
+	}
+	f.stats.outData.Add(1)
+}
+
+func (f *face) writeInterest(i *Interest) {
+	buf := new(bytes.Buffer)
+	err := i.WriteTo(tlv.NewWriter(buf))
+	if err != nil {
+		return
+	}
+	err = f.writePacket(net.Buffers{buf.Bytes()})
+	if err != nil {
+		f.logf("ndn: drop interest %v: %v", i.Name, err)
+		return
+	}
+	f.stats.outInterests.Add(1)
+}
+
+func (f *face) SendNack(i *Interest, reason uint64) {
+	buf := new(bytes.Buffer)
+	err := i.WriteTo(tlv.NewWriter(buf))
+	if err != nil {
+		return
+	}
+	b, err := tlv.Marshal(&lpPacket{
+		Nack:     []lpNack{{Reason: reason}},
+		Fragment: buf.Bytes(),
+	}, 100)
+	if err != nil {
+		return
+	}
+	err = f.writePacket(net.Buffers{b})
+	if err != nil {
+		f.logf("ndn: drop nack %v: %v", i.Name, err)
+		return
+	}
+	f.stats.outNacks.Add(1)
+}
+
+// writePacket writes one encoded packet.
+//
+// wm is only held while the packet is written, so encoding does not
+// block other senders.
+// A stream face is closed if a write fails, because a partial packet
+// cannot be recovered from.
+func (f *face) writePacket(bufs net.Buffers) error {
+	if f.datagram {
+		// net.Buffers may be written in more than one datagram.
+		b := bytes.Join(bufs, nil)
+		if len(b) > f.mtu {
+			return ErrPacketTooLarge
+		}
+		bufs = net.Buffers{b}
+	}
 	f.wm.Lock()
-	d.WriteTo(f.Writer)
+	n, err := bufs.WriteTo(f.Conn)
 	f.wm.Unlock()
+	f.stats.outBytes.Add(uint64(n))
+	if err != nil && !f.datagram {
+		f.shutdown(err)
+	}
+	return err
 }
 
 func (f *face) SendInterest(i *Interest) <-chan *Data {
-	ch := make(chan *Data, 1)
+	return f.SendInterestContext(context.Background(), i)
+}
+
+func (f *face) SendInterestContext(ctx context.Context, i *Interest) <-chan *Data {
+	return f.ExpressInterest(ctx, i).ch
+}
+
+func (f *face) ExpressInterest(ctx context.Context, i *Interest) *Response {
+	resp, ch := newResponse(ctx)
+	if f.cache != nil {
+		if d := f.cache.Get(i); d != nil {
+			ch <- d
+			close(ch)
+			return resp
+		}
+	}
 
 	lifeTime := 4 * time.Second
 	if i.LifeTime != 0 {
 		lifeTime = time.Duration(i.LifeTime) * time.Millisecond
 	}
-	timer := time.AfterFunc(lifeTime, func() {
-		f.pitm.Lock()
-		f.Update(i.Name.Components, func(m map[chan<- *Data]pitEntry) map[chan<- *Data]pitEntry {
-			if m == nil {
-				return nil
-			}
-			if _, ok := m[ch]; !ok {
-				return m
-			}
+	// the nonce is recorded in the pit entry before the interest is written.
+	if i.Nonce == 0 {
+		i.Nonce = uint64(rand.Uint32())
+	}
+
+	// the interest is written after the entry is added, without the lock of the pit shard,
+	// so that a slow transport does not block incoming data.
+	key := pitKey(i.Name.Components)
+	if f.negative != nil {
+		if ok, err := f.negative.lookup(key); ok {
+			f.stats.suppressedInterests.Add(1)
+			resp.err = err
 			close(ch)
-			delete(m, ch)
-			if len(m) == 0 {
-				return nil
-			}
+			return resp
+		}
+	}
+	var aggregated, closed bool
+	f.pit.update(key, func(m map[chan<- *Data]pitEntry) map[chan<- *Data]pitEntry {
+		// the shard is drained after closed is set.
+		if f.closed.Load() {
+			closed = true
 			return m
-		}, false)
-		f.pitm.Unlock()
-	})
-
-	f.pitm.Lock()
-	f.Update(i.Name.Components, func(m map[chan<- *Data]pitEntry) map[chan<- *Data]pitEntry {
+		}
 		if m == nil {
 			m = make(map[chan<- *Data]pitEntry)
 		}
 		for _, e := range m {
-			if reflect.DeepEqual(e.Selectors, &i.Selectors) {
-				goto PIT_DONE
+			if e.same(&i.Selectors, i.Name.ImplicitDigestSHA256) {
+				aggregated = true
+				break
 			}
 		}
-		f.wm.Lock()
-		i.WriteTo(f.Writer)
-		f.wm.Unlock()
-	PIT_DONE:
 		m[ch] = pitEntry{
 			Selectors: &i.Selectors,
-			timer:     timer,
+			digest:    i.Name.ImplicitDigestSHA256,
+			timer:     f.expiry.add(key, ch, time.Now().Add(lifeTime)),
+			// a deadline of ctx shorter than lifeTime also expires the entry.
+			// expire waits for the lock of the pit shard, so the entry is added first.
+			stop: context.AfterFunc(ctx, func() {
+				f.expire(key, ch, false)
+			}),
+			resp:  resp,
+			nonce: i.Nonce,
 		}
 		return m
-	}, false)
-	f.pitm.Unlock()
+	})
+	if closed {
+		resp.err = ErrFaceClosed
+		close(ch)
+		return resp
+	}
 
-	return ch
+	if !aggregated {
+		f.writeInterest(i)
+	}
+	return resp
 }
 
-func (f *face) recvData(d *Data) {
-	f.pitm.Lock()
-	f.UpdateAll(d.Name.Components, func(name []lpm.Component, m map[chan<- *Data]pitEntry) map[chan<- *Data]pitEntry {
+// expire removes the pending interest of ch with key.
+//
+// timeout is false if the context of the interest is done.
+func (f *face) expire(key string, ch chan<- *Data, timeout bool) {
+	f.pit.update(key, func(m map[chan<- *Data]pitEntry) map[chan<- *Data]pitEntry {
+		if m == nil {
+			return nil
+		}
+		e, ok := m[ch]
+		if !ok {
+			return m
+		}
+		f.expiry.remove(e.timer)
+		e.stop()
+		close(ch)
+		delete(m, ch)
+		if timeout {
+			f.stats.timeouts.Add(1)
+			if f.negative != nil {
+				f.negative.timeout(key)
+			}
+		}
+		return m
+	})
+}
+
+// same checks whether an interest with sel and digest is aggregated with e.
+func (e *pitEntry) same(sel *Selectors, digest lpm.Component) bool {
+	return bytes.Equal(e.digest, digest) && reflect.DeepEqual(e.Selectors, sel)
+}
+
+// recvData satisfies pending interests with d.
+//
+// If d is carried by lp, its CongestionMark is reported by Response, and
+// it is not cached with the NoCache policy.
+func (f *face) recvData(d *Data, lp *lpPacket) {
+	var satisfied bool
+	var digest []byte
+	digestOnce := func() []byte {
+		if digest == nil {
+			digest, _ = dataDigest(d)
+		}
+		return digest
+	}
+	for n, key := range pitKeys(d.Name.Components) {
+		f.pit.update(key, func(m map[chan<- *Data]pitEntry) map[chan<- *Data]pitEntry {
+			for ch, e := range m {
+				if !matchData(e.Selectors, e.digest, d, n, digestOnce) {
+					continue
+				}
+				satisfied = true
+				if lp != nil {
+					e.resp.congestionMark = lp.CongestionMark
+				}
+				if f.negative != nil {
+					f.negative.satisfied(key)
+				}
+				ch <- d
+				close(ch)
+				f.expiry.remove(e.timer)
+				e.stop()
+				delete(m, ch)
+			}
+			return m
+		})
+	}
+	// unsolicited data is not cached.
+	if satisfied && (lp == nil || !lp.noCache()) && f.cache != nil {
+		f.cache.Add(d)
+	}
+}
+
+// recvLpPacket handles an NDNLPv2 packet.
+//
+// Fragmented packets are dropped, and data packets with the NoCache policy
+// are not cached.
+func (f *face) recvLpPacket(p *lpPacket) {
+	if p.FragCount > 1 || len(p.Fragment) == 0 {
+		return
+	}
+	r := tlv.NewReader(bytes.NewReader(p.Fragment))
+	if len(p.Nack) == 0 {
+		f.readPacket(r, p)
+		return
+	}
+	i := new(Interest)
+	err := i.ReadFrom(r)
+	if err != nil {
+		f.stats.decodeErrors.Add(1)
+		return
+	}
+	f.stats.inNacks.Add(1)
+	f.recvNack(i, p.Nack[0].Reason)
+}
+
+// recvNack rejects the pending interest with the nonce of i, and
+// the interests aggregated with it.
+func (f *face) recvNack(i *Interest, reason uint64) {
+	key := pitKey(i.Name.Components)
+	f.pit.update(key, func(m map[chan<- *Data]pitEntry) map[chan<- *Data]pitEntry {
+		var nacked *pitEntry
+		for _, e := range m {
+			if e.nonce == i.Nonce {
+				nacked = &e
+				break
+			}
+		}
+		if nacked == nil {
+			return m
+		}
+		if f.negative != nil {
+			f.negative.nack(key, reason)
+		}
 		for ch, e := range m {
-			if !e.Match(d, len(name)) {
+			if !e.same(nacked.Selectors, nacked.digest) {
 				continue
 			}
-			ch <- d
+			e.resp.err = &NackError{Reason: reason}
 			close(ch)
-			e.timer.Stop()
+			f.expiry.remove(e.timer)
+			e.stop()
 			delete(m, ch)
 		}
-		if len(m) == 0 {
-			return nil
-		}
 		return m
-	}, true)
-	f.pitm.Unlock()
+	})
 }
 
 func (f *face) recvInterest(i *Interest) {
+	if f.cache != nil {
+		if d := f.cache.Get(i); d != nil {
+			f.writeData(d)
+			return
+		}
+	}
+	if f.dedup != nil && f.dedup.duplicate(i) {
+		f.stats.duplicateInterests.Add(1)
+		return
+	}
+	h := f.handler
+	if ph := f.matchHandler(i.Name); ph != nil {
+		h = ph.InterestHandler
+	}
+	if h != nil {
+		f.serveInterest(h, i)
+		return
+	}
+	if f.queue != nil {
+		f.enqueueInterest(i)
+		return
+	}
 	if f.recv != nil {
-		f.recv <- i
+		select {
+		case f.recv <- i:
+		case <-f.closing:
+		}
+	}
+}
+
+// serveInterest runs h in a background goroutine, or nacks i with
+// NackReasonCongestion if too many handlers are running.
+func (f *face) serveInterest(h InterestHandler, i *Interest) {
+	if f.handlerSem != nil {
+		select {
+		case f.handlerSem <- struct{}{}:
+		default:
+			f.stats.droppedInterests.Add(1)
+			f.logf("ndn: drop interest %v from %v: too many handlers", i.Name, f.RemoteAddr())
+			f.SendNack(i, NackReasonCongestion)
+			return
+		}
+	}
+	release := func() {
+		if f.handlerSem != nil {
+			<-f.handlerSem
+		}
+	}
+	err := f.goroutine(func() {
+		defer release()
+		h.ServeInterest(f, i)
+	})
+	if err != nil {
+		release()
 	}
 }
@@ -0,0 +1,64 @@
+package ndn
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DuplicateOptions controls WithDuplicateSuppression.
+type DuplicateOptions struct {
+	// Window is how long an incoming interest is remembered.
+	// If it is 0, 1 second is used.
+	Window time.Duration
+
+	// ByName suppresses interests with the same name in Window,
+	// even if their nonces are different.
+	// Otherwise, only interests with the same name and nonce are suppressed.
+	ByName bool
+}
+
+// dedupTable remembers recent incoming interests.
+type dedupTable struct {
+	DuplicateOptions
+
+	sync.Mutex
+	seen map[string]time.Time
+	// purged is when expired entries are last removed.
+	purged time.Time
+}
+
+func newDedupTable(opts DuplicateOptions) *dedupTable {
+	if opts.Window <= 0 {
+		opts.Window = time.Second
+	}
+	return &dedupTable{
+		DuplicateOptions: opts,
+		seen:             make(map[string]time.Time),
+		purged:           time.Now(),
+	}
+}
+
+// duplicate remembers i, and checks whether it is seen in the window.
+func (t *dedupTable) duplicate(i *Interest) bool {
+	key := i.Name.String()
+	if !t.ByName {
+		key += "#" + strconv.FormatUint(i.Nonce, 16)
+	}
+	now := time.Now()
+	t.Lock()
+	defer t.Unlock()
+	if now.Sub(t.purged) > t.Window {
+		for k, expire := range t.seen {
+			if now.After(expire) {
+				delete(t.seen, k)
+			}
+		}
+		t.purged = now
+	}
+	if expire, ok := t.seen[key]; ok && now.Before(expire) {
+		return true
+	}
+	t.seen[key] = now.Add(t.Window)
+	return false
+}
@@ -0,0 +1,40 @@
+package ndn
+
+import "net"
+
+// EtherType is the ethertype of NDN.
+const EtherType = 0x8624
+
+// MulticastEther is the default NDN Ethernet multicast group.
+var MulticastEther = net.HardwareAddr{0x01, 0x00, 0x5E, 0x00, 0x17, 0xAA}
+
+// EtherAddr is the address of an Ethernet face.
+type EtherAddr struct {
+	net.HardwareAddr
+}
+
+// Network implements net.Addr.
+func (a *EtherAddr) Network() string {
+	return "ether"
+}
+
+// NewEtherFace exchanges packets with NDN peers on the local link,
+// such as NFD Ethernet multicast faces, without IP configuration.
+//
+// Every packet is sent in one frame with NDN ethertype to group.
+// If group is nil, MulticastEther is used.
+// Packets larger than the MTU of ifi are not sent, because NDNLPv2
+// fragmentation is not supported.
+//
+// Raw sockets are only supported on Linux, and need CAP_NET_RAW.
+// opts are passed to NewDatagramFace.
+func NewEtherFace(ifi *net.Interface, group net.HardwareAddr, opts ...FaceOption) (Face, error) {
+	if group == nil {
+		group = MulticastEther
+	}
+	conn, err := listenEther(ifi, group)
+	if err != nil {
+		return nil, err
+	}
+	return NewDatagramFace(conn, opts...), nil
+}
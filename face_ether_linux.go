@@ -0,0 +1,135 @@
+package ndn
+
+import (
+	"encoding/binary"
+	"net"
+	"os"
+	"syscall"
+	"time"
+)
+
+// etherConn is an AF_PACKET socket bound to the NDN ethertype.
+type etherConn struct {
+	f     *os.File
+	raw   syscall.RawConn
+	local *EtherAddr
+	group *EtherAddr
+	to    *syscall.SockaddrLinklayer
+}
+
+// htons converts a 16-bit number to network byte order.
+func htons(v uint16) uint16 {
+	return v<<8 | v>>8
+}
+
+func listenEther(ifi *net.Interface, group net.HardwareAddr) (net.Conn, error) {
+	if ifi == nil || len(group) != 6 {
+		return nil, ErrNotSupported
+	}
+	proto := htons(EtherType)
+	fd, err := syscall.Socket(syscall.AF_PACKET, syscall.SOCK_DGRAM|syscall.SOCK_CLOEXEC|syscall.SOCK_NONBLOCK, int(proto))
+	if err != nil {
+		return nil, os.NewSyscallError("socket", err)
+	}
+	err = syscall.Bind(fd, &syscall.SockaddrLinklayer{
+		Protocol: proto,
+		Ifindex:  ifi.Index,
+	})
+	if err != nil {
+		syscall.Close(fd)
+		return nil, os.NewSyscallError("bind", err)
+	}
+	// struct packet_mreq
+	mreq := make([]byte, 16)
+	binary.NativeEndian.PutUint32(mreq, uint32(ifi.Index))
+	binary.NativeEndian.PutUint16(mreq[4:], syscall.PACKET_MR_MULTICAST)
+	binary.NativeEndian.PutUint16(mreq[6:], uint16(len(group)))
+	copy(mreq[8:], group)
+	err = syscall.SetsockoptString(fd, syscall.SOL_PACKET, syscall.PACKET_ADD_MEMBERSHIP, string(mreq))
+	if err != nil {
+		syscall.Close(fd)
+		return nil, os.NewSyscallError("setsockopt", err)
+	}
+
+	f := os.NewFile(uintptr(fd), "ether:"+ifi.Name)
+	raw, err := f.SyscallConn()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	to := &syscall.SockaddrLinklayer{
+		Protocol: proto,
+		Ifindex:  ifi.Index,
+		Halen:    uint8(len(group)),
+	}
+	copy(to.Addr[:], group)
+	return &etherConn{
+		f:     f,
+		raw:   raw,
+		local: &EtherAddr{HardwareAddr: ifi.HardwareAddr},
+		group: &EtherAddr{HardwareAddr: group},
+		to:    to,
+	}, nil
+}
+
+// Read reads one frame payload, and skips frames sent by this host.
+func (c *etherConn) Read(b []byte) (n int, err error) {
+	for {
+		var from syscall.Sockaddr
+		var serr error
+		err = c.raw.Read(func(fd uintptr) bool {
+			n, from, serr = syscall.Recvfrom(int(fd), b, 0)
+			return serr != syscall.EAGAIN
+		})
+		if err == nil {
+			err = serr
+		}
+		if err != nil {
+			return 0, err
+		}
+		if sa, ok := from.(*syscall.SockaddrLinklayer); ok && sa.Pkttype == syscall.PACKET_OUTGOING {
+			continue
+		}
+		return n, nil
+	}
+}
+
+// Write sends b in one frame to the group.
+func (c *etherConn) Write(b []byte) (int, error) {
+	var serr error
+	err := c.raw.Write(func(fd uintptr) bool {
+		serr = syscall.Sendto(int(fd), b, 0, c.to)
+		return serr != syscall.EAGAIN
+	})
+	if err == nil {
+		err = serr
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+func (c *etherConn) Close() error {
+	return c.f.Close()
+}
+
+func (c *etherConn) LocalAddr() net.Addr {
+	return c.local
+}
+
+func (c *etherConn) RemoteAddr() net.Addr {
+	return c.group
+}
+
+func (c *etherConn) SetDeadline(t time.Time) error {
+	return c.f.SetDeadline(t)
+}
+
+func (c *etherConn) SetReadDeadline(t time.Time) error {
+	return c.f.SetReadDeadline(t)
+}
+
+func (c *etherConn) SetWriteDeadline(t time.Time) error {
+	return c.f.SetWriteDeadline(t)
+}
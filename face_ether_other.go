@@ -0,0 +1,9 @@
+//go:build !linux
+
+package ndn
+
+import "net"
+
+func listenEther(ifi *net.Interface, group net.HardwareAddr) (net.Conn, error) {
+	return nil, ErrNotSupported
+}
@@ -0,0 +1,36 @@
+package ndn
+
+// InterestHandler responds to incoming interests.
+//
+// It is an alternative to the incoming interest queue; see WithInterestHandler.
+type InterestHandler interface {
+	// ServeInterest handles i, which is received from w.
+	// Data is sent with w.SendData.
+	ServeInterest(w Sender, i *Interest)
+}
+
+// InterestHandlerFunc is an adapter to use a function as InterestHandler.
+type InterestHandlerFunc func(w Sender, i *Interest)
+
+// ServeInterest calls fn(w, i).
+func (fn InterestHandlerFunc) ServeInterest(w Sender, i *Interest) {
+	fn(w, i)
+}
+
+// WithInterestHandler handles every incoming interest with h in a new goroutine,
+// so that a producer does not need its own dispatch loop.
+// The number of running handlers is limited by WithMaxHandlers.
+//
+// Only the last of WithInterestQueue and WithInterestHandler takes effect.
+// Close waits for running handlers to return,
+// and interests received after the face starts closing are not handled.
+// Therefore, handlers must not call Close or CloseWithContext on the face
+// they are serving, or block until it closes, because Close would wait for
+// the handler itself.
+// A handler that needs to close its face can call Close in a new goroutine.
+func WithInterestHandler(h InterestHandler) FaceOption {
+	return func(f *face) {
+		f.handler = h
+		f.recv = nil
+	}
+}
@@ -0,0 +1,92 @@
+package ndn
+
+import (
+	"bytes"
+	"net"
+	"time"
+
+	"github.com/go-ndn/tlv"
+)
+
+// lpIdle is an NDNLPv2 packet without fragment, which is ignored by the peer.
+var lpIdle = []byte{100, 0}
+
+// KeepAliveOptions controls KeepAlive.
+type KeepAliveOptions struct {
+	// Interval is how long the face can be idle before a keep-alive packet is sent.
+	Interval time.Duration
+
+	// Timeout is how long the face waits for any incoming packet before it is closed.
+	// If it is 0, 3 intervals are used.
+	Timeout time.Duration
+
+	// Probe is sent as the keep-alive packet with a new nonce, so that a peer
+	// that does not send keep-alive packets also answers, e.g.
+	// /localhost/nfd/status/general for NFD.
+	// If it is nil, an NDNLPv2 IDLE packet is sent.
+	Probe *Interest
+}
+
+// KeepAlive sends keep-alive packets when f has nothing to send, and closes f
+// if nothing is received within the timeout, so that a hung connection is
+// detected in seconds.
+//
+// After f is closed by KeepAlive, Err of f returns ErrTimeout.
+// f must be created by NewFace or NewDatagramFace, and
+// ErrFaceClosed is returned if f is already closed.
+func KeepAlive(f Face, opts KeepAliveOptions) error {
+	ff, ok := f.(*face)
+	if !ok || opts.Interval <= 0 {
+		return ErrNotSupported
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 3 * opts.Interval
+	}
+	return ff.goroutine(func() {
+		ff.keepAlive(opts)
+	})
+}
+
+func (f *face) keepAlive(opts KeepAliveOptions) {
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+	in, out := f.stats.inBytes.Load(), f.stats.outBytes.Load()
+	lastIn := time.Now()
+	for {
+		var now time.Time
+		select {
+		case <-f.closing:
+			return
+		case now = <-ticker.C:
+		}
+		if n := f.stats.inBytes.Load(); n != in {
+			in = n
+			lastIn = now
+		} else if now.Sub(lastIn) >= opts.Timeout {
+			f.shutdown(ErrTimeout)
+			return
+		}
+		if n := f.stats.outBytes.Load(); n != out {
+			out = n
+			continue
+		}
+		f.writeKeepAlive(opts.Probe)
+		out = f.stats.outBytes.Load()
+	}
+}
+
+func (f *face) writeKeepAlive(probe *Interest) {
+	b := lpIdle
+	if probe != nil {
+		i := *probe
+		// a new nonce is populated.
+		i.Nonce = 0
+		buf := new(bytes.Buffer)
+		err := i.WriteTo(tlv.NewWriter(buf))
+		if err != nil {
+			return
+		}
+		b = buf.Bytes()
+	}
+	f.writePacket(net.Buffers{b})
+}
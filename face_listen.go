@@ -0,0 +1,64 @@
+package ndn
+
+import "github.com/go-ndn/lpm"
+
+// ListenFace is a face that dispatches incoming interests by name prefix,
+// such as faces created by NewFace.
+type ListenFace interface {
+	Face
+	// Listen handles incoming interests under prefix with h, instead of
+	// the interest queue or the handler of the face.
+	// If more than one prefix matches, the longest one is used.
+	// h runs like the handler of WithInterestHandler.
+	//
+	// The returned function stops listening.
+	// If prefix is already listened, ErrPrefixListened is returned.
+	Listen(prefix Name, h InterestHandler) (func(), error)
+}
+
+// prefixHandler is a handler registered by Listen or Mux.
+//
+// Its pointer identifies the registration, because handlers might not be comparable.
+type prefixHandler struct {
+	InterestHandler
+}
+
+func (f *face) Listen(prefix Name, h InterestHandler) (func(), error) {
+	ph := &prefixHandler{InterestHandler: h}
+	var err error
+	f.hm.Lock()
+	f.handlers.Update(prefix.Components, func(v *prefixHandler) *prefixHandler {
+		if v != nil {
+			err = ErrPrefixListened
+			return v
+		}
+		return ph
+	}, false)
+	f.hm.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return func() {
+		f.hm.Lock()
+		f.handlers.Update(prefix.Components, func(v *prefixHandler) *prefixHandler {
+			if v == ph {
+				return nil
+			}
+			return v
+		}, true)
+		f.hm.Unlock()
+	}, nil
+}
+
+// matchHandler returns the handler of the longest prefix of name,
+// or nil if no prefix matches.
+func (f *face) matchHandler(name Name) (ph *prefixHandler) {
+	f.hm.Lock()
+	// every existing handler along name is visited from the root.
+	f.handlers.UpdateAll(name.Components, func(_ []lpm.Component, v *prefixHandler) *prefixHandler {
+		ph = v
+		return v
+	}, true)
+	f.hm.Unlock()
+	return
+}
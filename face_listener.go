@@ -0,0 +1,170 @@
+package ndn
+
+import (
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// websocketHandshakeTimeout bounds the upgrade request of a WebSocket connection.
+const websocketHandshakeTimeout = 10 * time.Second
+
+// Listener accepts connections from other nodes as faces, so that
+// a process can act as a hub that other nodes dial into.
+type Listener struct {
+	net.Listener
+	websocket bool
+	// datagram is true if every connection carries datagrams, such as ListenUDP.
+	datagram bool
+
+	mu     sync.Mutex
+	faces  map[Face]struct{}
+	closed bool
+}
+
+// Listen listens on a face uri:
+//
+//	tcp://:6363		stream faces over TCP; tcp4 and tcp6 are also accepted
+//	unix:///run/hub.sock	stream faces over a unix socket
+//	ws://:9696		datagram faces over WebSocket, like DialWebSocket
+//	udp://:6363		on-demand datagram faces over UDP; see ListenUDP
+//
+// If the port of a tcp, udp or ws uri is missing, 6363 or 9696 is used.
+func Listen(uri string) (*Listener, error) {
+	scheme, address, ok := strings.Cut(uri, "://")
+	if !ok {
+		return nil, ErrInvalidFaceURI
+	}
+	network := scheme
+	switch scheme {
+	case "tcp", "tcp4", "tcp6":
+		address = listenAddress(address, "6363")
+	case "ws":
+		// the path is not checked.
+		network = "tcp"
+		address = listenAddress(address, "9696")
+	case "udp", "udp4", "udp6":
+		return ListenUDP(scheme, listenAddress(address, "6363"), 0)
+	case "unix":
+		if address == "" {
+			return nil, ErrInvalidFaceURI
+		}
+	default:
+		return nil, ErrNotSupported
+	}
+	ln, err := net.Listen(network, address)
+	if err != nil {
+		return nil, err
+	}
+	return &Listener{
+		Listener:  ln,
+		websocket: scheme == "ws",
+		faces:     make(map[Face]struct{}),
+	}, nil
+}
+
+// listenAddress returns host:port with the default port.
+func listenAddress(address, port string) string {
+	address, _, _ = strings.Cut(address, "/")
+	if _, _, err := net.SplitHostPort(address); err == nil {
+		return address
+	}
+	return net.JoinHostPort(strings.TrimSuffix(strings.TrimPrefix(address, "["), "]"), port)
+}
+
+// Accept waits for the next connection, and returns it as a face.
+//
+// WebSocket connections are upgraded here, so that a failed handshake is reported here.
+// opts are passed to NewFace or NewDatagramFace.
+func (ln *Listener) Accept(opts ...FaceOption) (Face, error) {
+	conn, err := ln.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return ln.newFace(conn, opts)
+}
+
+func (ln *Listener) newFace(conn net.Conn, opts []FaceOption) (Face, error) {
+	if ln.datagram {
+		return NewDatagramFace(conn, opts...), nil
+	}
+	if !ln.websocket {
+		return NewFace(conn, opts...), nil
+	}
+	conn.SetDeadline(time.Now().Add(websocketHandshakeTimeout))
+	ws, err := websocketServerHandshake(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+	return NewDatagramFace(ws, opts...), nil
+}
+
+// Serve accepts connections until the listener is closed, and handles
+// incoming interests of every face with h.
+//
+// Failed WebSocket handshakes are ignored. Served faces are closed by Close.
+// opts are passed to Accept.
+func (ln *Listener) Serve(h InterestHandler, opts ...FaceOption) error {
+	opts = append(opts[:len(opts):len(opts)], WithInterestHandler(h))
+	for {
+		conn, err := ln.Listener.Accept()
+		if err != nil {
+			return err
+		}
+		go func() {
+			f, err := ln.newFace(conn, opts)
+			if err != nil {
+				return
+			}
+			ln.track(f)
+		}()
+	}
+}
+
+// track adds f to served faces until f stops.
+func (ln *Listener) track(f Face) {
+	ln.mu.Lock()
+	if ln.closed {
+		ln.mu.Unlock()
+		f.Close()
+		return
+	}
+	ln.faces[f] = struct{}{}
+	ln.mu.Unlock()
+	df, ok := f.(DoneFace)
+	if !ok {
+		return
+	}
+	<-df.Done()
+	ln.mu.Lock()
+	delete(ln.faces, f)
+	ln.mu.Unlock()
+}
+
+// Faces returns faces that are served by Serve.
+func (ln *Listener) Faces() []Face {
+	ln.mu.Lock()
+	defer ln.mu.Unlock()
+	faces := make([]Face, 0, len(ln.faces))
+	for f := range ln.faces {
+		faces = append(faces, f)
+	}
+	return faces
+}
+
+// Close stops listening, and closes all faces served by Serve.
+func (ln *Listener) Close() error {
+	ln.mu.Lock()
+	ln.closed = true
+	faces := ln.faces
+	ln.faces = make(map[Face]struct{})
+	ln.mu.Unlock()
+	err := ln.Listener.Close()
+	for f := range faces {
+		f.Close()
+	}
+	return err
+}
@@ -0,0 +1,58 @@
+package ndn
+
+import (
+	"errors"
+	"net"
+)
+
+// Errors introduced by memif transport.
+var (
+	ErrMemifHandshake = errors.New("memif handshake failed")
+	ErrMemifRingFull  = errors.New("memif ring is full")
+	ErrPacketTooLarge = errors.New("packet is too large")
+)
+
+// Default Ethernet addresses of NDN-DPDK memif faces.
+var (
+	MemifAddressDPDK = net.HardwareAddr{0x02, 0x00, 0x00, 0x00, 0x00, 0x01}
+	MemifAddressApp  = net.HardwareAddr{0x02, 0x00, 0x00, 0x00, 0x00, 0x02}
+)
+
+// MemifOptions controls NewMemifFace.
+type MemifOptions struct {
+	// SocketName is the control socket of the memif server,
+	// such as the socketName of an NDN-DPDK memif face locator.
+	SocketName string
+
+	// ID is the interface id, which must be the same as the server side.
+	ID uint32
+
+	// Dataroom is the buffer size of one frame, including the Ethernet header.
+	// If it is 0, 2048 is used.
+	Dataroom int
+
+	// RingCapacity is the number of frames in each ring.
+	// It is rounded up to a power of 2. If it is 0, 1024 is used.
+	RingCapacity int
+
+	// Local and Remote are the Ethernet addresses of frames.
+	// If they are nil, MemifAddressApp and MemifAddressDPDK are used.
+	Local, Remote net.HardwareAddr
+}
+
+// NewMemifFace attaches to a memif server, such as an NDN-DPDK forwarder,
+// and exchanges packets in shared memory instead of sockets.
+//
+// This side is the memif client, and owns the shared memory.
+// The face must be created on the forwarder first with the same socket name and id.
+// Every packet is carried in one Ethernet frame with NDN ethertype.
+//
+// memif is only supported on Linux amd64 and arm64.
+// faceOpts are passed to NewDatagramFace.
+func NewMemifFace(opts MemifOptions, faceOpts ...FaceOption) (Face, error) {
+	conn, err := dialMemif(opts)
+	if err != nil {
+		return nil, err
+	}
+	return NewDatagramFace(conn, faceOpts...), nil
+}
@@ -0,0 +1,506 @@
+//go:build linux && (amd64 || arm64)
+
+package ndn
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/bits"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// memif protocol version 2.0 from libmemif.
+const (
+	memifCookie  = 0x3E31F20
+	memifVersion = 2 << 8
+	memifMsgSize = 128
+
+	memifMsgAck        = 1
+	memifMsgHello      = 2
+	memifMsgInit       = 3
+	memifMsgAddRegion  = 4
+	memifMsgAddRing    = 5
+	memifMsgConnect    = 6
+	memifMsgConnected  = 7
+	memifMsgDisconnect = 8
+
+	// memifRingS2M is the ADD_RING flag of client-to-server rings.
+	memifRingS2M = 1
+	// memifRingMaskInt is set by the consumer that does not need interrupts.
+	memifRingMaskInt = 1
+	memifDescNext    = 1
+
+	memifRingHeaderSize = 128
+	memifDescSize       = 16
+	memifMaxLog2Ring    = 14
+	memifEtherHeaderLen = 14
+
+	// memifPollInterval bounds the wait for an interrupt that the server does not send.
+	memifPollInterval = 10 * time.Millisecond
+)
+
+// memifRing is a ring in the shared memory region.
+//
+// Every packet is in one descriptor, whose buffer is fixed per slot.
+// The client is always the producer of head, and the server is the producer of tail.
+type memifRing struct {
+	b    []byte
+	mask uint16
+}
+
+func (r *memifRing) word(offset int) *uint32 {
+	return (*uint32)(unsafe.Pointer(&r.b[offset]))
+}
+
+// flags and head share the 32-bit word at offset 4 in little endian.
+func (r *memifRing) flags() uint16 {
+	return uint16(atomic.LoadUint32(r.word(4)))
+}
+
+func (r *memifRing) head() uint16 {
+	return uint16(atomic.LoadUint32(r.word(4)) >> 16)
+}
+
+func (r *memifRing) setHead(head uint16) {
+	w := r.word(4)
+	for {
+		old := atomic.LoadUint32(w)
+		if atomic.CompareAndSwapUint32(w, old, old&0xFFFF|uint32(head)<<16) {
+			return
+		}
+	}
+}
+
+func (r *memifRing) tail() uint16 {
+	return uint16(atomic.LoadUint32(r.word(64)))
+}
+
+func (r *memifRing) desc(slot uint16) []byte {
+	offset := memifRingHeaderSize + memifDescSize*int(slot&r.mask)
+	return r.b[offset : offset+memifDescSize]
+}
+
+type memifConn struct {
+	ctrl   *net.UnixConn
+	region []byte
+
+	tx, rx       memifRing
+	txInt, rxInt *os.File // eventfd
+	txBuf, rxBuf int      // offset of the buffer of slot 0
+	lastTail     uint16   // next slot to receive
+	dataroom     int
+	header       []byte // ethernet header of outgoing frames
+
+	local, remote *EtherAddr
+	readDeadline  atomic.Value
+
+	// region is unmapped with write lock.
+	mu     sync.RWMutex
+	closed bool
+	// done is closed after the control socket is down.
+	done chan struct{}
+}
+
+func dialMemif(opts MemifOptions) (net.Conn, error) {
+	dataroom := opts.Dataroom
+	if dataroom == 0 {
+		dataroom = 2048
+	}
+	capacity := opts.RingCapacity
+	if capacity == 0 {
+		capacity = 1024
+	}
+	if dataroom <= memifEtherHeaderLen || capacity < 0 {
+		return nil, ErrNotSupported
+	}
+	log2Ring := bits.Len(uint(capacity - 1))
+	local := opts.Local
+	if local == nil {
+		local = MemifAddressApp
+	}
+	remote := opts.Remote
+	if remote == nil {
+		remote = MemifAddressDPDK
+	}
+
+	ctrl, err := net.DialUnix("unixpacket", nil, &net.UnixAddr{
+		Name: opts.SocketName,
+		Net:  "unixpacket",
+	})
+	if err != nil {
+		return nil, err
+	}
+	c := &memifConn{
+		ctrl:     ctrl,
+		dataroom: dataroom,
+		local:    &EtherAddr{HardwareAddr: local},
+		remote:   &EtherAddr{HardwareAddr: remote},
+		done:     make(chan struct{}),
+	}
+	c.header = make([]byte, memifEtherHeaderLen)
+	copy(c.header, remote)
+	copy(c.header[6:], local)
+	binary.BigEndian.PutUint16(c.header[12:], EtherType)
+
+	ctrl.SetDeadline(time.Now().Add(5 * time.Second))
+	err = c.handshake(opts.ID, log2Ring)
+	if err != nil {
+		c.release()
+		return nil, err
+	}
+	ctrl.SetDeadline(time.Time{})
+	go c.control()
+	return c, nil
+}
+
+// handshake follows the client side of the memif control protocol:
+// HELLO from the server, and then INIT, ADD_REGION, ADD_RING and CONNECT
+// from the client, each acknowledged by the server.
+func (c *memifConn) handshake(id uint32, log2Ring int) error {
+	hello, err := c.receiveMsg(memifMsgHello)
+	if err != nil {
+		return err
+	}
+	if binary.LittleEndian.Uint16(hello[34:]) > memifVersion ||
+		binary.LittleEndian.Uint16(hello[36:]) < memifVersion {
+		return ErrMemifHandshake
+	}
+	if limit := int(hello[44]); log2Ring > limit {
+		log2Ring = limit
+	}
+	if log2Ring > memifMaxLog2Ring {
+		log2Ring = memifMaxLog2Ring
+	}
+
+	shm, err := c.allocate(log2Ring)
+	if err != nil {
+		return err
+	}
+	defer shm.Close()
+
+	msg := newMemifMsg(memifMsgInit)
+	binary.LittleEndian.PutUint16(msg[2:], memifVersion)
+	binary.LittleEndian.PutUint32(msg[4:], id)
+	copy(msg[33:65], "go-ndn")
+	_, err = c.request(msg, nil, memifMsgAck)
+	if err != nil {
+		return err
+	}
+
+	msg = newMemifMsg(memifMsgAddRegion)
+	binary.LittleEndian.PutUint64(msg[4:], uint64(len(c.region)))
+	_, err = c.request(msg, shm, memifMsgAck)
+	if err != nil {
+		return err
+	}
+
+	ringSize := memifRingHeaderSize + memifDescSize<<log2Ring
+	for i, intr := range []*os.File{c.txInt, c.rxInt} {
+		msg = newMemifMsg(memifMsgAddRing)
+		if i == 0 {
+			binary.LittleEndian.PutUint16(msg[2:], memifRingS2M)
+		}
+		binary.LittleEndian.PutUint32(msg[8:], uint32(i*ringSize))
+		msg[12] = byte(log2Ring)
+		_, err = c.request(msg, intr, memifMsgAck)
+		if err != nil {
+			return err
+		}
+	}
+
+	msg = newMemifMsg(memifMsgConnect)
+	copy(msg[2:34], "go-ndn")
+	_, err = c.request(msg, nil, memifMsgConnected)
+	return err
+}
+
+// allocate creates the shared memory region and interrupts.
+//
+// The region has the client-to-server ring, the server-to-client ring,
+// and then the buffers of both rings.
+func (c *memifConn) allocate(log2Ring int) (*os.File, error) {
+	size := 1 << log2Ring
+	ringSize := memifRingHeaderSize + memifDescSize*size
+	c.txBuf = 2 * ringSize
+	c.rxBuf = c.txBuf + size*c.dataroom
+	regionSize := c.rxBuf + size*c.dataroom
+
+	dir := "/dev/shm"
+	if _, err := os.Stat(dir); err != nil {
+		dir = os.TempDir()
+	}
+	shm, err := os.CreateTemp(dir, "ndn-memif-")
+	if err != nil {
+		return nil, err
+	}
+	os.Remove(shm.Name())
+	err = shm.Truncate(int64(regionSize))
+	if err != nil {
+		shm.Close()
+		return nil, err
+	}
+	c.region, err = syscall.Mmap(int(shm.Fd()), 0, regionSize, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		shm.Close()
+		return nil, os.NewSyscallError("mmap", err)
+	}
+	c.txInt, err = newEventFD()
+	if err == nil {
+		c.rxInt, err = newEventFD()
+	}
+	if err != nil {
+		shm.Close()
+		return nil, err
+	}
+
+	c.tx = memifRing{b: c.region[:ringSize], mask: uint16(size - 1)}
+	c.rx = memifRing{b: c.region[ringSize : 2*ringSize], mask: uint16(size - 1)}
+	for i, r := range []*memifRing{&c.tx, &c.rx} {
+		binary.LittleEndian.PutUint32(r.b, memifCookie)
+		buf := c.txBuf
+		if i == 1 {
+			buf = c.rxBuf
+		}
+		for slot := 0; slot < size; slot++ {
+			d := r.desc(uint16(slot))
+			binary.LittleEndian.PutUint32(d[4:], uint32(c.dataroom))
+			binary.LittleEndian.PutUint32(d[8:], uint32(buf+slot*c.dataroom))
+		}
+	}
+	// all buffers of the server-to-client ring are given to the server.
+	c.rx.setHead(uint16(size))
+	return shm, nil
+}
+
+func newEventFD() (*os.File, error) {
+	fd, _, errno := syscall.Syscall(syscall.SYS_EVENTFD2, 0, syscall.O_CLOEXEC|syscall.O_NONBLOCK, 0)
+	if errno != 0 {
+		return nil, os.NewSyscallError("eventfd2", errno)
+	}
+	return os.NewFile(fd, "eventfd"), nil
+}
+
+func newMemifMsg(typ uint16) []byte {
+	msg := make([]byte, memifMsgSize)
+	binary.LittleEndian.PutUint16(msg, typ)
+	return msg
+}
+
+// request sends msg with an optional file descriptor, and waits for the reply.
+func (c *memifConn) request(msg []byte, file syscall.Conn, reply uint16) ([]byte, error) {
+	var oob []byte
+	if file != nil {
+		raw, err := file.SyscallConn()
+		if err != nil {
+			return nil, err
+		}
+		raw.Control(func(fd uintptr) {
+			oob = syscall.UnixRights(int(fd))
+		})
+	}
+	_, _, err := c.ctrl.WriteMsgUnix(msg, oob, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.receiveMsg(reply)
+}
+
+func (c *memifConn) receiveMsg(typ uint16) ([]byte, error) {
+	msg := make([]byte, memifMsgSize)
+	n, _, _, _, err := c.ctrl.ReadMsgUnix(msg, nil)
+	if err != nil {
+		return nil, err
+	}
+	if n < 2 || binary.LittleEndian.Uint16(msg) != typ {
+		return nil, ErrMemifHandshake
+	}
+	return msg, nil
+}
+
+// control waits until the server disconnects.
+func (c *memifConn) control() {
+	defer close(c.done)
+	msg := make([]byte, memifMsgSize)
+	for {
+		n, _, _, _, err := c.ctrl.ReadMsgUnix(msg, nil)
+		if err != nil || n >= 2 && binary.LittleEndian.Uint16(msg) == memifMsgDisconnect {
+			return
+		}
+	}
+}
+
+// Read reads the payload of one frame.
+func (c *memifConn) Read(b []byte) (int, error) {
+	for {
+		n, ok, err := c.receive(b)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			return n, nil
+		}
+		err = c.wait()
+		if err != nil {
+			return 0, err
+		}
+	}
+}
+
+func (c *memifConn) receive(b []byte) (n int, ok bool, err error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.closed {
+		return 0, false, net.ErrClosed
+	}
+	tail := c.rx.tail()
+	for c.lastTail != tail {
+		var frame []byte
+		for c.lastTail != tail {
+			d := c.rx.desc(c.lastTail)
+			c.lastTail++
+			length := int(binary.LittleEndian.Uint32(d[4:]))
+			offset := int(binary.LittleEndian.Uint32(d[8:]))
+			if offset+length <= len(c.region) {
+				frame = append(frame, c.region[offset:offset+length]...)
+			}
+			if binary.LittleEndian.Uint16(d)&memifDescNext == 0 {
+				break
+			}
+		}
+		c.refill()
+		if len(frame) > memifEtherHeaderLen && binary.BigEndian.Uint16(frame[12:]) == EtherType {
+			return copy(b, frame[memifEtherHeaderLen:]), true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// refill gives received buffers back to the server.
+func (c *memifConn) refill() {
+	head := c.rx.head()
+	for ; head != c.lastTail+c.rx.mask+1; head++ {
+		d := c.rx.desc(head)
+		binary.LittleEndian.PutUint16(d, 0)
+		binary.LittleEndian.PutUint32(d[4:], uint32(c.dataroom))
+		binary.LittleEndian.PutUint32(d[8:], uint32(c.rxBuf+int(head&c.rx.mask)*c.dataroom))
+	}
+	c.rx.setHead(head)
+}
+
+// wait waits for the interrupt of the server-to-client ring.
+func (c *memifConn) wait() error {
+	deadline := time.Now().Add(memifPollInterval)
+	if t, ok := c.readDeadline.Load().(time.Time); ok && !t.IsZero() {
+		if !time.Now().Before(t) {
+			return os.ErrDeadlineExceeded
+		}
+		if t.Before(deadline) {
+			deadline = t
+		}
+	}
+	c.rxInt.SetReadDeadline(deadline)
+	var buf [8]byte
+	_, err := c.rxInt.Read(buf[:])
+	select {
+	case <-c.done:
+		return io.EOF
+	default:
+	}
+	if err != nil && !errors.Is(err, os.ErrDeadlineExceeded) {
+		return err
+	}
+	return nil
+}
+
+// Write sends b in one frame.
+func (c *memifConn) Write(b []byte) (int, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.closed {
+		return 0, net.ErrClosed
+	}
+	select {
+	case <-c.done:
+		return 0, io.ErrClosedPipe
+	default:
+	}
+	if memifEtherHeaderLen+len(b) > c.dataroom {
+		return 0, ErrPacketTooLarge
+	}
+	head := c.tx.head()
+	if head-c.tx.tail() > c.tx.mask {
+		return 0, ErrMemifRingFull
+	}
+	offset := c.txBuf + int(head&c.tx.mask)*c.dataroom
+	n := copy(c.region[offset:], c.header)
+	n += copy(c.region[offset+n:], b)
+	d := c.tx.desc(head)
+	binary.LittleEndian.PutUint16(d, 0)
+	binary.LittleEndian.PutUint32(d[4:], uint32(n))
+	binary.LittleEndian.PutUint32(d[8:], uint32(offset))
+	c.tx.setHead(head + 1)
+	if c.tx.flags()&memifRingMaskInt == 0 {
+		var one [8]byte
+		binary.NativeEndian.PutUint64(one[:], 1)
+		c.txInt.Write(one[:])
+	}
+	return len(b), nil
+}
+
+// Close disconnects from the server and releases the shared memory.
+func (c *memifConn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return net.ErrClosed
+	}
+	c.closed = true
+	msg := newMemifMsg(memifMsgDisconnect)
+	copy(msg[6:], "closed")
+	c.ctrl.SetWriteDeadline(time.Now().Add(time.Second))
+	c.ctrl.Write(msg)
+	return c.release()
+}
+
+func (c *memifConn) release() error {
+	err := c.ctrl.Close()
+	if c.txInt != nil {
+		c.txInt.Close()
+	}
+	if c.rxInt != nil {
+		c.rxInt.Close()
+	}
+	if c.region != nil {
+		syscall.Munmap(c.region)
+	}
+	return err
+}
+
+func (c *memifConn) LocalAddr() net.Addr {
+	return c.local
+}
+
+func (c *memifConn) RemoteAddr() net.Addr {
+	return c.remote
+}
+
+func (c *memifConn) SetDeadline(t time.Time) error {
+	return c.SetReadDeadline(t)
+}
+
+func (c *memifConn) SetReadDeadline(t time.Time) error {
+	c.readDeadline.Store(t)
+	return nil
+}
+
+// SetWriteDeadline has no effect, because Write never blocks.
+func (c *memifConn) SetWriteDeadline(t time.Time) error {
+	return nil
+}
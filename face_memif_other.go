@@ -0,0 +1,9 @@
+//go:build !linux || !(amd64 || arm64)
+
+package ndn
+
+import "net"
+
+func dialMemif(opts MemifOptions) (net.Conn, error) {
+	return nil, ErrNotSupported
+}
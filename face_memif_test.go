@@ -0,0 +1,193 @@
+//go:build linux && (amd64 || arm64)
+
+package ndn
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// testMemifServer accepts one memif client, and echoes count frames.
+func testMemifServer(ln *net.UnixListener, count int) error {
+	ctrl, err := ln.AcceptUnix()
+	if err != nil {
+		return err
+	}
+	defer ctrl.Close()
+
+	msg := newMemifMsg(memifMsgHello)
+	binary.LittleEndian.PutUint16(msg[34:], memifVersion)
+	binary.LittleEndian.PutUint16(msg[36:], memifVersion)
+	msg[44] = 10
+	_, err = ctrl.Write(msg)
+	if err != nil {
+		return err
+	}
+
+	var (
+		region []byte
+		rings  []memifRing
+		ints   []*os.File
+	)
+	defer func() {
+		for _, f := range ints {
+			f.Close()
+		}
+		if region != nil {
+			syscall.Munmap(region)
+		}
+	}()
+	for connected := false; !connected; {
+		oob := make([]byte, syscall.CmsgSpace(4))
+		n, oobn, _, _, err := ctrl.ReadMsgUnix(msg, oob)
+		if err != nil {
+			return err
+		}
+		var fd int
+		if oobn > 0 {
+			cmsgs, err := syscall.ParseSocketControlMessage(oob[:oobn])
+			if err != nil {
+				return err
+			}
+			fds, err := syscall.ParseUnixRights(&cmsgs[0])
+			if err != nil {
+				return err
+			}
+			fd = fds[0]
+		}
+		reply := newMemifMsg(memifMsgAck)
+		switch binary.LittleEndian.Uint16(msg[:n]) {
+		case memifMsgInit:
+			if binary.LittleEndian.Uint32(msg[4:]) != 1 {
+				return ErrMemifHandshake
+			}
+		case memifMsgAddRegion:
+			region, err = syscall.Mmap(fd, 0, int(binary.LittleEndian.Uint64(msg[4:])), syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+			syscall.Close(fd)
+			if err != nil {
+				return err
+			}
+		case memifMsgAddRing:
+			offset := int(binary.LittleEndian.Uint32(msg[8:]))
+			size := 1 << msg[12]
+			rings = append(rings, memifRing{
+				b:    region[offset : offset+memifRingHeaderSize+memifDescSize*size],
+				mask: uint16(size - 1),
+			})
+			ints = append(ints, os.NewFile(uintptr(fd), "eventfd"))
+		case memifMsgConnect:
+			reply = newMemifMsg(memifMsgConnected)
+			connected = true
+		default:
+			return ErrMemifHandshake
+		}
+		_, err = ctrl.Write(reply)
+		if err != nil {
+			return err
+		}
+	}
+
+	// rings[0] is client-to-server, and rings[1] is server-to-client.
+	tx, rx := &rings[1], &rings[0]
+	var txTail, rxTail uint16
+	for i := 0; i < count; {
+		if rxTail == rx.head() {
+			time.Sleep(time.Millisecond)
+			continue
+		}
+		d := rx.desc(rxTail)
+		frame := region[binary.LittleEndian.Uint32(d[8:]):][:binary.LittleEndian.Uint32(d[4:])]
+		rxTail++
+		atomic.StoreUint32(rx.word(64), uint32(rxTail))
+
+		for txTail == tx.head() {
+			time.Sleep(time.Millisecond)
+		}
+		d = tx.desc(txTail)
+		n := copy(region[binary.LittleEndian.Uint32(d[8:]):][:binary.LittleEndian.Uint32(d[4:])], frame)
+		binary.LittleEndian.PutUint32(d[4:], uint32(n))
+		txTail++
+		atomic.StoreUint32(tx.word(64), uint32(txTail))
+		var one [8]byte
+		binary.NativeEndian.PutUint64(one[:], 1)
+		ints[1].Write(one[:])
+		i++
+	}
+	// wait for disconnect
+	_, err = ctrl.Read(msg)
+	if err != nil {
+		return err
+	}
+	if binary.LittleEndian.Uint16(msg) != memifMsgDisconnect {
+		return ErrMemifHandshake
+	}
+	return nil
+}
+
+func TestMemif(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ndn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "memif.sock")
+	ln, err := net.ListenUnix("unixpacket", &net.UnixAddr{Name: path, Net: "unixpacket"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	const count = 10
+	errc := make(chan error, 1)
+	go func() {
+		errc <- testMemifServer(ln, count)
+	}()
+
+	conn, err := dialMemif(MemifOptions{
+		SocketName:   path,
+		ID:           1,
+		Dataroom:     256,
+		RingCapacity: 3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = conn.Write(make([]byte, 256))
+	if err != ErrPacketTooLarge {
+		t.Fatalf("expect %v, got %v", ErrPacketTooLarge, err)
+	}
+	buf := make([]byte, 256)
+	for i := 0; i < count; i++ {
+		msg := bytes.Repeat([]byte{byte(i)}, 10+i)
+		_, err = conn.Write(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n, err := conn.Read(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(buf[:n], msg) {
+			t.Fatalf("expect %v, got %v", msg, buf[:n])
+		}
+	}
+
+	conn.SetReadDeadline(time.Now().Add(20 * time.Millisecond))
+	_, err = conn.Read(buf)
+	if err != os.ErrDeadlineExceeded {
+		t.Fatalf("expect %v, got %v", os.ErrDeadlineExceeded, err)
+	}
+	conn.Close()
+	err = <-errc
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,75 @@
+package ndn
+
+import "net"
+
+// Default NDN UDP multicast groups.
+var (
+	MulticastUDP4 = &net.UDPAddr{IP: net.IPv4(224, 0, 23, 170), Port: 56363}
+	MulticastUDP6 = &net.UDPAddr{IP: net.ParseIP("ff02::1234"), Port: 56363}
+)
+
+// MulticastOptions controls NewMulticastFace.
+type MulticastOptions struct {
+	// Group is the multicast group.
+	// If it is nil, MulticastUDP4 is used.
+	Group *net.UDPAddr
+
+	// Interface is the network interface that joins the group and sends packets.
+	// If it is nil, the system default is used.
+	Interface *net.Interface
+
+	// TTL is the hop limit of outgoing packets.
+	// If it is 0, the system default is used, which keeps packets on the local link.
+	TTL int
+}
+
+// NewMulticastFace joins an NDN UDP multicast group, and exchanges packets
+// with all peers in the group without a forwarder.
+//
+// Outgoing packets are not looped back to the local host.
+// faceOpts are passed to NewDatagramFace.
+func NewMulticastFace(opts MulticastOptions, faceOpts ...FaceOption) (Face, error) {
+	group := opts.Group
+	if group == nil {
+		group = MulticastUDP4
+	}
+	network := "udp4"
+	ipv6 := group.IP.To4() == nil
+	if ipv6 {
+		network = "udp6"
+		if group.Zone == "" && opts.Interface != nil {
+			zoned := *group
+			zoned.Zone = opts.Interface.Name
+			group = &zoned
+		}
+	}
+	conn, err := net.ListenMulticastUDP(network, opts.Interface, group)
+	if err != nil {
+		return nil, err
+	}
+	if opts.TTL != 0 {
+		err = setMulticastTTL(conn, ipv6, opts.TTL)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return NewDatagramFace(&multicastConn{
+		UDPConn: conn,
+		group:   group,
+	}, faceOpts...), nil
+}
+
+// multicastConn sends every datagram to the group.
+type multicastConn struct {
+	*net.UDPConn
+	group *net.UDPAddr
+}
+
+func (c *multicastConn) Write(b []byte) (int, error) {
+	return c.WriteToUDP(b, c.group)
+}
+
+func (c *multicastConn) RemoteAddr() net.Addr {
+	return c.group
+}
@@ -0,0 +1,9 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package ndn
+
+import "net"
+
+func setMulticastTTL(conn *net.UDPConn, ipv6 bool, ttl int) error {
+	return ErrNotSupported
+}
@@ -0,0 +1,115 @@
+package ndn
+
+import (
+	"sync"
+	"time"
+)
+
+// NegativeCacheOptions controls WithNegativeCache.
+type NegativeCacheOptions struct {
+	// TTL is how long a failed name is remembered.
+	// If it is 0, 1 second is used.
+	TTL time.Duration
+
+	// Timeouts is the number of timeouts of a name within TTL, after which
+	// interests with the name time out without being sent.
+	// If it is 0, only NoRoute nacks are cached.
+	Timeouts int
+}
+
+// negativeCache remembers names of recent outgoing interests that fail.
+type negativeCache struct {
+	NegativeCacheOptions
+
+	sync.Mutex
+	entries map[string]*negativeEntry
+	// purged is when expired entries are last removed.
+	purged time.Time
+}
+
+type negativeEntry struct {
+	expire time.Time
+	// noRoute is set if the last interest is nacked with NackReasonNoRoute.
+	noRoute  bool
+	timeouts int
+}
+
+func newNegativeCache(opts NegativeCacheOptions) *negativeCache {
+	if opts.TTL <= 0 {
+		opts.TTL = time.Second
+	}
+	return &negativeCache{
+		NegativeCacheOptions: opts,
+		entries:              make(map[string]*negativeEntry),
+		purged:               time.Now(),
+	}
+}
+
+// lookup returns false if an interest with key should be sent.
+// Otherwise, it returns the error of the interest, which is nil if
+// the interest should time out.
+func (c *negativeCache) lookup(key string) (bool, error) {
+	now := time.Now()
+	c.Lock()
+	defer c.Unlock()
+	ent, ok := c.entries[key]
+	if !ok || now.After(ent.expire) {
+		return false, nil
+	}
+	if ent.noRoute {
+		return true, &NackError{Reason: NackReasonNoRoute}
+	}
+	if c.Timeouts > 0 && ent.timeouts >= c.Timeouts {
+		return true, nil
+	}
+	return false, nil
+}
+
+// update calls f with the unexpired entry of key, and renews it.
+func (c *negativeCache) update(key string, f func(*negativeEntry)) {
+	now := time.Now()
+	c.Lock()
+	defer c.Unlock()
+	if now.Sub(c.purged) > c.TTL {
+		for k, ent := range c.entries {
+			if now.After(ent.expire) {
+				delete(c.entries, k)
+			}
+		}
+		c.purged = now
+	}
+	ent, ok := c.entries[key]
+	if !ok || now.After(ent.expire) {
+		ent = new(negativeEntry)
+		c.entries[key] = ent
+	}
+	f(ent)
+	ent.expire = now.Add(c.TTL)
+}
+
+// nack remembers that an interest with key is nacked with reason.
+func (c *negativeCache) nack(key string, reason uint64) {
+	if reason != NackReasonNoRoute {
+		return
+	}
+	c.update(key, func(ent *negativeEntry) {
+		ent.noRoute = true
+	})
+}
+
+// timeout remembers that an interest with key times out.
+func (c *negativeCache) timeout(key string) {
+	if c.Timeouts <= 0 {
+		return
+	}
+	c.update(key, func(ent *negativeEntry) {
+		ent.timeouts++
+	})
+}
+
+// satisfied forgets key after an interest with it is satisfied.
+func (c *negativeCache) satisfied(key string) {
+	c.Lock()
+	defer c.Unlock()
+	delete(c.entries, key)
+}
@@ -0,0 +1,114 @@
+package ndn
+
+import "log"
+
+// FaceOption configures NewFace and NewDatagramFace.
+type FaceOption func(*face)
+
+// WithInterestQueue sets the incoming interest queue.
+//
+// If it is not set, incoming interests will be ignored.
+// Otherwise, this queue must be handled before it is full, unless
+// WithInterestQueueOptions drops interests, and it is closed after the face is closed.
+func WithInterestQueue(recv chan<- *Interest) FaceOption {
+	return func(f *face) {
+		f.recv = recv
+		f.handler = nil
+	}
+}
+
+// WithInterestQueueOptions sets the overflow policy of the incoming interest queue.
+//
+// If it is not set, OverflowBlock is used.
+func WithInterestQueueOptions(opts InterestQueueOptions) FaceOption {
+	return func(f *face) {
+		f.queueOpts = opts
+	}
+}
+
+// WithDuplicateSuppression drops incoming interests that are duplicates
+// of recent ones before they reach the application.
+//
+// Interests answered from the cache of WithCache are not checked.
+func WithDuplicateSuppression(opts DuplicateOptions) FaceOption {
+	return func(f *face) {
+		f.dedup = newDedupTable(opts)
+	}
+}
+
+// WithNegativeCache fails outgoing interests without sending them, if
+// recent interests with the same name are nacked with NackReasonNoRoute,
+// or time out repeatedly.
+//
+// Failed interests are reported as if they were nacked or timed out again,
+// and counted in FaceStats.SuppressedInterests.
+func WithNegativeCache(opts NegativeCacheOptions) FaceOption {
+	return func(f *face) {
+		f.negative = newNegativeCache(opts)
+	}
+}
+
+// WithLogger logs malformed packets and dropped packets to l.
+func WithLogger(l *log.Logger) FaceOption {
+	return func(f *face) {
+		f.logger = l
+	}
+}
+
+// WithMaxHandlers limits the number of interest handlers that run at the
+// same time, including the ones of Listen, to n.
+// Interests that arrive while n handlers are running are nacked with
+// NackReasonCongestion, and counted in FaceStats.DroppedInterests.
+//
+// If it is not set, at most 256 handlers run. If n is negative, the number is not limited.
+func WithMaxHandlers(n int) FaceOption {
+	return func(f *face) {
+		f.maxHandlers = n
+	}
+}
+
+// WithReadBufferSize buffers reads from a stream transport with n bytes.
+func WithReadBufferSize(n int) FaceOption {
+	return func(f *face) {
+		f.readBufferSize = n
+	}
+}
+
+// WithMTU sets the maximum datagram size of a datagram face.
+// Larger outgoing packets are dropped.
+//
+// If it is not set, the maximum NDN packet size 8800 is used.
+func WithMTU(n int) FaceOption {
+	return func(f *face) {
+		f.mtu = n
+	}
+}
+
+// WithCache uses c as the content store of the face.
+//
+// Sent and retrieved data packets are added to c, except retrieved ones
+// with the NDNLPv2 NoCache policy.
+// Incoming interests are answered from c first, and
+// outgoing interests are satisfied from c without being sent.
+func WithCache(c Cache) FaceOption {
+	return func(f *face) {
+		f.cache = c
+	}
+}
+
+// WithSigner signs data packets without SignatureValue with key before they are sent.
+func WithSigner(key Signer) FaceOption {
+	return func(f *face) {
+		f.key = key
+	}
+}
+
+// WithKeepAlive starts KeepAlive with opts.
+//
+// Reconnection is not an option, because it needs to dial new transports;
+// see NewReconnectFace.
+func WithKeepAlive(opts KeepAliveOptions) FaceOption {
+	return func(f *face) {
+		f.keepAliveOpts = &opts
+	}
+}
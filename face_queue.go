@@ -0,0 +1,116 @@
+package ndn
+
+import "sync"
+
+// OverflowPolicy decides what happens to an incoming interest when
+// the incoming interest queue is full.
+type OverflowPolicy int
+
+// Overflow policies.
+const (
+	// OverflowBlock waits until the queue has room, which stalls the read loop,
+	// including incoming data.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropNewest drops the incoming interest.
+	OverflowDropNewest
+	// OverflowDropOldest drops the oldest queued interest to make room.
+	OverflowDropOldest
+)
+
+// InterestQueueOptions controls WithInterestQueueOptions.
+type InterestQueueOptions struct {
+	Policy OverflowPolicy
+
+	// Size is the number of interests buffered by the face before they are
+	// sent to the queue, in addition to the capacity of the queue.
+	// If it is 0, 64 is used. It is ignored by OverflowBlock.
+	Size int
+
+	// Overflow is invoked with every dropped interest in the read loop.
+	Overflow func(*Interest)
+}
+
+// interestQueue buffers incoming interests without blocking the read loop.
+type interestQueue struct {
+	InterestQueueOptions
+
+	sync.Mutex
+	buf []*Interest
+	// ready has a value after an interest is pushed.
+	ready chan struct{}
+}
+
+func newInterestQueue(opts InterestQueueOptions) *interestQueue {
+	if opts.Size <= 0 {
+		opts.Size = 64
+	}
+	return &interestQueue{
+		InterestQueueOptions: opts,
+		ready:                make(chan struct{}, 1),
+	}
+}
+
+// push adds i, and returns the dropped interest if the buffer is full.
+func (q *interestQueue) push(i *Interest) (dropped *Interest) {
+	q.Lock()
+	if len(q.buf) >= q.Size {
+		if q.Policy == OverflowDropNewest {
+			q.Unlock()
+			return i
+		}
+		dropped = q.buf[0]
+		q.buf[0] = nil
+		q.buf = q.buf[1:]
+	}
+	q.buf = append(q.buf, i)
+	q.Unlock()
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+	return
+}
+
+// pop returns the oldest interest, or nil if the buffer is empty.
+func (q *interestQueue) pop() *Interest {
+	q.Lock()
+	defer q.Unlock()
+	if len(q.buf) == 0 {
+		return nil
+	}
+	i := q.buf[0]
+	q.buf[0] = nil
+	q.buf = q.buf[1:]
+	return i
+}
+
+// enqueueInterest adds i to the buffer, and applies the overflow policy.
+func (f *face) enqueueInterest(i *Interest) {
+	dropped := f.queue.push(i)
+	if dropped == nil {
+		return
+	}
+	f.stats.droppedInterests.Add(1)
+	f.logf("ndn: drop interest %v from %v: queue full", dropped.Name, f.RemoteAddr())
+	if f.queue.Overflow != nil {
+		f.queue.Overflow(dropped)
+	}
+}
+
+// deliverInterests sends buffered interests to the queue until the face starts closing.
+func (f *face) deliverInterests() {
+	for {
+		select {
+		case <-f.queue.ready:
+		case <-f.closing:
+			return
+		}
+		for i := f.queue.pop(); i != nil; i = f.queue.pop() {
+			select {
+			case f.recv <- i:
+			case <-f.closing:
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package ndn
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+
+	"github.com/quic-go/quic-go"
+)
+
+// quicALPN is the application protocol of NDN over QUIC.
+const quicALPN = "ndn"
+
+// quicConn carries packets in one bidirectional stream of a QUIC connection.
+//
+// The stream has the same framing as TCP, because QUIC datagrams are
+// smaller than most NDN packets.
+type quicConn struct {
+	*quic.Stream
+	conn *quic.Conn
+}
+
+func (c *quicConn) LocalAddr() net.Addr {
+	return c.conn.LocalAddr()
+}
+
+func (c *quicConn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+// Close closes the whole connection, not only the stream.
+func (c *quicConn) Close() error {
+	return c.conn.CloseWithError(0, "")
+}
+
+// quicTLSConfig returns a copy of config that negotiates quicALPN.
+func quicTLSConfig(config *tls.Config) *tls.Config {
+	if config == nil {
+		config = new(tls.Config)
+	} else {
+		config = config.Clone()
+	}
+	if len(config.NextProtos) == 0 {
+		config.NextProtos = []string{quicALPN}
+	}
+	return config
+}
+
+// DialQUIC connects to another node listening with ListenQUIC.
+//
+// QUIC always uses TLS; config provides ServerName, RootCAs and optional client
+// certificates. opts are passed to NewFace.
+func DialQUIC(address string, config *tls.Config, opts ...FaceOption) (Face, error) {
+	ctx := context.Background()
+	conn, err := quic.DialAddr(ctx, address, quicTLSConfig(config), nil)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := conn.OpenStreamSync(ctx)
+	if err != nil {
+		conn.CloseWithError(0, "")
+		return nil, err
+	}
+	return NewFace(&quicConn{Stream: stream, conn: conn}, opts...), nil
+}
+
+// QUICListener accepts QUIC connections as faces.
+type QUICListener struct {
+	*quic.Listener
+}
+
+// ListenQUIC listens on a UDP address.
+//
+// config must have at least one server certificate.
+func ListenQUIC(address string, config *tls.Config) (*QUICListener, error) {
+	ln, err := quic.ListenAddr(address, quicTLSConfig(config), nil)
+	if err != nil {
+		return nil, err
+	}
+	return &QUICListener{Listener: ln}, nil
+}
+
+// Accept waits for the next connection, and returns it as a face.
+//
+// A QUIC stream is only announced with its first frame, so Accept
+// returns after the client sends its first packet.
+func (ln *QUICListener) Accept(opts ...FaceOption) (Face, error) {
+	ctx := context.Background()
+	conn, err := ln.Listener.Accept(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := conn.AcceptStream(ctx)
+	if err != nil {
+		conn.CloseWithError(0, "")
+		return nil, err
+	}
+	return NewFace(&quicConn{Stream: stream, conn: conn}, opts...), nil
+}
@@ -0,0 +1,83 @@
+package ndn
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestQUICTLSConfig(t *testing.T) {
+	config := &tls.Config{ServerName: "example.com"}
+	got := quicTLSConfig(config)
+	if len(got.NextProtos) != 1 || got.NextProtos[0] != quicALPN {
+		t.Fatalf("expect %v, got %v", []string{quicALPN}, got.NextProtos)
+	}
+	if got.ServerName != config.ServerName {
+		t.Fatalf("expect %v, got %v", config.ServerName, got.ServerName)
+	}
+	if len(config.NextProtos) != 0 {
+		t.Fatalf("expect config unchanged, got %v", config.NextProtos)
+	}
+
+	got = quicTLSConfig(&tls.Config{NextProtos: []string{"x"}})
+	if len(got.NextProtos) != 1 || got.NextProtos[0] != "x" {
+		t.Fatalf("expect %v, got %v", []string{"x"}, got.NextProtos)
+	}
+}
+
+func newQUICTestCertificate() (tls.Certificate, error) {
+	pri, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"localhost"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &pri.PublicKey, pri)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: pri}, nil
+}
+
+func TestQUIC(t *testing.T) {
+	cert, err := newQUICTestCertificate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ln, err := ListenQUIC("127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		producer, err := ln.Accept(WithInterestHandler(InterestHandlerFunc(func(w Sender, i *Interest) {
+			w.SendData(&Data{Name: i.Name})
+		})))
+		if err != nil {
+			return
+		}
+		<-producer.(DoneFace).Done()
+	}()
+
+	consumer, err := DialQUIC(ln.Addr().String(), &tls.Config{InsecureSkipVerify: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer consumer.Close()
+	name := NewName("/A")
+	d, ok := <-consumer.SendInterest(&Interest{Name: name})
+	if !ok {
+		t.Fatal(ErrTimeout)
+	}
+	if d.Name.Compare(name) != 0 {
+		t.Fatalf("expect %v, got %v", name, d.Name)
+	}
+}
@@ -0,0 +1,312 @@
+package ndn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// FaceState is the connection state of ReconnectFace.
+type FaceState int
+
+// Face states.
+const (
+	FaceUp FaceState = iota
+	FaceDown
+	FaceClosed
+)
+
+func (s FaceState) String() string {
+	switch s {
+	case FaceUp:
+		return "up"
+	case FaceDown:
+		return "down"
+	case FaceClosed:
+		return "closed"
+	}
+	return "unknown"
+}
+
+// ReconnectOptions controls NewReconnectFace.
+type ReconnectOptions struct {
+	// Dial creates the underlying face with opts, e.g. DialNFD.
+	Dial func(opts ...FaceOption) (Face, error)
+
+	// Key signs prefix registration commands.
+	Key Signer
+
+	// MinBackoff and MaxBackoff bound the delay between dial attempts,
+	// which doubles after every failure.
+	// If they are 0, 100ms and 1 minute are used.
+	MinBackoff, MaxBackoff time.Duration
+
+	// StateChanged is invoked when the face goes up or down, or is closed.
+	StateChanged func(FaceState)
+}
+
+// ReconnectFace is a face that re-establishes its transport after the connection drops,
+// and registers all routes again.
+//
+// Interests sent while the face is down are not satisfied.
+type ReconnectFace struct {
+	opts ReconnectOptions
+	recv chan<- *Interest
+
+	mu     sync.Mutex
+	face   Face
+	routes []Parameters
+	closed bool
+}
+
+// NewReconnectFace dials the first connection, and keeps reconnecting until it is closed.
+//
+// recv has the same meaning as NewFace.
+func NewReconnectFace(opts ReconnectOptions, recv chan<- *Interest) (*ReconnectFace, error) {
+	if opts.MinBackoff == 0 {
+		opts.MinBackoff = 100 * time.Millisecond
+	}
+	if opts.MaxBackoff == 0 {
+		opts.MaxBackoff = time.Minute
+	}
+	f := &ReconnectFace{
+		opts: opts,
+		recv: recv,
+	}
+	inner := make(chan *Interest)
+	face, err := opts.Dial(WithInterestQueue(inner))
+	if err != nil {
+		return nil, err
+	}
+	f.face = face
+	go f.serve(inner)
+	return f, nil
+}
+
+// serve forwards incoming interests until the connection drops, and then reconnects.
+func (f *ReconnectFace) serve(inner chan *Interest) {
+	for {
+		for i := range inner {
+			if f.recv != nil {
+				f.recv <- i
+			}
+		}
+		if f.isClosed() {
+			break
+		}
+		f.setState(FaceDown)
+		inner = f.reconnect()
+		if inner == nil {
+			break
+		}
+		f.setState(FaceUp)
+	}
+	if f.recv != nil {
+		close(f.recv)
+	}
+	f.setState(FaceClosed)
+}
+
+func (f *ReconnectFace) isClosed() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.closed
+}
+
+func (f *ReconnectFace) setState(s FaceState) {
+	if f.opts.StateChanged != nil {
+		f.opts.StateChanged(s)
+	}
+}
+
+// reconnect dials with exponential backoff, and registers all routes.
+//
+// It returns nil if the face is closed.
+func (f *ReconnectFace) reconnect() chan *Interest {
+	backoff := f.opts.MinBackoff
+	for {
+		time.Sleep(backoff)
+		if f.isClosed() {
+			return nil
+		}
+		backoff *= 2
+		if backoff > f.opts.MaxBackoff {
+			backoff = f.opts.MaxBackoff
+		}
+
+		inner := make(chan *Interest)
+		face, err := f.opts.Dial(WithInterestQueue(inner))
+		if err != nil {
+			continue
+		}
+		f.mu.Lock()
+		routes := append([]Parameters(nil), f.routes...)
+		f.mu.Unlock()
+		for i := range routes {
+			_, err = Register(face, &routes[i], f.opts.Key)
+			if err != nil {
+				break
+			}
+		}
+		f.mu.Lock()
+		if err == nil && !f.closed {
+			f.face = face
+			f.mu.Unlock()
+			return inner
+		}
+		closed := f.closed
+		f.mu.Unlock()
+		face.Close()
+		// wait for the read loop
+		for range inner {
+		}
+		if closed {
+			return nil
+		}
+	}
+}
+
+func (f *ReconnectFace) current() Face {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.face
+}
+
+// Register adds a route with the package-level Register, and the route is
+// added again after every reconnection.
+func (f *ReconnectFace) Register(params *Parameters) (*Parameters, error) {
+	installed, err := Register(f.current(), params, f.opts.Key)
+	if err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	f.routes = append(f.routes, *params)
+	f.mu.Unlock()
+	return installed, nil
+}
+
+// RegisterAll registers routes to prefixes with default parameters, like Register.
+//
+// Every prefix is tried, and the errors are joined.
+func (f *ReconnectFace) RegisterAll(prefixes []string) error {
+	var errs []error
+	for _, prefix := range prefixes {
+		_, err := f.Register(&Parameters{Name: NewName(prefix)})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", prefix, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// UnregisterAll unregisters all routes registered by Register, and
+// they are not registered again after reconnection.
+func (f *ReconnectFace) UnregisterAll() error {
+	f.mu.Lock()
+	routes := f.routes
+	f.routes = nil
+	f.mu.Unlock()
+	return f.unregisterRoutes(context.Background(), f.current(), routes)
+}
+
+// unregisterRoutes unregisters routes with face until ctx is done, and
+// the errors are joined.
+func (f *ReconnectFace) unregisterRoutes(ctx context.Context, face Face, routes []Parameters) error {
+	var errs []error
+	for i := range routes {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		_, err := Unregister(face, &routes[i], f.opts.Key)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%v: %w", routes[i].Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Unregister unregisters the route to name, which is registered by Register.
+func (f *ReconnectFace) Unregister(name Name) error {
+	params := Parameters{Name: name}
+	f.mu.Lock()
+	for i := range f.routes {
+		if f.routes[i].Name.Compare(name) == 0 {
+			params = f.routes[i]
+			f.routes = append(f.routes[:i], f.routes[i+1:]...)
+			break
+		}
+	}
+	f.mu.Unlock()
+	_, err := Unregister(f.current(), &params, f.opts.Key)
+	return err
+}
+
+// SendInterest implements Sender.
+func (f *ReconnectFace) SendInterest(i *Interest) <-chan *Data {
+	return f.current().SendInterest(i)
+}
+
+// SendInterestContext implements ContextSender.
+func (f *ReconnectFace) SendInterestContext(ctx context.Context, i *Interest) <-chan *Data {
+	return sendInterest(ctx, f.current(), i)
+}
+
+// ExpressInterest implements ResponseSender.
+func (f *ReconnectFace) ExpressInterest(ctx context.Context, i *Interest) *Response {
+	return ExpressInterest(ctx, f.current(), i)
+}
+
+// SendData implements Sender.
+func (f *ReconnectFace) SendData(d *Data) {
+	f.current().SendData(d)
+}
+
+// LocalAddr returns the local address of the current connection.
+func (f *ReconnectFace) LocalAddr() net.Addr {
+	return f.current().LocalAddr()
+}
+
+// RemoteAddr returns the remote address of the current connection.
+func (f *ReconnectFace) RemoteAddr() net.Addr {
+	return f.current().RemoteAddr()
+}
+
+// Close unregisters all routes registered by Register, closes the current connection,
+// and stops reconnecting.
+//
+// Routes are unregistered on a best-effort basis, because the connection may be down.
+func (f *ReconnectFace) Close() error {
+	return f.CloseWithContext(context.Background())
+}
+
+// CloseWithContext is like Close, but stops unregistering routes, and waiting for
+// the current connection when ctx is done, if it implements ContextCloser.
+func (f *ReconnectFace) CloseWithContext(ctx context.Context) error {
+	f.mu.Lock()
+	if f.closed {
+		f.mu.Unlock()
+		return nil
+	}
+	f.closed = true
+	face := f.face
+	routes := f.routes
+	f.routes = nil
+	f.mu.Unlock()
+	f.unregisterRoutes(ctx, face, routes)
+	if cc, ok := face.(ContextCloser); ok {
+		return cc.CloseWithContext(ctx)
+	}
+	return face.Close()
+}
+
+// Stats returns the counters of the current connection.
+func (f *ReconnectFace) Stats() FaceStats {
+	if sf, ok := f.current().(StatsFace); ok {
+		return sf.Stats()
+	}
+	return FaceStats{}
+}
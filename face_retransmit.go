@@ -0,0 +1,80 @@
+package ndn
+
+import (
+	"context"
+	"time"
+)
+
+// RetransmitOptions controls NewRetransmittingFace.
+type RetransmitOptions struct {
+	// MaxRetries is the number of retransmissions after the first attempt.
+	MaxRetries int
+
+	// Timeout is the lifetime of the first attempt.
+	// If it is 0, 1 second is used.
+	Timeout time.Duration
+
+	// Backoff multiplies the lifetime after every attempt.
+	// If it is 0, 2 is used.
+	Backoff float64
+}
+
+type retransmittingFace struct {
+	Face
+	RetransmitOptions
+}
+
+// NewRetransmittingFace creates a face that retransmits interests, so
+// a lost packet costs one attempt instead of the whole interest lifetime.
+//
+// Every attempt has a new nonce, and its LifeTime is replaced by the timeout of the attempt,
+// so the forwarder and f do not aggregate it with the previous attempt.
+func NewRetransmittingFace(f Face, opts RetransmitOptions) Face {
+	if opts.Timeout == 0 {
+		opts.Timeout = time.Second
+	}
+	if opts.Backoff == 0 {
+		opts.Backoff = 2
+	}
+	return &retransmittingFace{
+		Face:              f,
+		RetransmitOptions: opts,
+	}
+}
+
+func (f *retransmittingFace) SendInterest(i *Interest) <-chan *Data {
+	return f.SendInterestContext(context.Background(), i)
+}
+
+func (f *retransmittingFace) SendInterestContext(ctx context.Context, i *Interest) <-chan *Data {
+	return f.ExpressInterest(ctx, i).ch
+}
+
+// ExpressInterest retransmits only after timeouts. It stops after a nack,
+// or any other error, such as ErrFaceClosed, and reports that error.
+func (f *retransmittingFace) ExpressInterest(ctx context.Context, i *Interest) *Response {
+	resp, ch := newResponse(ctx)
+	go func() {
+		defer close(ch)
+		timeout := f.Timeout
+		for n := 0; n <= f.MaxRetries; n++ {
+			attempt := *i
+			if n > 0 {
+				// a new nonce is populated.
+				attempt.Nonce = 0
+			}
+			attempt.LifeTime = uint64(timeout / time.Millisecond)
+			pending := ExpressInterest(ctx, f.Face, &attempt)
+			if d := pending.Data(); d != nil {
+				ch <- d
+				return
+			}
+			if err := pending.Err(); err != ErrTimeout {
+				resp.err = err
+				return
+			}
+			timeout = time.Duration(float64(timeout) * f.Backoff)
+		}
+	}()
+	return resp
+}
@@ -0,0 +1,85 @@
+package ndn
+
+import (
+	"io"
+	"sync/atomic"
+)
+
+// FaceStats is a snapshot of face counters.
+type FaceStats struct {
+	InInterests  uint64
+	InData       uint64
+	InNacks      uint64
+	OutInterests uint64
+	OutData      uint64
+	OutNacks     uint64
+	InBytes      uint64
+	OutBytes     uint64
+
+	// Timeouts counts pending interests that expire without data or nack.
+	Timeouts uint64
+	// DecodeErrors counts malformed or unsupported packets.
+	DecodeErrors uint64
+	// DroppedInterests counts incoming interests dropped by the overflow policy,
+	// or by the handler limit of WithMaxHandlers.
+	DroppedInterests uint64
+	// DuplicateInterests counts incoming interests suppressed as duplicates.
+	DuplicateInterests uint64
+	// SuppressedInterests counts outgoing interests failed by the negative cache.
+	SuppressedInterests uint64
+	// PendingInterests is the number of pending interests.
+	PendingInterests int
+}
+
+// StatsFace is a face that counts packets, such as faces created by NewFace.
+type StatsFace interface {
+	Face
+	Stats() FaceStats
+}
+
+type faceCounters struct {
+	inInterests, inData, inNacks atomic.Uint64
+	outInterests, outData        atomic.Uint64
+	outNacks                     atomic.Uint64
+	inBytes, outBytes            atomic.Uint64
+	timeouts, decodeErrors       atomic.Uint64
+	droppedInterests             atomic.Uint64
+	duplicateInterests           atomic.Uint64
+	suppressedInterests          atomic.Uint64
+}
+
+// countingReader counts bytes read from a stream transport.
+type countingReader struct {
+	io.Reader
+	n *atomic.Uint64
+}
+
+func (r countingReader) Read(b []byte) (int, error) {
+	n, err := r.Reader.Read(b)
+	r.n.Add(uint64(n))
+	return n, err
+}
+
+func (f *face) Stats() FaceStats {
+	var pending int
+	f.pit.visit(func(m map[chan<- *Data]pitEntry) map[chan<- *Data]pitEntry {
+		pending += len(m)
+		return m
+	})
+	return FaceStats{
+		InInterests:         f.stats.inInterests.Load(),
+		InData:              f.stats.inData.Load(),
+		InNacks:             f.stats.inNacks.Load(),
+		OutInterests:        f.stats.outInterests.Load(),
+		OutData:             f.stats.outData.Load(),
+		OutNacks:            f.stats.outNacks.Load(),
+		InBytes:             f.stats.inBytes.Load(),
+		OutBytes:            f.stats.outBytes.Load(),
+		Timeouts:            f.stats.timeouts.Load(),
+		DecodeErrors:        f.stats.decodeErrors.Load(),
+		DroppedInterests:    f.stats.droppedInterests.Load(),
+		DuplicateInterests:  f.stats.duplicateInterests.Load(),
+		SuppressedInterests: f.stats.suppressedInterests.Load(),
+		PendingInterests:    pending,
+	}
+}
@@ -2,13 +2,22 @@ package ndn
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
 	"math/rand"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/go-ndn/packet"
+	"github.com/go-ndn/tlv"
 )
 
 type testFace struct {
@@ -21,7 +30,7 @@ func newTestFace(address string) (*testFace, error) {
 		return nil, err
 	}
 	return &testFace{
-		Face: NewFace(conn, nil),
+		Face: NewFace(conn),
 	}, nil
 }
 
@@ -41,7 +50,7 @@ func newProducer(address, name string) (Face, error) {
 		return nil, err
 	}
 	recv := make(chan *Interest)
-	f := NewFace(conn, recv)
+	f := NewFace(conn, WithInterestQueue(recv))
 	err = SendControl(f, "rib", "register", &Parameters{
 		Name: NewName(name),
 	}, rsaKey)
@@ -179,3 +188,1079 @@ func BenchmarkForwardRTT(b *testing.B) {
 		}
 	}
 }
+
+// newUDPPipe creates two connected UDP sockets on loopback.
+func newUDPPipe() (net.Conn, net.Conn, error) {
+	var addrs [2]*net.UDPAddr
+	for i := range addrs {
+		conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+		if err != nil {
+			return nil, nil, err
+		}
+		addrs[i] = conn.LocalAddr().(*net.UDPAddr)
+		conn.Close()
+	}
+	c1, err := net.DialUDP("udp", addrs[0], addrs[1])
+	if err != nil {
+		return nil, nil, err
+	}
+	c2, err := net.DialUDP("udp", addrs[1], addrs[0])
+	if err != nil {
+		c1.Close()
+		return nil, nil, err
+	}
+	return c1, c2, nil
+}
+
+func TestDatagramFace(t *testing.T) {
+	c1, c2, err := newUDPPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	recv := make(chan *Interest)
+	producer := NewDatagramFace(c2, WithInterestQueue(recv))
+	defer producer.Close()
+	consumer := NewDatagramFace(c1)
+	defer consumer.Close()
+
+	content := bytes.Repeat([]byte("0123456789"), 500)
+	go func() {
+		for i := range recv {
+			producer.SendData(&Data{
+				Name:    i.Name,
+				Content: content,
+				SignatureInfo: SignatureInfo{
+					SignatureType: SignatureTypeDigestCRC32C,
+				},
+			})
+		}
+	}()
+
+	// a malformed datagram does not break later packets
+	_, err = c2.Write([]byte{6, 253, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		d, ok := <-consumer.SendInterest(&Interest{
+			Name: NewName(fmt.Sprintf("/A/%d", i)),
+		})
+		if !ok {
+			t.Fatal(ErrTimeout)
+		}
+		if !bytes.Equal(d.Content, content) {
+			t.Fatalf("expect %d bytes, got %d", len(content), len(d.Content))
+		}
+	}
+}
+
+func TestFaceConcurrent(t *testing.T) {
+	c1, c2 := net.Pipe()
+	recv := make(chan *Interest)
+	producer := NewFace(c2, WithInterestQueue(recv))
+	defer producer.Close()
+	consumer := NewFace(c1)
+	defer consumer.Close()
+
+	go func() {
+		for i := range recv {
+			go producer.SendData(&Data{Name: i.Name})
+		}
+	}()
+
+	var wg sync.WaitGroup
+	errc := make(chan error, 100)
+	for n := 0; n < 100; n++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			name := NewName(fmt.Sprintf("/A/%d", n))
+			d, ok := <-consumer.SendInterest(&Interest{Name: name})
+			if !ok {
+				errc <- ErrTimeout
+				return
+			}
+			if d.Name.Compare(name) != 0 {
+				errc <- fmt.Errorf("expect %v, got %v", name, d.Name)
+			}
+		}(n)
+	}
+	wg.Wait()
+	close(errc)
+	for err := range errc {
+		t.Fatal(err)
+	}
+}
+
+func TestMulticastFace(t *testing.T) {
+	f, err := NewMulticastFace(MulticastOptions{TTL: 1})
+	if err != nil {
+		t.Skip(err)
+	}
+	defer f.Close()
+	if f.RemoteAddr().String() != MulticastUDP4.String() {
+		t.Fatalf("expect %v, got %v", MulticastUDP4, f.RemoteAddr())
+	}
+}
+
+func TestEtherFace(t *testing.T) {
+	ifi, err := net.InterfaceByName("lo")
+	if err != nil {
+		t.Skip(err)
+	}
+	f, err := NewEtherFace(ifi, nil)
+	if err != nil {
+		t.Skip(err)
+	}
+	defer f.Close()
+	if f.RemoteAddr().String() != MulticastEther.String() {
+		t.Fatalf("expect %v, got %v", MulticastEther, f.RemoteAddr())
+	}
+}
+
+func TestDialNFD(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ndn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "nfd.sock")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	t.Setenv(nfdTransportEnv, "unix://"+path)
+	f, err := DialNFD()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+
+	t.Setenv(nfdTransportEnv, "")
+	paths := NFDSocketPaths
+	defer func() {
+		NFDSocketPaths = paths
+	}()
+	NFDSocketPaths = []string{filepath.Join(dir, "missing.sock"), path}
+	found, err := NFDSocketPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found != path {
+		t.Fatalf("expect %s, got %s", path, found)
+	}
+}
+
+func TestReconnectFace(t *testing.T) {
+	conns := make(chan net.Conn, 2)
+	states := make(chan FaceState, 3)
+	f, err := NewReconnectFace(ReconnectOptions{
+		Dial: func(opts ...FaceOption) (Face, error) {
+			c1, c2 := net.Pipe()
+			conns <- c2
+			return NewFace(c1, opts...), nil
+		},
+		MinBackoff: time.Millisecond,
+		StateChanged: func(s FaceState) {
+			states <- s
+		},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the connection drops
+	(<-conns).Close()
+	for _, want := range []FaceState{FaceDown, FaceUp} {
+		if s := <-states; s != want {
+			t.Fatalf("expect %v, got %v", want, s)
+		}
+	}
+	<-conns
+	f.Close()
+	if s := <-states; s != FaceClosed {
+		t.Fatalf("expect %v, got %v", FaceClosed, s)
+	}
+}
+
+func TestSendInterestContext(t *testing.T) {
+	c1, c2 := net.Pipe()
+	go io.Copy(ioutil.Discard, c2)
+	f := NewFace(c1)
+	defer f.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pending := f.(ContextSender).SendInterestContext(ctx, &Interest{Name: NewName("/A")})
+	cancel()
+	select {
+	case _, ok := <-pending:
+		if ok {
+			t.Fatal("expect no data")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expect pending interest to be removed")
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := SendInterestContext(ctx, f, &Interest{Name: NewName("/A")})
+	if err != context.DeadlineExceeded {
+		t.Fatalf("expect %v, got %v", context.DeadlineExceeded, err)
+	}
+}
+
+type lossyFace struct {
+	Face
+	loss      int
+	attempts  []Interest
+	attemptsm sync.Mutex
+}
+
+func (f *lossyFace) SendInterest(i *Interest) <-chan *Data {
+	f.attemptsm.Lock()
+	f.attempts = append(f.attempts, *i)
+	n := len(f.attempts)
+	f.attemptsm.Unlock()
+
+	ch := make(chan *Data, 1)
+	if n > f.loss {
+		ch <- &Data{Name: i.Name}
+	}
+	close(ch)
+	return ch
+}
+
+func TestRetransmittingFace(t *testing.T) {
+	for _, test := range []struct {
+		loss, retries int
+		ok            bool
+	}{
+		{0, 0, true},
+		{2, 2, true},
+		{3, 2, false},
+	} {
+		lossy := &lossyFace{loss: test.loss}
+		f := NewRetransmittingFace(lossy, RetransmitOptions{
+			MaxRetries: test.retries,
+			Timeout:    100 * time.Millisecond,
+		})
+		_, ok := <-f.SendInterest(&Interest{Name: NewName("/A"), Nonce: 1})
+		if ok != test.ok {
+			t.Fatalf("expect %v, got %v", test.ok, ok)
+		}
+		want := test.loss + 1
+		if want > test.retries+1 {
+			want = test.retries + 1
+		}
+		if len(lossy.attempts) != want {
+			t.Fatalf("expect %d attempts, got %d", want, len(lossy.attempts))
+		}
+		lifeTime := uint64(100)
+		for n, i := range lossy.attempts {
+			if i.LifeTime != lifeTime {
+				t.Fatalf("expect %d, got %d", lifeTime, i.LifeTime)
+			}
+			lifeTime *= 2
+			if n > 0 && i.Nonce != 0 {
+				t.Fatalf("expect new nonce, got %d", i.Nonce)
+			}
+		}
+	}
+}
+
+func TestRetransmittingFaceClosed(t *testing.T) {
+	c1, _ := net.Pipe()
+	closed := NewFace(c1)
+	closed.Close()
+	f := NewRetransmittingFace(closed, RetransmitOptions{
+		MaxRetries: 3,
+		Timeout:    time.Second,
+	})
+	err := f.(ResponseSender).ExpressInterest(context.Background(), &Interest{Name: NewName("/A")}).Err()
+	if err != ErrFaceClosed {
+		t.Fatalf("expect %v, got %v", ErrFaceClosed, err)
+	}
+}
+
+func TestExpressInterest(t *testing.T) {
+	c1, c2 := net.Pipe()
+	go io.Copy(ioutil.Discard, c2)
+	f := NewFace(c1)
+	defer f.Close()
+
+	i := &Interest{Name: NewName("/A")}
+	r1 := ExpressInterest(context.Background(), f, i)
+	// aggregated
+	r2 := ExpressInterest(context.Background(), f, &Interest{Name: NewName("/A")})
+	f.(*face).recvNack(&Interest{Name: NewName("/A"), Nonce: i.Nonce}, NackReasonNoRoute)
+	for _, r := range []*Response{r1, r2} {
+		reason, ok := r.Nack()
+		if !ok || reason != NackReasonNoRoute {
+			t.Fatalf("expect nack %d, got %v", NackReasonNoRoute, r.Err())
+		}
+		if r.Data() != nil {
+			t.Fatal("expect no data")
+		}
+	}
+
+	r := ExpressInterest(context.Background(), f, &Interest{Name: NewName("/B"), LifeTime: 10})
+	if r.Err() != ErrTimeout {
+		t.Fatalf("expect %v, got %v", ErrTimeout, r.Err())
+	}
+	if _, ok := r.Nack(); ok {
+		t.Fatal("expect no nack")
+	}
+}
+
+func TestFaceClose(t *testing.T) {
+	c1, c2 := net.Pipe()
+	go io.Copy(ioutil.Discard, c2)
+	recv := make(chan *Interest)
+	f := NewFace(c1, WithInterestQueue(recv))
+
+	r := ExpressInterest(context.Background(), f, &Interest{Name: NewName("/A")})
+	err := f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// pending interests are rejected without waiting for lifetime
+	if r.Err() != ErrFaceClosed {
+		t.Fatalf("expect %v, got %v", ErrFaceClosed, r.Err())
+	}
+	if _, ok := <-recv; ok {
+		t.Fatal("expect interest queue closed")
+	}
+	if pending := f.(StatsFace).Stats().PendingInterests; pending != 0 {
+		t.Fatalf("expect no pending interest, got %d", pending)
+	}
+	r = ExpressInterest(context.Background(), f, &Interest{Name: NewName("/A")})
+	if r.Err() != ErrFaceClosed {
+		t.Fatalf("expect %v, got %v", ErrFaceClosed, r.Err())
+	}
+	err = KeepAlive(f, KeepAliveOptions{Interval: time.Second})
+	if err != ErrFaceClosed {
+		t.Fatalf("expect %v, got %v", ErrFaceClosed, err)
+	}
+	err = f.(ContextCloser).CloseWithContext(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFaceErr(t *testing.T) {
+	for _, test := range []struct {
+		stop func(f Face, peer net.Conn)
+		err  func(error) bool
+	}{
+		{
+			stop: func(f Face, _ net.Conn) { f.Close() },
+			err:  func(err error) bool { return err == ErrFaceClosed },
+		},
+		{
+			stop: func(_ Face, peer net.Conn) { peer.Close() },
+			err:  func(err error) bool { return err == io.EOF },
+		},
+		{
+			stop: func(_ Face, peer net.Conn) { peer.Write([]byte{200, 0}) },
+			err: func(err error) bool {
+				pe, ok := err.(*PacketError)
+				return ok && pe.Type == 200 && pe.Err == ErrNotSupported
+			},
+		},
+	} {
+		c1, c2 := net.Pipe()
+		f := NewFace(c1).(DoneFace)
+		if f.Err() != nil {
+			t.Fatalf("expect nil, got %v", f.Err())
+		}
+		test.stop(f, c2)
+		<-f.Done()
+		if !test.err(f.Err()) {
+			t.Fatalf("unexpected error %v", f.Err())
+		}
+		c2.Close()
+	}
+}
+
+func TestFaceStats(t *testing.T) {
+	c1, c2, err := newUDPPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c2.Close()
+	f := NewDatagramFace(c1).(StatsFace)
+	defer f.Close()
+
+	pending := f.SendInterest(&Interest{Name: NewName("/A"), LifeTime: 50})
+	stats := f.Stats()
+	if stats.OutInterests != 1 || stats.PendingInterests != 1 {
+		t.Fatalf("expect 1 pending interest, got %+v", stats)
+	}
+	<-pending
+	// unknown packet type
+	_, err = c2.Write([]byte{200, 1, 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	stats = f.Stats()
+	if stats.Timeouts != 1 || stats.PendingInterests != 0 {
+		t.Fatalf("expect 1 timeout, got %+v", stats)
+	}
+	if stats.DecodeErrors != 1 || stats.InBytes != 3 {
+		t.Fatalf("expect 1 decode error in 3 bytes, got %+v", stats)
+	}
+}
+
+func TestKeepAlive(t *testing.T) {
+	c1, c2 := net.Pipe()
+	defer c2.Close()
+	recv := make(chan *Interest)
+	f := NewFace(c1, WithInterestQueue(recv))
+	err := KeepAlive(f, KeepAliveOptions{
+		Interval: 10 * time.Millisecond,
+		Timeout:  50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the peer reads but never sends
+	buf := make([]byte, len(lpIdle))
+	_, err = io.ReadFull(c2, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf, lpIdle) {
+		t.Fatalf("expect %v, got %v", lpIdle, buf)
+	}
+	go io.Copy(ioutil.Discard, c2)
+	select {
+	case <-recv:
+	case <-time.After(time.Second):
+		t.Fatal("expect face to be closed")
+	}
+	<-f.(DoneFace).Done()
+	if f.(DoneFace).Err() != ErrTimeout {
+		t.Fatalf("expect %v, got %v", ErrTimeout, f.(DoneFace).Err())
+	}
+}
+
+func TestFaceOptions(t *testing.T) {
+	var logs bytes.Buffer
+	c1, c2, err := newUDPPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c2.Close()
+	cache := NewCache(10)
+	recv := make(chan *Interest, 1)
+	f := NewDatagramFace(c1,
+		WithInterestQueue(recv),
+		WithLogger(log.New(&logs, "", 0)),
+		WithMTU(100),
+		WithCache(cache),
+	)
+	defer f.Close()
+
+	// too large for mtu
+	f.SendData(&Data{Name: NewName("/A"), Content: make([]byte, 200)})
+	if !strings.Contains(logs.String(), ErrPacketTooLarge.Error()) {
+		t.Fatalf("expect mtu log, got %q", logs.String())
+	}
+
+	// answered from cache without sending
+	d := &Data{Name: NewName("/B")}
+	cache.Add(d)
+	got := ExpressInterest(context.Background(), f, &Interest{Name: NewName("/B")}).Data()
+	if got != d {
+		t.Fatalf("expect %v, got %v", d, got)
+	}
+	if out := f.(StatsFace).Stats().OutInterests; out != 0 {
+		t.Fatalf("expect no outgoing interest, got %d", out)
+	}
+}
+
+func TestDial(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ndn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "nfd.sock")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	f, err := Dial("unix://" + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+
+	pc, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pc.Close()
+	f, err = Dial("udp4://" + pc.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if f.RemoteAddr().String() != pc.LocalAddr().String() {
+		t.Fatalf("expect %v, got %v", pc.LocalAddr(), f.RemoteAddr())
+	}
+
+	for _, test := range []struct {
+		uri string
+		err error
+	}{
+		{"udp:///", ErrInvalidFaceURI},
+		{"tcp://localhost/path", ErrInvalidFaceURI},
+		{"ether://[01:00:5e:00:17:aa]", ErrNotSupported},
+	} {
+		_, err := Dial(test.uri)
+		if err != test.err {
+			t.Fatalf("%s: expect %v, got %v", test.uri, test.err, err)
+		}
+	}
+}
+
+func TestInterestHandler(t *testing.T) {
+	c1, c2 := net.Pipe()
+	producer := NewFace(c2, WithInterestHandler(InterestHandlerFunc(func(w Sender, i *Interest) {
+		w.SendData(&Data{Name: i.Name})
+	})))
+	defer producer.Close()
+	consumer := NewFace(c1)
+	defer consumer.Close()
+
+	name := NewName("/A/B")
+	d, ok := <-consumer.SendInterest(&Interest{Name: name})
+	if !ok {
+		t.Fatal(ErrTimeout)
+	}
+	if d.Name.Compare(name) != 0 {
+		t.Fatalf("expect %v, got %v", name, d.Name)
+	}
+}
+
+func TestMaxHandlers(t *testing.T) {
+	c1, c2 := net.Pipe()
+	running := make(chan struct{})
+	release := make(chan struct{})
+	producer := NewFace(c2,
+		WithMaxHandlers(1),
+		WithInterestHandler(InterestHandlerFunc(func(w Sender, i *Interest) {
+			running <- struct{}{}
+			<-release
+			w.SendData(&Data{Name: i.Name})
+		})),
+	)
+	defer producer.Close()
+	consumer := NewFace(c1)
+	defer consumer.Close()
+
+	ch := consumer.SendInterest(&Interest{Name: NewName("/A/1")})
+	<-running
+	_, err := SendInterestContext(context.Background(), consumer, &Interest{Name: NewName("/A/2")})
+	if nack, ok := err.(*NackError); !ok || nack.Reason != NackReasonCongestion {
+		t.Fatalf("expect %v, got %v", &NackError{Reason: NackReasonCongestion}, err)
+	}
+	if n := producer.(StatsFace).Stats().DroppedInterests; n != 1 {
+		t.Fatalf("expect 1, got %v", n)
+	}
+	close(release)
+	if _, ok := <-ch; !ok {
+		t.Fatal(ErrTimeout)
+	}
+
+	// the slot is released after the handler returns
+	go func() {
+		for range running {
+		}
+	}()
+	defer close(running)
+	for n := 0; ; n++ {
+		_, err = SendInterestContext(context.Background(), consumer, &Interest{Name: NewName(fmt.Sprintf("/A/3/%d", n))})
+		if err == nil {
+			break
+		}
+		if n == 100 {
+			t.Fatal(err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestListen(t *testing.T) {
+	c1, c2 := net.Pipe()
+	recv := make(chan *Interest, 1)
+	producer := NewFace(c2, WithInterestQueue(recv)).(ListenFace)
+	defer producer.Close()
+	consumer := NewFace(c1)
+	defer consumer.Close()
+
+	reply := func(content string) InterestHandler {
+		return InterestHandlerFunc(func(w Sender, i *Interest) {
+			w.SendData(&Data{Name: i.Name, Content: []byte(content)})
+		})
+	}
+	stopA, err := producer.Listen(NewName("/A"), reply("A"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = producer.Listen(NewName("/A/B"), reply("AB"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = producer.Listen(NewName("/A"), reply("A"))
+	if err != ErrPrefixListened {
+		t.Fatalf("expect %v, got %v", ErrPrefixListened, err)
+	}
+
+	for _, test := range []struct {
+		name, want string
+	}{
+		{"/A", "A"},
+		{"/A/C", "A"},
+		{"/A/B/C", "AB"},
+	} {
+		d, ok := <-consumer.SendInterest(&Interest{Name: NewName(test.name)})
+		if !ok {
+			t.Fatalf("%s: %v", test.name, ErrTimeout)
+		}
+		if string(d.Content) != test.want {
+			t.Fatalf("%s: expect %s, got %s", test.name, test.want, d.Content)
+		}
+	}
+
+	stopA()
+	consumer.SendInterest(&Interest{Name: NewName("/A/C")})
+	i := <-recv
+	if i.Name.String() != "/A/C" {
+		t.Fatalf("expect /A/C, got %v", i.Name)
+	}
+}
+
+func TestInterestQueueOverflow(t *testing.T) {
+	c1, c2 := net.Pipe()
+	recv := make(chan *Interest)
+	overflow := make(chan *Interest, 3)
+	producer := NewFace(c2,
+		WithInterestQueue(recv),
+		WithInterestQueueOptions(InterestQueueOptions{
+			Policy:   OverflowDropOldest,
+			Size:     1,
+			Overflow: func(i *Interest) { overflow <- i },
+		}),
+	)
+	defer producer.Close()
+	consumer := NewFace(c1)
+	defer consumer.Close()
+
+	// the read loop is not blocked by the full queue.
+	for n := 1; n <= 3; n++ {
+		consumer.SendInterest(&Interest{Name: NewName(fmt.Sprintf("/A/%d", n))})
+	}
+	dropped := <-overflow
+	if dropped.Name.String() == "/A/3" {
+		t.Fatal("expect the oldest interest dropped")
+	}
+	for i := range recv {
+		if i.Name.String() == "/A/3" {
+			break
+		}
+	}
+	if n := producer.(StatsFace).Stats().DroppedInterests; n == 0 {
+		t.Fatal("expect dropped interests")
+	}
+}
+
+func TestListener(t *testing.T) {
+	for _, scheme := range []string{"tcp", "ws"} {
+		ln, err := Listen(scheme + "://127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		go ln.Serve(InterestHandlerFunc(func(w Sender, i *Interest) {
+			w.SendData(&Data{Name: i.Name})
+		}))
+
+		f, err := Dial(scheme + "://" + ln.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		name := NewName("/A")
+		d, ok := <-f.SendInterest(&Interest{Name: name})
+		if !ok {
+			t.Fatalf("%s: %v", scheme, ErrTimeout)
+		}
+		if d.Name.Compare(name) != 0 {
+			t.Fatalf("expect %v, got %v", name, d.Name)
+		}
+		// served faces are closed with the listener
+		ln.Close()
+		<-f.(DoneFace).Done()
+	}
+}
+
+func TestListenUDP(t *testing.T) {
+	ln, err := ListenUDP("udp4", "127.0.0.1:0", 100*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go ln.Serve(InterestHandlerFunc(func(w Sender, i *Interest) {
+		w.SendData(&Data{Name: i.Name})
+	}))
+
+	// one socket serves every peer
+	for n := 0; n < 2; n++ {
+		f, err := Dial("udp4://" + ln.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		name := NewName(fmt.Sprintf("/A/%d", n))
+		d, ok := <-f.SendInterest(&Interest{Name: name})
+		if !ok {
+			t.Fatal(ErrTimeout)
+		}
+		if d.Name.Compare(name) != 0 {
+			t.Fatalf("expect %v, got %v", name, d.Name)
+		}
+	}
+
+	// idle faces expire
+	deadline := time.Now().Add(5 * time.Second)
+	for len(ln.Faces()) != 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expect no face, got %d", len(ln.Faces()))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestTransportFace(t *testing.T) {
+	// a byte stream without net.Conn
+	r1, w1 := io.Pipe()
+	r2, w2 := io.Pipe()
+	type readWriteCloser struct {
+		io.Reader
+		io.WriteCloser
+	}
+	producer := NewTransportFace(NewStreamTransport(readWriteCloser{r1, w2}),
+		WithInterestHandler(InterestHandlerFunc(func(w Sender, i *Interest) {
+			w.SendData(&Data{Name: i.Name})
+		})))
+	defer producer.Close()
+	consumer := NewTransportFace(NewStreamTransport(readWriteCloser{r2, w1}))
+	defer consumer.Close()
+	name := NewName("/A")
+	d, ok := <-consumer.SendInterest(&Interest{Name: name})
+	if !ok {
+		t.Fatal(ErrTimeout)
+	}
+	if d.Name.Compare(name) != 0 {
+		t.Fatalf("expect %v, got %v", name, d.Name)
+	}
+
+	// unconnected sockets
+	var conns []net.PacketConn
+	for n := 0; n < 2; n++ {
+		conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		conns = append(conns, conn)
+	}
+	producer = NewTransportFace(NewPacketTransport(conns[0], conns[1].LocalAddr()),
+		WithInterestHandler(InterestHandlerFunc(func(w Sender, i *Interest) {
+			w.SendData(&Data{Name: i.Name})
+		})))
+	defer producer.Close()
+	consumer = NewTransportFace(NewPacketTransport(conns[1], conns[0].LocalAddr()))
+	defer consumer.Close()
+	d, ok = <-consumer.SendInterest(&Interest{Name: name})
+	if !ok {
+		t.Fatal(ErrTimeout)
+	}
+	if d.Name.Compare(name) != 0 {
+		t.Fatalf("expect %v, got %v", name, d.Name)
+	}
+}
+
+func TestDialFirst(t *testing.T) {
+	// the IPv6 address blackholes, and the IPv4 address answers probes.
+	pc, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pc.Close()
+	go func() {
+		buf := make([]byte, maxPacketSize)
+		for {
+			n, addr, err := pc.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			pc.WriteTo(buf[:n], addr)
+		}
+	}()
+	blackhole, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer blackhole.Close()
+
+	addrs := interleaveAddrs([]string{blackhole.LocalAddr().String()}, []string{pc.LocalAddr().String()})
+	start := time.Now()
+	conn, err := dialFirst(context.Background(), addrs, probeUDP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if conn.RemoteAddr().String() != pc.LocalAddr().String() {
+		t.Fatalf("expect %v, got %v", pc.LocalAddr(), conn.RemoteAddr())
+	}
+	if d := time.Since(start); d >= probeTimeout {
+		t.Fatalf("expect the second address before probe timeout, got %v", d)
+	}
+
+	_, err = dialFirst(context.Background(), []string{"a", "b"}, func(context.Context, string) (net.Conn, error) {
+		return nil, ErrTimeout
+	})
+	if err != ErrTimeout {
+		t.Fatalf("expect %v, got %v", ErrTimeout, err)
+	}
+}
+
+func TestDuplicateSuppression(t *testing.T) {
+	c1, c2 := net.Pipe()
+	defer c1.Close()
+	recv := make(chan *Interest, 3)
+	f := NewFace(c2,
+		WithInterestQueue(recv),
+		WithDuplicateSuppression(DuplicateOptions{}),
+	)
+	defer f.Close()
+
+	w := tlv.NewWriter(c1)
+	for _, nonce := range []uint64{1, 1, 2} {
+		err := (&Interest{Name: NewName("/A"), Nonce: nonce}).WriteTo(w)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, want := range []uint64{1, 2} {
+		if i := <-recv; i.Nonce != want {
+			t.Fatalf("expect nonce %d, got %d", want, i.Nonce)
+		}
+	}
+	if n := f.(StatsFace).Stats().DuplicateInterests; n != 1 {
+		t.Fatalf("expect 1 duplicate, got %d", n)
+	}
+
+	byName := newDedupTable(DuplicateOptions{ByName: true, Window: time.Hour})
+	for _, test := range []struct {
+		name string
+		want bool
+	}{
+		{"/A", false},
+		{"/A", true},
+		{"/B", false},
+	} {
+		i := &Interest{Name: NewName(test.name), Nonce: uint64(rand.Uint32())}
+		if got := byName.duplicate(i); got != test.want {
+			t.Fatalf("%s: expect %v, got %v", test.name, test.want, got)
+		}
+	}
+}
+
+func TestPendingInterestMatch(t *testing.T) {
+	c1, c2 := net.Pipe()
+	producer := NewFace(c2, WithInterestHandler(InterestHandlerFunc(func(w Sender, i *Interest) {
+		// stale data with the same name
+		w.SendData(&Data{Name: i.Name})
+	})))
+	defer producer.Close()
+	consumer := NewFace(c1)
+	defer consumer.Close()
+
+	r := ExpressInterest(context.Background(), consumer, &Interest{
+		Name: NewName("/A"),
+		Selectors: Selectors{
+			MustBeFresh: true,
+		},
+		LifeTime: 100,
+	})
+	if r.Err() != ErrTimeout {
+		t.Fatalf("expect %v, got %v", ErrTimeout, r.Err())
+	}
+	name := NewName("/A")
+	name.ImplicitDigestSHA256 = make([]byte, 32)
+	r = ExpressInterest(context.Background(), consumer, &Interest{Name: name, LifeTime: 100})
+	if r.Err() != ErrTimeout {
+		t.Fatalf("expect %v, got %v", ErrTimeout, r.Err())
+	}
+}
+
+func TestNoCachePolicy(t *testing.T) {
+	c1, c2 := net.Pipe()
+	go io.Copy(io.Discard, c2)
+	cache := NewCache(10)
+	f := NewFace(c1, WithCache(cache))
+	defer f.Close()
+
+	for _, test := range []struct {
+		name    string
+		noCache bool
+	}{
+		{"/A", true},
+		{"/B", false},
+	} {
+		ch := f.SendInterest(&Interest{Name: NewName(test.name), LifeTime: 1000})
+		b, err := tlv.Marshal(&Data{Name: NewName(test.name)}, 6)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := &lpPacket{Fragment: b}
+		if test.noCache {
+			p.CachePolicy = []lpCachePolicy{{Type: lpCachePolicyNoCache}}
+		}
+		f.(*face).recvLpPacket(p)
+		if <-ch == nil {
+			t.Fatalf("%s: expect data", test.name)
+		}
+		if got := cache.Get(&Interest{Name: NewName(test.name)}) != nil; got == test.noCache {
+			t.Fatalf("%s: expect cached %v, got %v", test.name, !test.noCache, got)
+		}
+	}
+}
+
+func TestPendingInterestExpiry(t *testing.T) {
+	c1, c2 := net.Pipe()
+	go io.Copy(io.Discard, c2)
+	f := NewFace(c1)
+	defer f.Close()
+
+	long := f.SendInterest(&Interest{Name: NewName("/A"), LifeTime: 10000})
+	// an earlier deadline wakes the expiry goroutine
+	select {
+	case <-f.SendInterest(&Interest{Name: NewName("/B"), LifeTime: 50}):
+	case <-time.After(time.Second):
+		t.Fatal("expect /B expired")
+	}
+	var chs []<-chan *Data
+	for i := 0; i < 1000; i++ {
+		chs = append(chs, f.SendInterest(&Interest{
+			Name:     NewName(fmt.Sprintf("/C/%d", i)),
+			LifeTime: uint64(10 + i%20),
+		}))
+	}
+	for _, ch := range chs {
+		if <-ch != nil {
+			t.Fatal("expect timeout")
+		}
+	}
+	stats := f.(StatsFace).Stats()
+	if stats.Timeouts != 1001 || stats.PendingInterests != 1 {
+		t.Fatalf("expect %v timeouts and %v pending, got %+v", 1001, 1, stats)
+	}
+	select {
+	case <-long:
+		t.Fatal("expect /A pending")
+	default:
+	}
+}
+
+func TestPITKeys(t *testing.T) {
+	keys := pitKeys(NewName("/A/B/C").Components)
+	if len(keys) != 4 || keys[0] != "" {
+		t.Fatalf("expect 4 keys from the root, got %q", keys)
+	}
+	for k, want := range []string{"/", "/A", "/A/B", "/A/B/C"} {
+		if got := pitKey(NewName(want).Components); got != keys[k] {
+			t.Fatalf("expect %q, got %q", keys[k], got)
+		}
+	}
+	if pitKey(NewName("/AB").Components) == pitKey(NewName("/A/B").Components) {
+		t.Fatal("expect different keys")
+	}
+}
+
+func TestNegativeCache(t *testing.T) {
+	c1, c2 := net.Pipe()
+	go io.Copy(io.Discard, c2)
+	f := NewFace(c1, WithNegativeCache(NegativeCacheOptions{
+		TTL:      time.Second,
+		Timeouts: 2,
+	}))
+	defer f.Close()
+
+	i := &Interest{Name: NewName("/A")}
+	r := ExpressInterest(context.Background(), f, i)
+	f.(*face).recvNack(&Interest{Name: NewName("/A"), Nonce: i.Nonce}, NackReasonNoRoute)
+	if _, ok := r.Nack(); !ok {
+		t.Fatalf("expect nack, got %v", r.Err())
+	}
+	// answered locally
+	r = ExpressInterest(context.Background(), f, &Interest{Name: NewName("/A")})
+	if reason, ok := r.Nack(); !ok || reason != NackReasonNoRoute {
+		t.Fatalf("expect nack %d, got %v", NackReasonNoRoute, r.Err())
+	}
+
+	for k := 0; k < 3; k++ {
+		r := ExpressInterest(context.Background(), f, &Interest{Name: NewName("/B"), LifeTime: 10})
+		if r.Err() != ErrTimeout {
+			t.Fatalf("expect %v, got %v", ErrTimeout, r.Err())
+		}
+	}
+	stats := f.(StatsFace).Stats()
+	if stats.SuppressedInterests != 2 || stats.Timeouts != 2 {
+		t.Fatalf("expect %v suppressed and %v timeouts, got %+v", 2, 2, stats)
+	}
+
+	// other names are sent
+	if ExpressInterest(context.Background(), f, &Interest{Name: NewName("/A/B"), LifeTime: 10}).Err() != ErrTimeout {
+		t.Fatal("expect timeout")
+	}
+	if got := f.(StatsFace).Stats().SuppressedInterests; got != 2 {
+		t.Fatalf("expect %v, got %v", 2, got)
+	}
+}
+
+func TestCongestionMark(t *testing.T) {
+	c1, c2 := net.Pipe()
+	go io.Copy(io.Discard, c2)
+	f := NewFace(c1)
+	defer f.Close()
+
+	r := ExpressInterest(context.Background(), f, &Interest{Name: NewName("/A")})
+	b, err := tlv.Marshal(&Data{Name: NewName("/A")}, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.(*face).recvLpPacket(&lpPacket{CongestionMark: 1, Fragment: b})
+	if r.Data() == nil {
+		t.Fatal("expect data")
+	}
+	if got := r.CongestionMark(); got != 1 {
+		t.Fatalf("expect %v, got %v", 1, got)
+	}
+}
@@ -0,0 +1,66 @@
+package ndn
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+)
+
+// DialTLS connects to another node listening with ListenTLS.
+//
+// config provides ServerName and RootCAs, and the client certificate
+// if the listener requires one. opts are passed to NewFace.
+func DialTLS(address string, config *tls.Config, opts ...FaceOption) (Face, error) {
+	conn, err := tls.Dial("tcp", address, config)
+	if err != nil {
+		return nil, err
+	}
+	return NewFace(conn, opts...), nil
+}
+
+// TLSListener accepts TLS connections over TCP as faces.
+type TLSListener struct {
+	net.Listener
+}
+
+// ListenTLS listens on a TCP address.
+//
+// config must have at least one server certificate.
+// To authenticate clients, set ClientCAs and ClientAuth to tls.RequireAndVerifyClientCert.
+func ListenTLS(address string, config *tls.Config) (*TLSListener, error) {
+	ln, err := tls.Listen("tcp", address, config)
+	if err != nil {
+		return nil, err
+	}
+	return &TLSListener{Listener: ln}, nil
+}
+
+// Accept waits for the next connection, and returns it as a face
+// after the handshake, so that failed client authentication is reported here.
+func (ln *TLSListener) Accept(opts ...FaceOption) (Face, error) {
+	conn, err := ln.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	tc := conn.(*tls.Conn)
+	err = tc.Handshake()
+	if err != nil {
+		tc.Close()
+		return nil, err
+	}
+	return NewFace(tc, opts...), nil
+}
+
+// PeerCertificates returns the verified certificates of the remote node
+// if the face is over TLS.
+func PeerCertificates(f Face) []*x509.Certificate {
+	ff, ok := f.(*face)
+	if !ok {
+		return nil
+	}
+	tc, ok := ff.Conn.(*tls.Conn)
+	if !ok {
+		return nil
+	}
+	return tc.ConnectionState().PeerCertificates
+}
@@ -0,0 +1,93 @@
+package ndn
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func newTestTLSCertificate(t *testing.T, name string) (tls.Certificate, *x509.Certificate) {
+	pri, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		DNSNames:              []string{name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &pri.PublicKey, pri)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: pri}, cert
+}
+
+func TestTLSFace(t *testing.T) {
+	serverCert, serverX509 := newTestTLSCertificate(t, "server")
+	clientCert, clientX509 := newTestTLSCertificate(t, "client")
+	serverCAs := x509.NewCertPool()
+	serverCAs.AddCert(serverX509)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientX509)
+
+	ln, err := ListenTLS("127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	for _, test := range []struct {
+		certs []tls.Certificate
+		ok    bool
+	}{
+		{[]tls.Certificate{clientCert}, true},
+		{nil, false},
+	} {
+		accepted := make(chan Face, 1)
+		go func() {
+			f, _ := ln.Accept()
+			accepted <- f
+		}()
+		client, err := DialTLS(ln.Addr().String(), &tls.Config{
+			ServerName:   "server",
+			RootCAs:      serverCAs,
+			Certificates: test.certs,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		server := <-accepted
+		if (server != nil) != test.ok {
+			t.Fatalf("expect accepted %v, got %v", test.ok, server != nil)
+		}
+		if server != nil {
+			certs := PeerCertificates(server)
+			if len(certs) != 1 || certs[0].Subject.CommonName != "client" {
+				t.Fatalf("expect client certificate, got %v", certs)
+			}
+			server.Close()
+		}
+		client.Close()
+	}
+}
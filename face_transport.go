@@ -0,0 +1,206 @@
+package ndn
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"time"
+)
+
+// Transport carries whole packets of a face, so that transports other than
+// net.Conn, such as SCTP, serial bridges or in-memory pipes, can be used
+// with NewTransportFace.
+type Transport interface {
+	// ReadPacket reads one packet into b.
+	// If b is too small, the rest of the packet is discarded.
+	ReadPacket(b []byte) (int, error)
+	// WritePacket writes one packet.
+	WritePacket(b []byte) error
+	LocalAddr() net.Addr
+	RemoteAddr() net.Addr
+	Close() error
+}
+
+// NewTransportFace creates a face from t.
+//
+// Every packet is read and written with one call, like NewDatagramFace.
+// opts have the same meaning as NewFace.
+func NewTransportFace(t Transport, opts ...FaceOption) Face {
+	return NewDatagramFace(&transportConn{Transport: t}, opts...)
+}
+
+// transportConn adapts Transport to net.Conn for NewDatagramFace.
+type transportConn struct {
+	Transport
+}
+
+func (c *transportConn) Read(b []byte) (int, error) {
+	return c.ReadPacket(b)
+}
+
+func (c *transportConn) Write(b []byte) (int, error) {
+	err := c.WritePacket(b)
+	if err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// Deadlines are not supported by Transport.
+func (c *transportConn) SetDeadline(time.Time) error      { return nil }
+func (c *transportConn) SetReadDeadline(time.Time) error  { return nil }
+func (c *transportConn) SetWriteDeadline(time.Time) error { return nil }
+
+// transportAddr is the address of a transport that has none, such as a serial port.
+type transportAddr string
+
+func (a transportAddr) Network() string { return string(a) }
+func (a transportAddr) String() string  { return string(a) }
+
+// streamTransport finds packet boundaries in a byte stream with tlv type and length.
+type streamTransport struct {
+	rwc io.ReadWriteCloser
+	r   *bufio.Reader
+}
+
+// NewStreamTransport creates a transport from a byte stream, such as a serial port.
+//
+// If rwc implements net.Conn, its addresses are used.
+func NewStreamTransport(rwc io.ReadWriteCloser) Transport {
+	return &streamTransport{
+		rwc: rwc,
+		r:   bufio.NewReader(rwc),
+	}
+}
+
+// ReadPacket returns *PacketError if the packet is larger than the maximum NDN
+// packet size, because the next packet cannot be found.
+func (t *streamTransport) ReadPacket(b []byte) (int, error) {
+	typ, err := readNumber(t.r)
+	if err != nil {
+		return 0, err
+	}
+	l, err := readNumber(t.r)
+	if err != nil {
+		return 0, unexpectedEOF(err)
+	}
+	if l > maxPacketSize {
+		return 0, &PacketError{Type: typ, Err: ErrPacketTooLarge}
+	}
+	pkt := appendHeader(nil, typ, int(l))
+	if len(pkt)+int(l) > maxPacketSize {
+		return 0, &PacketError{Type: typ, Err: ErrPacketTooLarge}
+	}
+	n := len(pkt)
+	pkt = append(pkt, make([]byte, l)...)
+	_, err = io.ReadFull(t.r, pkt[n:])
+	if err != nil {
+		return 0, unexpectedEOF(err)
+	}
+	return copy(b, pkt), nil
+}
+
+func (t *streamTransport) WritePacket(b []byte) error {
+	_, err := t.rwc.Write(b)
+	return err
+}
+
+func (t *streamTransport) LocalAddr() net.Addr {
+	if conn, ok := t.rwc.(net.Conn); ok {
+		return conn.LocalAddr()
+	}
+	return transportAddr("stream")
+}
+
+func (t *streamTransport) RemoteAddr() net.Addr {
+	if conn, ok := t.rwc.(net.Conn); ok {
+		return conn.RemoteAddr()
+	}
+	return transportAddr("stream")
+}
+
+func (t *streamTransport) Close() error {
+	return t.rwc.Close()
+}
+
+// readNumber reads a variable-length number, which is encoded by appendNumber.
+func readNumber(r io.ByteReader) (uint64, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	var n int
+	switch b {
+	case 253:
+		n = 2
+	case 254:
+		n = 4
+	case 255:
+		n = 8
+	default:
+		return uint64(b), nil
+	}
+	var v uint64
+	for i := 0; i < n; i++ {
+		b, err = r.ReadByte()
+		if err != nil {
+			return 0, unexpectedEOF(err)
+		}
+		v = v<<8 | uint64(b)
+	}
+	return v, nil
+}
+
+// unexpectedEOF converts io.EOF in the middle of a packet to io.ErrUnexpectedEOF.
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// packetTransport exchanges packets with one address over net.PacketConn.
+type packetTransport struct {
+	conn net.PacketConn
+	addr net.Addr
+}
+
+// NewPacketTransport creates a transport that exchanges packets with addr
+// over a connectionless socket, such as an unconnected UDP socket.
+//
+// Packets from other addresses are dropped. The socket is closed with the transport.
+func NewPacketTransport(conn net.PacketConn, addr net.Addr) Transport {
+	return &packetTransport{
+		conn: conn,
+		addr: addr,
+	}
+}
+
+func (t *packetTransport) ReadPacket(b []byte) (int, error) {
+	for {
+		n, from, err := t.conn.ReadFrom(b)
+		if err != nil {
+			return 0, err
+		}
+		if from.String() == t.addr.String() {
+			return n, nil
+		}
+	}
+}
+
+func (t *packetTransport) WritePacket(b []byte) error {
+	_, err := t.conn.WriteTo(b, t.addr)
+	return err
+}
+
+func (t *packetTransport) LocalAddr() net.Addr {
+	return t.conn.LocalAddr()
+}
+
+func (t *packetTransport) RemoteAddr() net.Addr {
+	return t.addr
+}
+
+func (t *packetTransport) Close() error {
+	return t.conn.Close()
+}
@@ -0,0 +1,182 @@
+package ndn
+
+import (
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// UDPIdleTimeout is the default idle timeout of on-demand UDP faces.
+const UDPIdleTimeout = 10 * time.Minute
+
+// udpBacklog bounds new peers that are not yet accepted, and
+// packets that are not yet read by each peer.
+const udpBacklog = 64
+
+// ListenUDP listens on a UDP address, and accepts a datagram face for every
+// remote endpoint on its first packet, so that one socket serves many peers.
+//
+// An accepted face stops with ErrTimeout if it receives no packet for idle.
+// If idle is 0, UDPIdleTimeout is used.
+func ListenUDP(network, address string, idle time.Duration) (*Listener, error) {
+	addr, err := net.ResolveUDPAddr(network, address)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.ListenUDP(network, addr)
+	if err != nil {
+		return nil, err
+	}
+	if idle == 0 {
+		idle = UDPIdleTimeout
+	}
+	ln := &udpListener{
+		conn:    conn,
+		idle:    idle,
+		accept:  make(chan *udpPeerConn, udpBacklog),
+		peers:   make(map[string]*udpPeerConn),
+		closing: make(chan struct{}),
+	}
+	go ln.serve()
+	return &Listener{
+		Listener: ln,
+		datagram: true,
+		faces:    make(map[Face]struct{}),
+	}, nil
+}
+
+// udpListener demultiplexes packets from one socket by remote address.
+type udpListener struct {
+	conn   *net.UDPConn
+	idle   time.Duration
+	accept chan *udpPeerConn
+
+	mu    sync.Mutex
+	peers map[string]*udpPeerConn
+
+	closing   chan struct{}
+	closeOnce sync.Once
+}
+
+func (ln *udpListener) serve() {
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, addr, err := ln.conn.ReadFromUDP(buf)
+		if err != nil {
+			ln.Close()
+			return
+		}
+		b := make([]byte, n)
+		copy(b, buf)
+
+		ln.mu.Lock()
+		p, ok := ln.peers[addr.String()]
+		if !ok {
+			p = &udpPeerConn{
+				ln:   ln,
+				addr: addr,
+				in:   make(chan []byte, udpBacklog),
+				done: make(chan struct{}),
+			}
+			select {
+			case ln.accept <- p:
+				ln.peers[addr.String()] = p
+			default:
+				// too many new peers; the packet is dropped.
+				p = nil
+			}
+		}
+		ln.mu.Unlock()
+		if p == nil {
+			continue
+		}
+		select {
+		case p.in <- b:
+		default:
+			// the face is too slow; the packet is dropped.
+		}
+	}
+}
+
+func (ln *udpListener) Accept() (net.Conn, error) {
+	select {
+	case p := <-ln.accept:
+		return p, nil
+	case <-ln.closing:
+		return nil, net.ErrClosed
+	}
+}
+
+// Close closes the socket, and all accepted connections.
+func (ln *udpListener) Close() error {
+	var err error
+	ln.closeOnce.Do(func() {
+		close(ln.closing)
+		err = ln.conn.Close()
+	})
+	return err
+}
+
+func (ln *udpListener) Addr() net.Addr {
+	return ln.conn.LocalAddr()
+}
+
+// udpPeerConn is a connection to one remote endpoint of udpListener.
+type udpPeerConn struct {
+	ln   *udpListener
+	addr *net.UDPAddr
+	in   chan []byte
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// Read reads one datagram.
+//
+// ErrTimeout is returned if no datagram is received for the idle timeout.
+func (c *udpPeerConn) Read(b []byte) (int, error) {
+	timer := time.NewTimer(c.ln.idle)
+	defer timer.Stop()
+	select {
+	case p := <-c.in:
+		return copy(b, p), nil
+	case <-timer.C:
+		return 0, ErrTimeout
+	case <-c.done:
+		return 0, io.EOF
+	case <-c.ln.closing:
+		return 0, io.EOF
+	}
+}
+
+func (c *udpPeerConn) Write(b []byte) (int, error) {
+	return c.ln.conn.WriteToUDP(b, c.addr)
+}
+
+// Close removes the connection, and a later packet from the same endpoint
+// is accepted as a new connection.
+func (c *udpPeerConn) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.ln.mu.Lock()
+		if c.ln.peers[c.addr.String()] == c {
+			delete(c.ln.peers, c.addr.String())
+		}
+		c.ln.mu.Unlock()
+	})
+	return nil
+}
+
+func (c *udpPeerConn) LocalAddr() net.Addr {
+	return c.ln.conn.LocalAddr()
+}
+
+func (c *udpPeerConn) RemoteAddr() net.Addr {
+	return c.addr
+}
+
+// Deadlines are not supported, because the socket is shared.
+func (c *udpPeerConn) SetDeadline(time.Time) error      { return nil }
+func (c *udpPeerConn) SetReadDeadline(time.Time) error  { return nil }
+func (c *udpPeerConn) SetWriteDeadline(time.Time) error { return nil }
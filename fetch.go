@@ -0,0 +1,254 @@
+package ndn
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/go-ndn/lpm"
+)
+
+var (
+	// ErrInvalidSegment is returned if a segment does not follow the naming
+	// conventions of Segment.
+	ErrInvalidSegment = errors.New("invalid segment")
+	// ErrInvalidOffset is returned if SegmentReader seeks or reads before the start.
+	ErrInvalidOffset = errors.New("invalid offset")
+)
+
+// FetchOptions controls Fetch.
+type FetchOptions struct {
+	// Window is the number of segments requested at the same time,
+	// if Pipeline is nil.
+	// If it is 0, 16 is used.
+	Window int
+
+	// Pipeline adapts the number of segments requested at the same time
+	// to congestion, such as NewAIMDPipeline and NewCUBICPipeline.
+	// If it is nil, the window is fixed.
+	Pipeline Pipeline
+
+	// Retries is the maximum number of times an interest is sent again
+	// after it times out.
+	// If it is 0, 3 is used.
+	Retries int
+
+	// LifeTime is the lifetime of interests in milliseconds.
+	// If it is 0, the default lifetime is used.
+	LifeTime uint64
+}
+
+// Fetch retrieves all segments of content under name, which are named like Segment,
+// and returns the reassembled content, like ndncatchunks.
+//
+// If name does not end with a version, the latest version is discovered
+// with a fresh interest. Other segments are requested with a window of
+// opts.Pipeline, and only segments that time out are requested again.
+// FinalBlockID must be present in the last segment.
+func Fetch(ctx context.Context, w Sender, name Name, opts FetchOptions) ([]byte, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	f := newFetcher(w, opts)
+	d, err := f.discover(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if d != nil {
+		err := f.add(d)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	type result struct {
+		segment uint64
+		d       *Data
+		err     error
+	}
+	results := make(chan result)
+	var next uint64
+	var inflight int
+	for {
+		// at least one segment is requested, even if the window is empty.
+		for (inflight == 0 || inflight < f.window()) && (!f.hasFinal || next <= f.final) {
+			if _, ok := f.segments[next]; !ok {
+				inflight++
+				go func(segment uint64) {
+					d, err := f.fetch(ctx, segment)
+					select {
+					case results <- result{segment: segment, d: d, err: err}:
+					case <-ctx.Done():
+					}
+				}(next)
+			}
+			next++
+		}
+		if inflight == 0 {
+			break
+		}
+		r := <-results
+		inflight--
+		if f.hasFinal && r.segment > f.final {
+			// requested before FinalBlockID is known
+			continue
+		}
+		if r.err != nil {
+			return nil, r.err
+		}
+		err := f.add(r.d)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var content []byte
+	for segment := uint64(0); segment <= f.final; segment++ {
+		content = append(content, f.segments[segment]...)
+	}
+	return content, nil
+}
+
+// fetcher is the state of Fetch and SegmentReader.
+//
+// Only w, opts and pm are used by more than one goroutine, and
+// segments is only used by Fetch.
+type fetcher struct {
+	w    Sender
+	opts FetchOptions
+	pm   sync.Mutex // pipeline mutex
+	// version is the versioned name of segments.
+	version  Name
+	segments map[uint64][]byte
+	final    uint64
+	hasFinal bool
+}
+
+func newFetcher(w Sender, opts FetchOptions) *fetcher {
+	if opts.Window <= 0 {
+		opts.Window = 16
+	}
+	if opts.Pipeline == nil {
+		opts.Pipeline = fixedPipeline(opts.Window)
+	}
+	if opts.Retries <= 0 {
+		opts.Retries = 3
+	}
+	return &fetcher{
+		w:        w,
+		opts:     opts,
+		segments: make(map[uint64][]byte),
+	}
+}
+
+// discover sets version to name if it ends with a version.
+// Otherwise, it discovers the latest version with a fresh interest, and
+// returns the segment that satisfies it.
+func (f *fetcher) discover(ctx context.Context, name Name) (*Data, error) {
+	l := name.Len()
+	if l > 0 {
+		if _, ok := ParseVersion(name.Components[l-1]); ok {
+			f.version = name
+			return nil, nil
+		}
+	}
+	d, err := f.express(ctx, func() *Interest {
+		return &Interest{
+			Name: name,
+			Selectors: Selectors{
+				MustBeFresh: true,
+			},
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if d.Name.Len() != l+2 {
+		return nil, ErrInvalidSegment
+	}
+	if _, ok := ParseVersion(d.Name.Components[l]); !ok {
+		return nil, ErrInvalidSegment
+	}
+	f.version = Name{Components: d.Name.Components[:l+1]}
+	return d, nil
+}
+
+// fetch retrieves segment under version.
+func (f *fetcher) fetch(ctx context.Context, segment uint64) (*Data, error) {
+	components := make([]lpm.Component, 0, f.version.Len()+1)
+	components = append(components, f.version.Components...)
+	name := Name{Components: append(components, SegmentComponent(segment))}
+	d, err := f.express(ctx, func() *Interest {
+		return &Interest{Name: name}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if d.Name.Compare(name) != 0 {
+		return nil, ErrInvalidSegment
+	}
+	return d, nil
+}
+
+// express sends interests created by newInterest until one is satisfied
+// or opts.Retries is exceeded.
+// A new interest is created every time, because sending populates the nonce.
+func (f *fetcher) express(ctx context.Context, newInterest func() *Interest) (*Data, error) {
+	for retry := 0; ; retry++ {
+		i := newInterest()
+		i.LifeTime = f.opts.LifeTime
+		r := ExpressInterest(ctx, f.w, i)
+		d, err := r.Data(), r.Err()
+		reason, nacked := r.Nack()
+		congested := err == ErrTimeout || (nacked && reason == NackReasonCongestion)
+		f.pm.Lock()
+		if congested {
+			f.opts.Pipeline.Lost()
+		} else if err == nil {
+			f.opts.Pipeline.Satisfied(r.CongestionMark() != 0)
+		}
+		f.pm.Unlock()
+		if congested && retry < f.opts.Retries {
+			continue
+		}
+		return d, err
+	}
+}
+
+func (f *fetcher) window() int {
+	f.pm.Lock()
+	defer f.pm.Unlock()
+	return f.opts.Pipeline.Window()
+}
+
+// add stores the content of d, a segment under version.
+func (f *fetcher) add(d *Data) error {
+	segment, err := f.learn(d)
+	if err != nil {
+		return err
+	}
+	f.segments[segment] = d.Content
+	return nil
+}
+
+// learn returns the segment number of d, and learns FinalBlockID.
+func (f *fetcher) learn(d *Data) (uint64, error) {
+	segment, ok := ParseSegment(d.Name.Components[d.Name.Len()-1])
+	if !ok {
+		return 0, ErrInvalidSegment
+	}
+	if len(d.MetaInfo.FinalBlockID.Component) != 0 {
+		final, ok := ParseSegment(d.MetaInfo.FinalBlockID.Component)
+		if !ok {
+			return 0, ErrInvalidSegment
+		}
+		if !f.hasFinal || final < f.final {
+			f.final = final
+			f.hasFinal = true
+		}
+	}
+	if f.hasFinal && segment > f.final {
+		return 0, ErrInvalidSegment
+	}
+	return segment, nil
+}
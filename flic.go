@@ -0,0 +1,324 @@
+package ndn
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-ndn/lpm"
+	"github.com/go-ndn/tlv"
+)
+
+// ErrInvalidManifest is returned if a FLIC manifest or the data it points to is invalid.
+var ErrInvalidManifest = errors.New("invalid flic manifest")
+
+// manifestComponent names manifests other than the root.
+const manifestComponent = "manifest"
+
+// defaultManifestFanout keeps a manifest of SHA-256 pointers in a packet of maxPacketSize.
+const defaultManifestFanout = 128
+
+// flicManifest is the content of a FLIC manifest.
+type flicManifest struct {
+	Pointers []flicPointer `tlv:"130"`
+}
+
+// flicPointer points to a data segment or a manifest by implicit digest.
+type flicPointer struct {
+	Manifest bool   `tlv:"131?"`
+	Segment  uint64 `tlv:"132"`
+	Digest   []byte `tlv:"133"`
+}
+
+// ManifestOptions controls Manifest.
+type ManifestOptions struct {
+	// Size is the maximum content size of a data segment.
+	// If it is 0, 8000 bytes is used.
+	Size int
+
+	// Fanout is the maximum number of pointers in a manifest.
+	// If it is less than 2, 128 is used.
+	Fanout int
+
+	// Version is the version of content.
+	// If it is 0, the current Unix time in milliseconds is used.
+	Version uint64
+
+	// Key signs the root manifest.
+	// If it is nil, the root manifest is signed with DigestSha256.
+	Key Signer
+}
+
+// Manifest splits the content of d into data segments, and builds a tree of
+// FLIC (File-Like ICN Collection) manifests that point to them by implicit digest.
+//
+// The root manifest is named /<name of d>/<version>, and is the only packet
+// signed with opts.Key; it is the first in the returned slice.
+// Data segments are named /<name of d>/<version>/<segment>, and other manifests
+// are named /<name of d>/<version>/manifest/<segment>.
+func Manifest(d *Data, opts ManifestOptions) ([]*Data, error) {
+	fanout := opts.Fanout
+	if fanout <= 1 {
+		fanout = defaultManifestFanout
+	}
+	if opts.Version == 0 {
+		opts.Version = uint64(time.Now().UnixMilli())
+	}
+	segments, err := Segment(d, SegmentOptions{
+		Size:    opts.Size,
+		Version: opts.Version,
+	})
+	if err != nil {
+		return nil, err
+	}
+	versioned := Name{Components: segments[0].Name.Components[:d.Name.Len()+1]}
+
+	var packets []*Data
+	var pointers []flicPointer
+	for k, seg := range segments {
+		p, err := newFLICPointer(seg, false, uint64(k))
+		if err != nil {
+			return nil, err
+		}
+		packets = append(packets, seg)
+		pointers = append(pointers, p)
+	}
+
+	var next uint64
+	for len(pointers) > fanout {
+		var parents []flicPointer
+		for start := 0; start < len(pointers); start += fanout {
+			end := start + fanout
+			if end > len(pointers) {
+				end = len(pointers)
+			}
+			m, err := newManifestData(flicName(versioned, true, next), d.MetaInfo, pointers[start:end], nil)
+			if err != nil {
+				return nil, err
+			}
+			p, err := newFLICPointer(m, true, next)
+			if err != nil {
+				return nil, err
+			}
+			next++
+			packets = append(packets, m)
+			parents = append(parents, p)
+		}
+		pointers = parents
+	}
+
+	root, err := newManifestData(versioned, d.MetaInfo, pointers, opts.Key)
+	if err != nil {
+		return nil, err
+	}
+	return append([]*Data{root}, packets...), nil
+}
+
+func newManifestData(name Name, metaInfo MetaInfo, pointers []flicPointer, key Signer) (*Data, error) {
+	content, err := tlv.Marshal(&flicManifest{Pointers: pointers}, 129)
+	if err != nil {
+		return nil, err
+	}
+	m := &Data{
+		Name:     name,
+		MetaInfo: metaInfo,
+		Content:  content,
+	}
+	m.MetaInfo.FinalBlockID = FinalBlockID{}
+	err = SignData(key, m)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func newFLICPointer(d *Data, manifest bool, segment uint64) (flicPointer, error) {
+	digest, _, err := dataDigestSize(d)
+	if err != nil {
+		return flicPointer{}, err
+	}
+	return flicPointer{
+		Manifest: manifest,
+		Segment:  segment,
+		Digest:   digest,
+	}, nil
+}
+
+// flicName returns the name of a data segment or a manifest under versioned.
+func flicName(versioned Name, manifest bool, segment uint64) Name {
+	components := make([]lpm.Component, 0, versioned.Len()+2)
+	components = append(components, versioned.Components...)
+	if manifest {
+		components = append(components, lpm.Component(manifestComponent))
+	}
+	return Name{Components: append(components, SegmentComponent(segment))}
+}
+
+// FetchManifest retrieves content under name from FLIC manifests created by Manifest.
+//
+// If name does not end with a version, the latest version is discovered
+// with a fresh interest. Only the root manifest is verified with key, as in VerifyData;
+// other packets are requested with implicit digests from their manifests.
+// Packets are requested with a window of opts.Pipeline.
+func FetchManifest(ctx context.Context, w Sender, name Name, key Key, opts FetchOptions) ([]byte, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	f := newFetcher(w, opts)
+	root, err := f.discoverManifest(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	err = VerifyData(key, root)
+	if err != nil {
+		return nil, err
+	}
+	pointers, err := decodeManifest(root)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		var manifests []flicPointer
+		for _, p := range pointers {
+			if p.Manifest {
+				manifests = append(manifests, p)
+			}
+		}
+		if len(manifests) == 0 {
+			break
+		}
+		ds, err := f.fetchPointers(ctx, manifests)
+		if err != nil {
+			return nil, err
+		}
+		var expanded []flicPointer
+		for _, p := range pointers {
+			if !p.Manifest {
+				expanded = append(expanded, p)
+				continue
+			}
+			children, err := decodeManifest(ds[0])
+			if err != nil {
+				return nil, err
+			}
+			ds = ds[1:]
+			expanded = append(expanded, children...)
+		}
+		pointers = expanded
+	}
+
+	ds, err := f.fetchPointers(ctx, pointers)
+	if err != nil {
+		return nil, err
+	}
+	var content []byte
+	for _, d := range ds {
+		content = append(content, d.Content...)
+	}
+	return content, nil
+}
+
+func decodeManifest(d *Data) ([]flicPointer, error) {
+	var m flicManifest
+	err := tlv.Unmarshal(d.Content, &m, 129)
+	if err != nil {
+		return nil, ErrInvalidManifest
+	}
+	return m.Pointers, nil
+}
+
+// discoverManifest returns the root manifest under name.
+func (f *fetcher) discoverManifest(ctx context.Context, name Name) (*Data, error) {
+	l := name.Len()
+	if l > 0 {
+		if _, ok := ParseVersion(name.Components[l-1]); ok {
+			d, err := f.express(ctx, func() *Interest {
+				return &Interest{Name: name}
+			})
+			if err != nil {
+				return nil, err
+			}
+			if d.Name.Compare(name) != 0 {
+				return nil, ErrInvalidManifest
+			}
+			f.version = name
+			return d, nil
+		}
+	}
+	d, err := f.express(ctx, func() *Interest {
+		return &Interest{
+			Name: name,
+			Selectors: Selectors{
+				ChildSelector: 1,
+				MustBeFresh:   true,
+			},
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if d.Name.Len() != l+1 {
+		return nil, ErrInvalidManifest
+	}
+	if _, ok := ParseVersion(d.Name.Components[l]); !ok {
+		return nil, ErrInvalidManifest
+	}
+	f.version = d.Name
+	return d, nil
+}
+
+// fetchPointers retrieves packets that pointers point to under version, in order.
+func (f *fetcher) fetchPointers(ctx context.Context, pointers []flicPointer) ([]*Data, error) {
+	type result struct {
+		index int
+		d     *Data
+		err   error
+	}
+	results := make(chan result)
+	ds := make([]*Data, len(pointers))
+	var next, inflight int
+	for {
+		for (inflight == 0 || inflight < f.window()) && next < len(pointers) {
+			inflight++
+			go func(index int) {
+				d, err := f.fetchPointer(ctx, pointers[index])
+				select {
+				case results <- result{index: index, d: d, err: err}:
+				case <-ctx.Done():
+				}
+			}(next)
+			next++
+		}
+		if inflight == 0 {
+			return ds, nil
+		}
+		r := <-results
+		inflight--
+		if r.err != nil {
+			return nil, r.err
+		}
+		ds[r.index] = r.d
+	}
+}
+
+// fetchPointer retrieves the packet that p points to, and checks its implicit digest.
+func (f *fetcher) fetchPointer(ctx context.Context, p flicPointer) (*Data, error) {
+	name := flicName(f.version, p.Manifest, p.Segment)
+	name.ImplicitDigestSHA256 = p.Digest
+	d, err := f.express(ctx, func() *Interest {
+		return &Interest{Name: name}
+	})
+	if err != nil {
+		return nil, err
+	}
+	digest, _, err := dataDigestSize(d)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(digest, p.Digest) {
+		return nil, ErrInvalidManifest
+	}
+	return d, nil
+}
@@ -0,0 +1,69 @@
+package ndn
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+)
+
+func TestManifest(t *testing.T) {
+	content := make([]byte, 100)
+	for k := range content {
+		content[k] = byte(k)
+	}
+	packets, err := Manifest(&Data{
+		Name:     NewName("/A"),
+		MetaInfo: MetaInfo{FreshnessPeriod: 3600000},
+		Content:  content,
+	}, ManifestOptions{Size: 10, Fanout: 3, Version: 1, Key: ecdsaKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 10 data segments, 4 and 2 manifests, and the root
+	if len(packets) != 17 {
+		t.Fatalf("expect %v, got %v", 17, len(packets))
+	}
+	// only the root is signed with the key
+	for k, d := range packets {
+		want := uint64(SignatureTypeDigestSHA256)
+		if k == 0 {
+			want = ecdsaKey.SignatureType()
+		}
+		if d.SignatureInfo.SignatureType != want {
+			t.Fatalf("%v: expect %v, got %v", d.Name, want, d.SignatureInfo.SignatureType)
+		}
+	}
+
+	c := NewCache(32)
+	for _, d := range packets {
+		c.Add(d)
+	}
+	c1, c2 := net.Pipe()
+	producer := NewFace(c2, WithInterestHandler(InterestHandlerFunc(func(w Sender, i *Interest) {
+		if d := c.Get(i); d != nil {
+			w.SendData(d)
+		}
+	})))
+	defer producer.Close()
+	consumer := NewFace(c1)
+	defer consumer.Close()
+
+	for _, test := range []struct {
+		name string
+		key  Key
+		ok   bool
+	}{
+		{"/A", ecdsaKey, true},
+		{packets[0].Name.String(), ecdsaKey, true},
+		{"/A", rsaKey, false},
+	} {
+		got, err := FetchManifest(context.Background(), consumer, NewName(test.name), test.key, FetchOptions{Window: 4})
+		if (err == nil) != test.ok {
+			t.Fatalf("%v: expect ok %v, got %v", test.name, test.ok, err)
+		}
+		if test.ok && !bytes.Equal(got, content) {
+			t.Fatalf("expect %v, got %v", content, got)
+		}
+	}
+}
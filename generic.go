@@ -1,15 +1,13 @@
 package ndn
 
-import "container/list"
-
-//go:generate generic github.com/go-ndn/lpm/matcher .pit Type->map[chan<-*Data]pitEntry TypeMatcher->pitMatcher
-//go:generate generic github.com/go-ndn/lpm/matcher .cache Type->container/list:map[string]*list.Element TypeMatcher->cacheMatcher
+//go:generate generic github.com/go-ndn/lpm/matcher .cache Type->map[string]*cacheEntry TypeMatcher->cacheMatcher
+//go:generate generic github.com/go-ndn/lpm/matcher .handler Type->*prefixHandler TypeMatcher->handlerMatcher
 
 func init() {
-	cacheNodeValEmpty = func(t map[string]*list.Element) bool {
+	cacheNodeValEmpty = func(t map[string]*cacheEntry) bool {
 		return t == nil
 	}
-	pitNodeValEmpty = func(t map[chan<- *Data]pitEntry) bool {
+	handlerNodeValEmpty = func(t *prefixHandler) bool {
 		return t == nil
 	}
 }
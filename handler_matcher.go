@@ -0,0 +1,119 @@
+package ndn
+
+import "github.com/go-ndn/lpm"
+
+type handlerMatcher struct {
+	handlerNode
+}
+
+var handlerNodeValEmpty func(*prefixHandler) bool
+
+type handlerNode struct {
+	val   *prefixHandler
+	table map[string]handlerNode
+}
+
+func (n *handlerNode) empty() bool {
+	return handlerNodeValEmpty(n.val) && len(n.table) == 0
+}
+
+func (n *handlerNode) update(key []lpm.Component, depth int, f func([]lpm.Component, *prefixHandler) *prefixHandler, exist, all bool) {
+	try := func() {
+		if !exist || !handlerNodeValEmpty(n.val) {
+			n.val = f(key[:depth], n.val)
+		}
+	}
+	if len(key) == depth {
+		try()
+		return
+	}
+
+	if n.table == nil {
+		if exist {
+			try()
+			return
+		}
+		n.table = make(map[string]handlerNode)
+	}
+
+	v, ok := n.table[string(key[depth])]
+	if !ok {
+		if exist {
+			try()
+			return
+		}
+	}
+
+	if all {
+		try()
+	}
+
+	v.update(key, depth+1, f, exist, all)
+	if v.empty() {
+		delete(n.table, string(key[depth]))
+	} else {
+		n.table[string(key[depth])] = v
+	}
+}
+
+func (n *handlerNode) match(key []lpm.Component, depth int, f func(*prefixHandler), exist bool) {
+	try := func() {
+		if !exist || !handlerNodeValEmpty(n.val) {
+			f(n.val)
+		}
+	}
+	if len(key) == depth {
+		try()
+		return
+	}
+
+	if n.table == nil {
+		if exist {
+			try()
+		}
+		return
+	}
+
+	v, ok := n.table[string(key[depth])]
+	if !ok {
+		if exist {
+			try()
+		}
+		return
+	}
+
+	v.match(key, depth+1, f, exist)
+}
+
+func (n *handlerNode) visit(key []lpm.Component, f func([]lpm.Component, *prefixHandler) *prefixHandler) {
+	if !handlerNodeValEmpty(n.val) {
+		n.val = f(key, n.val)
+	}
+	for k, v := range n.table {
+		v.visit(append(key, lpm.Component(k)), f)
+		if v.empty() {
+			delete(n.table, k)
+		} else {
+			n.table[k] = v
+		}
+	}
+}
+
+func (n *handlerNode) Update(key []lpm.Component, f func(*prefixHandler) *prefixHandler, exist bool) {
+	n.update(key, 0, func(_ []lpm.Component, v *prefixHandler) *prefixHandler {
+		return f(v)
+	}, exist, false)
+}
+
+func (n *handlerNode) UpdateAll(key []lpm.Component, f func([]lpm.Component, *prefixHandler) *prefixHandler, exist bool) {
+	n.update(key, 0, f, exist, true)
+}
+
+func (n *handlerNode) Match(key []lpm.Component, f func(*prefixHandler), exist bool) {
+	n.match(key, 0, f, exist)
+}
+
+func (n *handlerNode) Visit(f func([]lpm.Component, *prefixHandler) *prefixHandler) {
+	key := make([]lpm.Component, 0, 16)
+	n.visit(key, f)
+}
@@ -9,6 +9,7 @@ import (
 	"errors"
 	"io"
 	"io/ioutil"
+	"strconv"
 	"time"
 
 	"github.com/go-ndn/tlv"
@@ -18,25 +19,41 @@ import (
 var (
 	ErrNotSupported     = errors.New("feature not supported")
 	ErrInvalidSignature = errors.New("invalid signature")
-	ErrInvalidPEM       = errors.New("invalid pem")
+	// ErrCertificateExpired is returned if the current time is outside ValidityPeriod.
+	ErrCertificateExpired = errors.New("certificate is expired or not yet valid")
+	ErrInvalidPEM         = errors.New("invalid pem")
 )
 
 const (
 	pemHeaderName = "NAME"
-	pemTypeRSA    = "RSA PRIVATE KEY"
-	pemTypeECDSA  = "ECDSA PRIVATE KEY"
-	pemTypeHMAC   = "HMAC PRIVATE KEY"
+	// pemHeaderSignatureType is the signature type of the key, because
+	// RSA keys can sign with either PKCS#1 v1.5 or PSS, and ECDSA keys
+	// can use SHA-256, SHA-384 or SHA-512.
+	pemHeaderSignatureType = "SIGNATURE-TYPE"
+
+	pemTypeRSA     = "RSA PRIVATE KEY"
+	pemTypeECDSA   = "ECDSA PRIVATE KEY"
+	pemTypeHMAC    = "HMAC PRIVATE KEY"
+	pemTypeEd25519 = "ED25519 PRIVATE KEY"
 )
 
-// Key signs and verifies data packets.
-type Key interface {
+// Signer signs packets.
+//
+// Every Key is a Signer, but a Signer may also forward the signed portion
+// to a signing service that holds the private key. See RemoteSigner.
+type Signer interface {
 	Locator() Name
 	SignatureType() uint64
+	Sign(interface{}) ([]byte, error)
+}
+
+// Key signs and verifies data packets.
+type Key interface {
+	Signer
 	// If the key is symmetric, Private is identical to Public.
 	Private() ([]byte, error)
 	Public() ([]byte, error)
 
-	Sign(interface{}) ([]byte, error)
 	Verify(interface{}, []byte) error
 }
 
@@ -46,12 +63,14 @@ type Key interface {
 func EncodePrivateKey(key Key, w io.Writer) error {
 	var keyType string
 	switch key.SignatureType() {
-	case SignatureTypeSHA256WithRSA:
+	case SignatureTypeSHA256WithRSA, SignatureTypeSHA256WithRSAPSS:
 		keyType = pemTypeRSA
-	case SignatureTypeSHA256WithECDSA:
+	case SignatureTypeSHA256WithECDSA, SignatureTypeSHA384WithECDSA, SignatureTypeSHA512WithECDSA:
 		keyType = pemTypeECDSA
 	case SignatureTypeSHA256WithHMAC:
 		keyType = pemTypeHMAC
+	case SignatureTypeEd25519:
+		keyType = pemTypeEd25519
 	default:
 		return ErrNotSupported
 	}
@@ -62,12 +81,48 @@ func EncodePrivateKey(key Key, w io.Writer) error {
 	return pem.Encode(w, &pem.Block{
 		Type: keyType,
 		Headers: map[string]string{
-			pemHeaderName: key.Locator().String(),
+			pemHeaderName:          key.Locator().String(),
+			pemHeaderSignatureType: strconv.FormatUint(key.SignatureType(), 10),
 		},
 		Bytes: keyBytes,
 	})
 }
 
+// setSignatureType restores the signature type saved in pemHeaderSignatureType.
+//
+// If the header is missing, the key keeps the default signature type.
+func setSignatureType(key Key, header map[string]string) error {
+	v, ok := header[pemHeaderSignatureType]
+	if !ok {
+		return nil
+	}
+	sigType, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return err
+	}
+	switch key := key.(type) {
+	case *RSAKey:
+		switch sigType {
+		case SignatureTypeSHA256WithRSA:
+			key.PSS = false
+		case SignatureTypeSHA256WithRSAPSS:
+			key.PSS = true
+		default:
+			return ErrNotSupported
+		}
+	case *ECDSAKey:
+		if sigType == key.SignatureType() {
+			return nil
+		}
+		h, ok := ecdsaHashes[sigType]
+		if !ok {
+			return ErrNotSupported
+		}
+		key.Hash = h
+	}
+	return nil
+}
+
 // DecodePrivateKey decodes the private key in PEM encoding.
 //
 // See EncodePrivateKey.
@@ -81,6 +136,10 @@ func DecodePrivateKey(r io.Reader) (key Key, err error) {
 		err = ErrInvalidPEM
 		return
 	}
+	return decodePrivateKeyBlock(block)
+}
+
+func decodePrivateKeyBlock(block *pem.Block) (key Key, err error) {
 	name := NewName(block.Headers[pemHeaderName])
 	switch block.Type {
 	case pemTypeRSA:
@@ -108,29 +167,23 @@ func DecodePrivateKey(r io.Reader) (key Key, err error) {
 			Name:       name,
 			PrivateKey: block.Bytes,
 		}
+	case pemTypeEd25519:
+		key, err = parsePrivateKey(name, block.Bytes)
 	default:
 		err = ErrNotSupported
 	}
+	if err != nil {
+		return
+	}
+	err = setSignatureType(key, block.Headers)
 	return
 }
 
-// CertificateToData creates a data packet from a self-signed public key.
+// CertificateToData creates a self-signed certificate with SelfSign.
 //
 // See CertificateFromData.
-func CertificateToData(key Key) (d *Data, err error) {
-	d = &Data{
-		Name: key.Locator(),
-		MetaInfo: MetaInfo{
-			ContentType:     2,       // key
-			FreshnessPeriod: 3600000, // 1 hour
-		},
-	}
-	d.Content, err = key.Public()
-	if err != nil {
-		return
-	}
-	err = SignData(key, d)
-	return
+func CertificateToData(key Key) (*Data, error) {
+	return SelfSign(key)
 }
 
 // EncodeCertificate invokes CertificateToData and encodes
@@ -142,41 +195,33 @@ func EncodeCertificate(key Key, w io.Writer) error {
 	if err != nil {
 		return err
 	}
+	return encodeCertificateData(d, w)
+}
+
+func encodeCertificateData(d *Data, w io.Writer) error {
 	enc := base64.NewEncoder(base64.StdEncoding, w)
-	err = d.WriteTo(tlv.NewWriter(enc))
+	err := d.WriteTo(tlv.NewWriter(enc))
 	if err != nil {
 		return err
 	}
 	return enc.Close()
 }
 
-// CertificateFromData creates a public key from a data packet.
+// CertificateFromData creates a public key from a certificate data packet.
+//
+// The key is named by the key name in the certificate.
 //
 // See CertificateToData.
 func CertificateFromData(d *Data) (key Key, err error) {
-	pub, err := x509.ParsePKIXPublicKey(d.Content)
+	cert, err := ParseCertificate(d)
 	if err != nil {
 		return
 	}
-	switch pub := pub.(type) {
-	case *rsa.PublicKey:
-		key = &RSAKey{
-			Name: d.Name,
-			PrivateKey: &rsa.PrivateKey{
-				PublicKey: *pub,
-			},
-		}
-	case *ecdsa.PublicKey:
-		key = &ECDSAKey{
-			Name: d.Name,
-			PrivateKey: &ecdsa.PrivateKey{
-				PublicKey: *pub,
-			},
-		}
-	default:
-		err = ErrNotSupported
+	pub, err := x509.ParsePKIXPublicKey(cert.PublicKey)
+	if err != nil {
+		return
 	}
-	return
+	return publicKey(cert.Name, pub)
 }
 
 // DecodeCertificate decodes a data packet in base64 encoding,
@@ -184,16 +229,30 @@ func CertificateFromData(d *Data) (key Key, err error) {
 //
 // See EncodeCertificate.
 func DecodeCertificate(r io.Reader) (key Key, err error) {
-	d := new(Data)
-	err = d.ReadFrom(tlv.NewReader(base64.NewDecoder(base64.StdEncoding, r)))
+	d, err := decodeCertificateData(r)
 	if err != nil {
 		return
 	}
 	return CertificateFromData(d)
 }
 
-// SignData signs a data packet with the given key.
-func SignData(key Key, d *Data) (err error) {
+func decodeCertificateData(r io.Reader) (d *Data, err error) {
+	d = new(Data)
+	err = d.ReadFrom(tlv.NewReader(base64.NewDecoder(base64.StdEncoding, r)))
+	return
+}
+
+// SignData signs a data packet with the given key or signer.
+//
+// If key is nil, the data packet is signed with DigestSha256,
+// which only provides integrity.
+func SignData(key Signer, d *Data) (err error) {
+	if key == nil {
+		d.SignatureInfo.SignatureType = SignatureTypeDigestSHA256
+		d.SignatureInfo.KeyLocator = KeyLocator{}
+		d.SignatureValue = nil
+		return d.digest()
+	}
 	d.SignatureInfo.SignatureType = key.SignatureType()
 	d.SignatureInfo.KeyLocator.Name = key.Locator()
 	d.SignatureValue, err = key.Sign(d)
@@ -202,20 +261,48 @@ func SignData(key Key, d *Data) (err error) {
 
 // VerifyData verifies a data packet with the given key.
 //
-// It also checks ValidityPeriod.
+// It also checks ValidityPeriod, and returns ErrCertificateExpired
+// if the current time is outside of it.
+// If key is nil, the data packet must be signed with DigestSha256 or DigestCRC32C.
 func VerifyData(key Key, d *Data) error {
-	now := time.Now()
-	if d.SignatureInfo.ValidityPeriod.NotBefore != "" {
-		t, err := time.Parse(ISO8601, d.SignatureInfo.ValidityPeriod.NotBefore)
-		if err != nil || now.Before(t) {
-			return ErrInvalidSignature
-		}
+	return verifyData(key, d, 0)
+}
+
+// verifyData is VerifyData that tolerates clock skew in ValidityPeriod.
+func verifyData(key Key, d *Data, skew time.Duration) error {
+	if key == nil {
+		return d.verifyDigest()
 	}
-	if d.SignatureInfo.ValidityPeriod.NotAfter != "" {
-		t, err := time.Parse(ISO8601, d.SignatureInfo.ValidityPeriod.NotAfter)
-		if err != nil || now.After(t) {
-			return ErrInvalidSignature
+	err := checkValidity(&d.SignatureInfo.ValidityPeriod, time.Now(), skew)
+	if err != nil {
+		return err
+	}
+	return verificationKey(key, d.SignatureInfo.SignatureType).Verify(d, d.SignatureValue)
+}
+
+// verificationKey adapts key to the signature type of a packet.
+//
+// An RSA key verifies both PKCS#1 v1.5 and PSS signatures, and
+// an ECDSA key verifies signatures with any supported digest.
+func verificationKey(key Key, sigType uint64) Key {
+	switch key := key.(type) {
+	case *RSAKey:
+		return &RSAKey{
+			Name:       key.Name,
+			PrivateKey: key.PrivateKey,
+			PSS:        sigType == SignatureTypeSHA256WithRSAPSS,
 		}
+	case *ECDSAKey:
+		h, ok := ecdsaHashes[sigType]
+		if !ok {
+			return key
+		}
+		return &ECDSAKey{
+			Name:       key.Name,
+			PrivateKey: key.PrivateKey,
+			Hash:       h,
+		}
+	default:
+		return key
 	}
-	return key.Verify(d, d.SignatureValue)
 }
@@ -0,0 +1,95 @@
+package ndn
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"io/ioutil"
+	"strconv"
+)
+
+const pemTypeEncrypted = "ENCRYPTED PRIVATE KEY"
+
+// PasswordFunc returns the password of an encrypted private key.
+//
+// It can prompt the user, or read the password from a secret store.
+type PasswordFunc func() ([]byte, error)
+
+// EncodeEncryptedPrivateKey encodes the private key in PEM-encoded PKCS#8,
+// which is encrypted with password.
+//
+// Symmetric keys are not supported.
+//
+// See DecodeEncryptedPrivateKey.
+func EncodeEncryptedPrivateKey(key Key, password []byte, w io.Writer) error {
+	b, err := encryptPKCS8(key, password)
+	if err != nil {
+		return err
+	}
+	return pem.Encode(w, &pem.Block{
+		Type: pemTypeEncrypted,
+		Headers: map[string]string{
+			pemHeaderName:          key.Locator().String(),
+			pemHeaderSignatureType: strconv.FormatUint(key.SignatureType(), 10),
+		},
+		Bytes: b,
+	})
+}
+
+// DecodeEncryptedPrivateKey decodes the private key in PEM encoding.
+//
+// If the key is encrypted in PKCS#8 or with legacy PEM encryption (RFC 1421),
+// password is invoked to decrypt it. Otherwise, it is the same as DecodePrivateKey.
+// ErrInvalidPassword is returned if the password is wrong.
+//
+// See EncodeEncryptedPrivateKey.
+func DecodeEncryptedPrivateKey(r io.Reader, password PasswordFunc) (Key, error) {
+	pemData, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return nil, ErrInvalidPEM
+	}
+	name := NewName(block.Headers[pemHeaderName])
+	switch {
+	case block.Type == pemTypeEncrypted:
+		pw, err := password()
+		if err != nil {
+			return nil, err
+		}
+		key, err := decryptPKCS8(name, block.Bytes, pw)
+		if err != nil {
+			return nil, err
+		}
+		err = setSignatureType(key, block.Headers)
+		if err != nil {
+			return nil, err
+		}
+		return key, nil
+	case x509.IsEncryptedPEMBlock(block):
+		pw, err := password()
+		if err != nil {
+			return nil, err
+		}
+		b, err := x509.DecryptPEMBlock(block, pw)
+		if err == x509.IncorrectPasswordError {
+			return nil, ErrInvalidPassword
+		}
+		if err != nil {
+			return nil, err
+		}
+		headers := map[string]string{pemHeaderName: block.Headers[pemHeaderName]}
+		if v, ok := block.Headers[pemHeaderSignatureType]; ok {
+			headers[pemHeaderSignatureType] = v
+		}
+		return decodePrivateKeyBlock(&pem.Block{
+			Type:    block.Type,
+			Headers: headers,
+			Bytes:   b,
+		})
+	default:
+		return decodePrivateKeyBlock(block)
+	}
+}
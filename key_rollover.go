@@ -0,0 +1,114 @@
+package ndn
+
+import (
+	"crypto/elliptic"
+	"time"
+)
+
+// RolloverOptions controls KeyChain.Rollover.
+type RolloverOptions struct {
+	// Generate creates the new key.
+	// If it is nil, an ECDSA P-256 key is generated.
+	Generate func(identity Name) (Key, error)
+
+	// Certify obtains the certificate of the new key, e.g. from a CA with CertClient.
+	// If it is nil, the certificate is issued by the old key,
+	// so consumers that trust the old key can follow to the new one.
+	Certify func(key, old Key) (*Data, error)
+
+	// Publish is invoked with the certificates of both keys before the switch,
+	// so they can be served together during the overlap window.
+	Publish func(old, new *Data)
+
+	// Switched is invoked after the default key is switched,
+	// so producers can re-sign data that refers to the signing key,
+	// such as RDR metadata.
+	Switched func(old, new Key) error
+
+	// Overlap is how long the old key stays in the key chain after the switch.
+	// If it is 0, the old key is kept until it is deleted.
+	Overlap time.Duration
+}
+
+// Rollover replaces the default key of identity with a new key.
+//
+// The new key and its certificate are added first, and then the default key
+// is switched in one PIB update, so SigningKey never returns a key without certificate.
+func (kc *KeyChain) Rollover(identity Name, opts RolloverOptions) (Key, error) {
+	oldName, err := kc.PIB.DefaultKey(identity)
+	if err != nil {
+		return nil, err
+	}
+	old, err := kc.TPM.Key(oldName)
+	if err != nil {
+		return nil, err
+	}
+	oldCert, err := kc.Certificate(oldName)
+	if err != nil {
+		return nil, err
+	}
+
+	generate := opts.Generate
+	if generate == nil {
+		generate = func(identity Name) (Key, error) {
+			return GenerateECDSAKey(identity, elliptic.P256())
+		}
+	}
+	key, err := generate(identity)
+	if err != nil {
+		return nil, err
+	}
+	certify := opts.Certify
+	if certify == nil {
+		certify = issueByOldKey
+	}
+	cert, err := certify(key, old)
+	if err != nil {
+		return nil, err
+	}
+	err = kc.addKey(key, cert)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Publish != nil {
+		opts.Publish(oldCert, cert)
+	}
+
+	err = kc.PIB.SetDefaultKey(key.Locator())
+	if err != nil {
+		return nil, err
+	}
+	if opts.Overlap > 0 {
+		time.AfterFunc(opts.Overlap, func() {
+			kc.DeleteKey(oldName)
+		})
+	}
+	if opts.Switched != nil {
+		err = opts.Switched(old, key)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+// issueByOldKey certifies key with old, and the issuer id is the key id of old.
+func issueByOldKey(key, old Key) (*Data, error) {
+	public, err := key.Public()
+	if err != nil {
+		return nil, err
+	}
+	_, issuerID, ok := ParseKeyName(old.Locator())
+	if !ok {
+		return nil, ErrNotSupported
+	}
+	now := time.Now()
+	return IssueCertificate(&Certificate{
+		Name:      key.Locator(),
+		IssuerID:  issuerID,
+		Version:   uint64(now.UnixNano() / 1000000),
+		PublicKey: public,
+		NotBefore: now.Add(-time.Second),
+		NotAfter:  now.Add(selfSignedValidity),
+	}, old)
+}
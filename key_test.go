@@ -2,6 +2,12 @@ package ndn
 
 import (
 	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"io/ioutil"
 	"os"
 	"reflect"
 	"testing"
@@ -46,6 +52,51 @@ func TestPrivateKey(t *testing.T) {
 	}
 }
 
+func TestEncryptedPrivateKey(t *testing.T) {
+	password := func() ([]byte, error) {
+		return []byte("secret"), nil
+	}
+	for _, key1 := range []Key{rsaKey, ecdsaKey} {
+		buf := new(bytes.Buffer)
+		err := EncodeEncryptedPrivateKey(key1, []byte("secret"), buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b := buf.Bytes()
+
+		_, err = DecodeEncryptedPrivateKey(bytes.NewReader(b), func() ([]byte, error) {
+			return []byte("wrong"), nil
+		})
+		if err != ErrInvalidPassword {
+			t.Fatalf("expect %v, got %v", ErrInvalidPassword, err)
+		}
+
+		key2, err := DecodeEncryptedPrivateKey(bytes.NewReader(b), password)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if name := key2.Locator(); name.Compare(key1.Locator()) != 0 {
+			t.Fatalf("expect %v, got %v", key1.Locator(), name)
+		}
+		pri1, _ := key1.Private()
+		pri2, _ := key2.Private()
+		if !bytes.Equal(pri1, pri2) {
+			t.Fatalf("expect %x, got %x", pri1, pri2)
+		}
+	}
+
+	// unencrypted keys do not need password
+	buf := new(bytes.Buffer)
+	err := EncodePrivateKey(hmacKey, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = DecodeEncryptedPrivateKey(buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestCertificate(t *testing.T) {
 	for _, key := range []Key{rsaKey, ecdsaKey} {
 		buf := new(bytes.Buffer)
@@ -61,6 +112,46 @@ func TestCertificate(t *testing.T) {
 	}
 }
 
+func TestCertificateFormat(t *testing.T) {
+	d, err := SelfSign(ecdsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name := ecdsaKey.Locator(); d.Name.Len() != name.Len()+2 {
+		t.Fatalf("expect v2 certificate name, got %v", d.Name)
+	}
+	cert, err := ParseCertificate(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cert.Name.Compare(ecdsaKey.Locator()) != 0 {
+		t.Fatalf("expect %v, got %v", ecdsaKey.Locator(), cert.Name)
+	}
+	if string(cert.IssuerID) != "self" {
+		t.Fatalf("expect self, got %v", cert.IssuerID)
+	}
+	if !cert.NotBefore.Before(cert.NotAfter) {
+		t.Fatalf("invalid validity period %v-%v", cert.NotBefore, cert.NotAfter)
+	}
+	if validity := cert.NotAfter.Sub(cert.NotBefore); validity < selfSignedValidity {
+		t.Fatalf("expect validity %v, got %v", selfSignedValidity, validity)
+	}
+	if d.SignatureInfo.KeyLocator.Name.Compare(ecdsaKey.Locator()) != 0 {
+		t.Fatalf("expect key locator %v, got %v", ecdsaKey.Locator(), d.SignatureInfo.KeyLocator.Name)
+	}
+	err = VerifyData(ecdsaKey, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCertificateSymmetric(t *testing.T) {
+	_, err := CertificateToData(hmacKey)
+	if err != ErrNotSupported {
+		t.Fatalf("expect %v, got %v", ErrNotSupported, err)
+	}
+}
+
 func TestSignVerify(t *testing.T) {
 	now := time.Now().UTC()
 	d := &Data{
@@ -82,3 +173,264 @@ func TestSignVerify(t *testing.T) {
 		}
 	}
 }
+
+func TestDigestSHA256(t *testing.T) {
+	d := &Data{
+		Name:    NewName("/localhost/nfd/status"),
+		Content: []byte("ok"),
+	}
+	err := SignData(nil, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.SignatureInfo.SignatureType != SignatureTypeDigestSHA256 || len(d.SignatureValue) != 32 {
+		t.Fatalf("expect DigestSha256, got type %d and %d-byte value", d.SignatureInfo.SignatureType, len(d.SignatureValue))
+	}
+	err = VerifyData(nil, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Content = []byte("modified")
+	err = VerifyData(nil, d)
+	if err != ErrInvalidSignature {
+		t.Fatalf("expect %v, got %v", ErrInvalidSignature, err)
+	}
+	err = SignData(ecdsaKey, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = VerifyData(nil, d)
+	if err != ErrNotSupported {
+		t.Fatalf("expect %v, got %v", ErrNotSupported, err)
+	}
+}
+
+func TestSignerKey(t *testing.T) {
+	for _, key := range []Key{rsaKey, ecdsaKey} {
+		var signer crypto.Signer
+		switch key := key.(type) {
+		case *RSAKey:
+			signer = key.PrivateKey
+		case *ECDSAKey:
+			signer = key.PrivateKey
+		}
+		signerKey := &SignerKey{
+			Name:   key.Locator(),
+			Signer: signer,
+		}
+		d := &Data{Name: NewName("/hello")}
+		err := SignData(signerKey, d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = VerifyData(key, d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = VerifyData(signerKey, d)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSignatureOptions(t *testing.T) {
+	opts := &SignatureOptions{
+		Nonce:  true,
+		Time:   true,
+		SeqNum: true,
+	}
+	var last SignatureInfo
+	for seq := uint64(1); seq <= 3; seq++ {
+		d := new(Data)
+		err := opts.Populate(&d.SignatureInfo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		info := d.SignatureInfo
+		if info.SeqNum != seq {
+			t.Fatalf("expect SeqNum %d, got %d", seq, info.SeqNum)
+		}
+		if info.Time <= last.Time {
+			t.Fatalf("expect Time > %d, got %d", last.Time, info.Time)
+		}
+		if len(info.Nonce) != 8 || bytes.Equal(info.Nonce, last.Nonce) {
+			t.Fatalf("expect new 8-byte Nonce, got %x", info.Nonce)
+		}
+		last = info
+
+		err = SignData(ecdsaKey, d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = VerifyData(ecdsaKey, d)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRSAPSS(t *testing.T) {
+	key := &RSAKey{
+		Name:       rsaKey.Locator(),
+		PrivateKey: rsaKey.(*RSAKey).PrivateKey,
+		PSS:        true,
+	}
+	d := &Data{
+		Name: NewName("/A/B"),
+	}
+	err := SignData(key, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.SignatureInfo.SignatureType != SignatureTypeSHA256WithRSAPSS {
+		t.Fatalf("expect %d, got %d", SignatureTypeSHA256WithRSAPSS, d.SignatureInfo.SignatureType)
+	}
+	// the public key in certificate does not know PSS
+	cert, err := CertificateToData(rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := CertificateFromData(cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = VerifyData(pub, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.SignatureInfo.SignatureType = SignatureTypeSHA256WithRSA
+	err = VerifyData(pub, d)
+	if err != ErrInvalidSignature {
+		t.Fatalf("expect %v, got %v", ErrInvalidSignature, err)
+	}
+}
+
+// roundTripKeys saves and loads key with PEM, encrypted PEM and file TPM.
+func roundTripKeys(t *testing.T, key Key) []Key {
+	buf := new(bytes.Buffer)
+	err := EncodePrivateKey(key, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key1, err := DecodePrivateKey(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	password := []byte("secret")
+	buf.Reset()
+	err = EncodeEncryptedPrivateKey(key, password, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key2, err := DecodeEncryptedPrivateKey(buf, func() ([]byte, error) {
+		return password, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "ndn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	err = NewFileTPM(dir).AddKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key3, err := NewFileTPM(dir).Key(key.Locator())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []Key{key1, key2, key3}
+}
+
+func TestRSAPSSRoundTrip(t *testing.T) {
+	for _, pss := range []bool{false, true} {
+		key := &RSAKey{
+			Name:       rsaKey.Locator(),
+			PrivateKey: rsaKey.(*RSAKey).PrivateKey,
+			PSS:        pss,
+		}
+		for _, loaded := range roundTripKeys(t, key) {
+			if got := loaded.SignatureType(); got != key.SignatureType() {
+				t.Fatalf("expect %d, got %d", key.SignatureType(), got)
+			}
+		}
+	}
+}
+
+func TestECDSAHash(t *testing.T) {
+	for _, test := range []struct {
+		curve   elliptic.Curve
+		hash    crypto.Hash
+		sigType uint64
+	}{
+		{elliptic.P256(), 0, SignatureTypeSHA256WithECDSA},
+		{elliptic.P384(), crypto.SHA384, SignatureTypeSHA384WithECDSA},
+		{elliptic.P521(), crypto.SHA512, SignatureTypeSHA512WithECDSA},
+	} {
+		key, err := GenerateECDSAKey(NewName("/A"), test.curve)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key.Hash = test.hash
+		d := &Data{
+			Name: NewName("/A/B"),
+		}
+		err = SignData(key, d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.SignatureInfo.SignatureType != test.sigType {
+			t.Fatalf("expect %d, got %d", test.sigType, d.SignatureInfo.SignatureType)
+		}
+		// the public key in certificate uses SHA-256 by default
+		cert, err := CertificateToData(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub, err := CertificateFromData(cert)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = VerifyData(pub, d)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, loaded := range roundTripKeys(t, key) {
+			if got := loaded.SignatureType(); got != test.sigType {
+				t.Fatalf("expect %d, got %d", test.sigType, got)
+			}
+		}
+	}
+}
+
+func TestRemoteSigner(t *testing.T) {
+	private := ecdsaKey.(*ECDSAKey).PrivateKey
+	var sent int
+	signer := &RemoteSigner{
+		Name: ecdsaKey.Locator(),
+		Type: SignatureTypeSHA256WithECDSA,
+		Send: func(msg []byte) ([]byte, error) {
+			sent++
+			digest := sha256.Sum256(msg)
+			return ecdsa.SignASN1(rand.Reader, private, digest[:])
+		},
+	}
+	d := &Data{Name: NewName("/A/B")}
+	err := SignData(signer, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent != 1 {
+		t.Fatalf("expect 1 request, got %d", sent)
+	}
+	err = VerifyData(ecdsaKey, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,262 @@
+package ndn
+
+import (
+	"errors"
+	"sort"
+)
+
+// Errors introduced by KeyChain.
+var (
+	ErrIdentityNotFound    = errors.New("identity not found")
+	ErrKeyNotFound         = errors.New("key not found")
+	ErrCertificateNotFound = errors.New("certificate not found")
+)
+
+// KeyChain manages identities, keys and certificates.
+//
+// Public information is stored in PIB, and private keys are stored in TPM.
+type KeyChain struct {
+	PIB PIB
+	TPM TPM
+}
+
+// NewKeyChain creates a new key chain.
+//
+// If pib or tpm is nil, an in-memory backend is used.
+func NewKeyChain(pib PIB, tpm TPM) *KeyChain {
+	if pib == nil {
+		pib = NewMemoryPIB()
+	}
+	if tpm == nil {
+		tpm = NewMemoryTPM()
+	}
+	return &KeyChain{
+		PIB: pib,
+		TPM: tpm,
+	}
+}
+
+// keyIdentity returns the identity of a key name,
+// which is the prefix before the last "KEY" component.
+func keyIdentity(name Name) Name {
+	for i := name.Len() - 1; i >= 0; i-- {
+		if string(name.Components[i]) == keyComponent {
+			return Name{Components: name.Components[:i]}
+		}
+	}
+	return name
+}
+
+// AddKey adds a key to the key chain.
+//
+// The private key is stored in TPM. For asymmetric keys,
+// the public key and its self-signed certificate are stored in PIB.
+func (kc *KeyChain) AddKey(key Key) error {
+	return kc.addKey(key, nil)
+}
+
+// addKey adds a key with its certificate.
+//
+// If cert is nil, a self-signed certificate is created.
+func (kc *KeyChain) addKey(key Key, cert *Data) error {
+	name := key.Locator()
+	identity := keyIdentity(name)
+	err := kc.PIB.AddIdentity(identity)
+	if err != nil {
+		return err
+	}
+	var public []byte
+	if key.SignatureType() != SignatureTypeSHA256WithHMAC {
+		public, err = key.Public()
+		if err != nil {
+			return err
+		}
+	}
+	err = kc.PIB.AddKey(identity, name, public)
+	if err != nil {
+		return err
+	}
+	err = kc.TPM.AddKey(key)
+	if err != nil {
+		return err
+	}
+	if public == nil {
+		return nil
+	}
+	if cert == nil {
+		cert, err = CertificateToData(key)
+		if err != nil {
+			return err
+		}
+	}
+	return kc.PIB.AddCertificate(name, cert)
+}
+
+// DeleteKey removes a key and its certificates from the key chain.
+func (kc *KeyChain) DeleteKey(name Name) error {
+	err := kc.PIB.DeleteKey(name)
+	if err != nil {
+		return err
+	}
+	return kc.TPM.DeleteKey(name)
+}
+
+// DeleteIdentity removes an identity and all its keys from the key chain.
+func (kc *KeyChain) DeleteIdentity(identity Name) error {
+	keys, err := kc.PIB.Keys(identity)
+	if err != nil {
+		return err
+	}
+	for _, name := range keys {
+		err = kc.TPM.DeleteKey(name)
+		if err != nil && err != ErrKeyNotFound {
+			return err
+		}
+	}
+	return kc.PIB.DeleteIdentity(identity)
+}
+
+// Certificate finds a certificate by either certificate name or key name.
+//
+// For a key name, the default certificate of the key is returned.
+func (kc *KeyChain) Certificate(name Name) (*Data, error) {
+	cert, err := kc.PIB.Certificate(name)
+	if err != ErrCertificateNotFound {
+		return cert, err
+	}
+	cert, err = kc.PIB.DefaultCertificate(name)
+	if err == ErrKeyNotFound {
+		return nil, ErrCertificateNotFound
+	}
+	return cert, err
+}
+
+// SigningKey finds the key that signs packets under name.
+//
+// The longest identity that is a prefix of name is chosen, and its default key is returned.
+// If no identity matches, the default key of the default identity is returned.
+func (kc *KeyChain) SigningKey(name Name) (Name, error) {
+	identities, err := kc.PIB.Identities()
+	if err != nil {
+		return Name{}, err
+	}
+	var identity Name
+	found := false
+	for _, id := range identities {
+		if isPrefix(id, name) && (!found || id.Len() > identity.Len()) {
+			identity = id
+			found = true
+		}
+	}
+	if !found {
+		identity, err = kc.PIB.DefaultIdentity()
+		if err != nil {
+			return Name{}, err
+		}
+	}
+	return kc.PIB.DefaultKey(identity)
+}
+
+// Sign signs a data packet with the key chosen by SigningKey from its name.
+func (kc *KeyChain) Sign(d *Data) error {
+	name, err := kc.SigningKey(d.Name)
+	if err != nil {
+		return err
+	}
+	return kc.SignData(name, d)
+}
+
+// SignMiddleware signs every data packet sent by handlers with Sign,
+// so that handlers can send unsigned data.
+//
+// The data packet is copied before it is signed.
+// If it cannot be signed, it is not sent, and onError is called with
+// the unsigned data packet and the error, unless onError is nil.
+func (kc *KeyChain) SignMiddleware(onError func(*Data, error)) Middleware {
+	return func(next InterestHandler) InterestHandler {
+		return InterestHandlerFunc(func(w Sender, i *Interest) {
+			next.ServeInterest(&signSender{Sender: w, kc: kc, onError: onError}, i)
+		})
+	}
+}
+
+// signSender signs data sent by a handler of SignMiddleware.
+type signSender struct {
+	Sender
+	kc      *KeyChain
+	onError func(*Data, error)
+}
+
+func (s *signSender) SendData(d *Data) {
+	signed := *d
+	err := s.kc.Sign(&signed)
+	if err != nil {
+		if s.onError != nil {
+			s.onError(d, err)
+		}
+		return
+	}
+	s.Sender.SendData(&signed)
+}
+
+// SignData signs a data packet with the named key.
+//
+// If name is an identity, its default key is used.
+// If name is empty, the data packet is signed with DigestSha256.
+func (kc *KeyChain) SignData(name Name, d *Data) error {
+	if name.Len() == 0 {
+		return SignData(nil, d)
+	}
+	key, err := kc.TPM.Key(name)
+	if err == ErrKeyNotFound {
+		var keyName Name
+		keyName, err = kc.PIB.DefaultKey(name)
+		if err != nil {
+			return err
+		}
+		key, err = kc.TPM.Key(keyName)
+	}
+	if err != nil {
+		return err
+	}
+	return SignData(key, d)
+}
+
+// VerifyData verifies a data packet with the key specified in its KeyLocator.
+//
+// Symmetric keys are found in TPM, and public keys are found in PIB.
+// Digest signatures are checked without a key.
+func (kc *KeyChain) VerifyData(d *Data) error {
+	name := d.SignatureInfo.KeyLocator.Name
+	switch d.SignatureInfo.SignatureType {
+	case SignatureTypeDigestSHA256, SignatureTypeDigestCRC32C:
+		return VerifyData(nil, d)
+	case SignatureTypeSHA256WithHMAC:
+		key, err := kc.TPM.Key(name)
+		if err != nil {
+			return err
+		}
+		return VerifyData(key, d)
+	}
+	cert, err := kc.Certificate(name)
+	if err != nil {
+		return err
+	}
+	key, err := CertificateFromData(cert)
+	if err != nil {
+		return err
+	}
+	return VerifyData(key, d)
+}
+
+func sortNames(names []Name) {
+	sort.Slice(names, func(i, j int) bool {
+		return names[i].Compare(names[j]) < 0
+	})
+}
+
+func sortCertificates(certs []*Data) {
+	sort.Slice(certs, func(i, j int) bool {
+		return certs[i].Name.Compare(certs[j].Name) < 0
+	})
+}
@@ -0,0 +1,312 @@
+package ndn
+
+import (
+	"context"
+	"crypto/elliptic"
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestKeyChain(t *testing.T) {
+	kc := NewKeyChain(nil, nil)
+	for _, key := range []Key{rsaKey, ecdsaKey, hmacKey} {
+		err := kc.AddKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	identities, err := kc.PIB.Identities()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(identities) != 3 {
+		t.Fatalf("expect 3 identities, got %v", identities)
+	}
+
+	for _, key := range []Key{rsaKey, ecdsaKey, hmacKey} {
+		d := &Data{Name: NewName("/hello")}
+		err = kc.SignData(key.Locator(), d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = kc.VerifyData(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = kc.DeleteIdentity(keyIdentity(rsaKey.Locator()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = kc.Certificate(rsaKey.Locator())
+	if err != ErrCertificateNotFound {
+		t.Fatalf("expect %v, got %v", ErrCertificateNotFound, err)
+	}
+}
+
+func TestFileKeyChain(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ndn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	kc := NewFileKeyChain(dir)
+	for _, key := range []Key{rsaKey, ecdsaKey, hmacKey} {
+		err := kc.AddKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// reopen
+	kc = NewFileKeyChain(dir)
+	for _, key := range []Key{rsaKey, ecdsaKey, hmacKey} {
+		key2, err := kc.TPM.Key(key.Locator())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(key, key2) {
+			t.Fatalf("expect %+v, got %+v", key, key2)
+		}
+	}
+	for _, key := range []Key{rsaKey, ecdsaKey} {
+		_, err := kc.Certificate(key.Locator())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	identity, err := kc.PIB.DefaultIdentity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := keyIdentity(rsaKey.Locator()); identity.Compare(want) != 0 {
+		t.Fatalf("expect %v, got %v", want, identity)
+	}
+}
+
+func TestKeyChainDefault(t *testing.T) {
+	kc := NewKeyChain(nil, nil)
+	var keys []*ECDSAKey
+	for _, identity := range []string{"/A", "/A/B", "/A/B"} {
+		key, err := GenerateECDSAKey(NewName(identity), elliptic.P256())
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = kc.AddKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, key)
+	}
+
+	identity, err := kc.PIB.DefaultIdentity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if identity.String() != "/A" {
+		t.Fatalf("expect /A, got %v", identity)
+	}
+
+	err = kc.PIB.SetDefaultKey(keys[2].Locator())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		name string
+		want Name
+	}{
+		{"/A/C", keys[0].Locator()},
+		{"/A/B/C", keys[2].Locator()},
+		{"/Z", keys[0].Locator()},
+	} {
+		d := &Data{Name: NewName(test.name)}
+		err := kc.Sign(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.SignatureInfo.KeyLocator.Name.Compare(test.want) != 0 {
+			t.Fatalf("expect %v, got %v", test.want, d.SignatureInfo.KeyLocator.Name)
+		}
+		err = kc.VerifyData(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = kc.PIB.SetDefaultIdentity(NewName("/Z"))
+	if err != ErrIdentityNotFound {
+		t.Fatalf("expect %v, got %v", ErrIdentityNotFound, err)
+	}
+}
+
+func TestKeyChainRollover(t *testing.T) {
+	kc := NewKeyChain(nil, nil)
+	identity := NewName("/A")
+	old, err := GenerateECDSAKey(identity, elliptic.P256())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = kc.AddKey(old)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var published []*Data
+	var switched bool
+	key, err := kc.Rollover(identity, RolloverOptions{
+		Publish: func(old, new *Data) {
+			published = append(published, old, new)
+		},
+		Switched: func(Key, Key) error {
+			switched = true
+			return nil
+		},
+		Overlap: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(published) != 2 || !switched {
+		t.Fatalf("expect published and switched, got %d certificates and %v", len(published), switched)
+	}
+	name, err := kc.PIB.DefaultKey(identity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name.Compare(key.Locator()) != 0 {
+		t.Fatalf("expect %v, got %v", key.Locator(), name)
+	}
+	cert, err := kc.Certificate(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cert.SignatureInfo.KeyLocator.Name.Compare(old.Locator()) != 0 {
+		t.Fatalf("expect issuer %v, got %v", old.Locator(), cert.SignatureInfo.KeyLocator.Name)
+	}
+
+	// the old key is removed after the overlap window
+	for i := 0; i < 100; i++ {
+		_, err = kc.TPM.Key(old.Locator())
+		if err == ErrKeyNotFound {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("expect %v, got %v", ErrKeyNotFound, err)
+}
+
+func TestSignMiddleware(t *testing.T) {
+	kc := NewKeyChain(nil, nil)
+	for _, identity := range []string{"/A", "/A/B"} {
+		key, err := GenerateECDSAKey(NewName(identity), elliptic.P256())
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = kc.AddKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var sent []*Data
+	h := kc.SignMiddleware(nil)(InterestHandlerFunc(func(w Sender, i *Interest) {
+		d := &Data{Name: i.Name}
+		sent = append(sent, d)
+		w.SendData(d)
+	}))
+	c1, c2 := net.Pipe()
+	producer := NewFace(c2, WithInterestHandler(h))
+	defer producer.Close()
+	consumer := NewFace(c1)
+	defer consumer.Close()
+
+	for _, test := range []struct {
+		in   string
+		want string
+	}{
+		{"/A/B/C", "/A/B"},
+		{"/A/C", "/A"},
+		// the default identity
+		{"/D", "/A"},
+	} {
+		d, err := SendInterestContext(context.Background(), consumer, &Interest{Name: NewName(test.in)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := keyIdentity(d.SignatureInfo.KeyLocator.Name).String(); got != test.want {
+			t.Fatalf("%s: expect %v, got %v", test.in, test.want, got)
+		}
+		err = kc.VerifyData(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, d := range sent {
+		if d.SignatureValue != nil {
+			t.Fatalf("expect %v unsigned", d.Name)
+		}
+	}
+}
+
+// failingKey is a key that cannot sign.
+type failingKey struct {
+	Key
+}
+
+var errFailingKey = errors.New("failing key")
+
+func (failingKey) Sign(interface{}) ([]byte, error) {
+	return nil, errFailingKey
+}
+
+func TestSignMiddlewareError(t *testing.T) {
+	kc := NewKeyChain(nil, nil)
+	key, err := GenerateECDSAKey(NewName("/A"), elliptic.P256())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = kc.AddKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = kc.TPM.AddKey(failingKey{Key: key})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type failure struct {
+		d   *Data
+		err error
+	}
+	failed := make(chan failure, 1)
+	h := kc.SignMiddleware(func(d *Data, err error) {
+		failed <- failure{d: d, err: err}
+	})(InterestHandlerFunc(func(w Sender, i *Interest) {
+		w.SendData(&Data{Name: i.Name})
+	}))
+	c1, c2 := net.Pipe()
+	producer := NewFace(c2, WithInterestHandler(h))
+	defer producer.Close()
+	consumer := NewFace(c1)
+	defer consumer.Close()
+
+	consumer.SendInterest(&Interest{Name: NewName("/A/B")})
+	got := <-failed
+	if got.err != errFailingKey {
+		t.Fatalf("expect %v, got %v", errFailingKey, got.err)
+	}
+	if got.d.Name.String() != "/A/B" {
+		t.Fatalf("expect /A/B, got %v", got.d.Name)
+	}
+	if n := producer.(StatsFace).Stats().OutData; n != 0 {
+		t.Fatalf("expect 0, got %v", n)
+	}
+}
@@ -0,0 +1,68 @@
+package ndn
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+)
+
+// newKeyName creates a key name /<identity>/KEY/<keyid> with a random 8-byte key id.
+func newKeyName(identity Name) (Name, error) {
+	id := make([]byte, 8)
+	_, err := rand.Read(id)
+	if err != nil {
+		return Name{}, err
+	}
+	return KeyName(identity, id), nil
+}
+
+// GenerateRSAKey generates a new RSA key of the given bit size for identity.
+func GenerateRSAKey(identity Name, bits int) (*RSAKey, error) {
+	name, err := newKeyName(identity)
+	if err != nil {
+		return nil, err
+	}
+	pri, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		return nil, err
+	}
+	return &RSAKey{
+		Name:       name,
+		PrivateKey: pri,
+	}, nil
+}
+
+// GenerateECDSAKey generates a new ECDSA key on the given curve for identity.
+func GenerateECDSAKey(identity Name, curve elliptic.Curve) (*ECDSAKey, error) {
+	name, err := newKeyName(identity)
+	if err != nil {
+		return nil, err
+	}
+	pri, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &ECDSAKey{
+		Name:       name,
+		PrivateKey: pri,
+	}, nil
+}
+
+// GenerateEd25519Key generates a new Ed25519 key for identity.
+func GenerateEd25519Key(identity Name) (*Ed25519Key, error) {
+	name, err := newKeyName(identity)
+	if err != nil {
+		return nil, err
+	}
+	pub, pri, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &Ed25519Key{
+		Name:       name,
+		PrivateKey: pri,
+		PublicKey:  pub,
+	}, nil
+}
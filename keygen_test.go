@@ -0,0 +1,58 @@
+package ndn
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"testing"
+)
+
+func TestGenerateKey(t *testing.T) {
+	identity := NewName("/ndn/guest/bob")
+	rsaKey, err := GenerateRSAKey(identity, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecdsaKey, err := GenerateECDSAKey(identity, elliptic.P256())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ed25519Key, err := GenerateEd25519Key(identity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []Key{rsaKey, ecdsaKey, ed25519Key} {
+		if identity.Compare(keyIdentity(key.Locator())) != 0 {
+			t.Fatalf("expect identity %v, got %v", identity, key.Locator())
+		}
+
+		d := &Data{Name: NewName("/hello")}
+		err = SignData(key, d)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		buf := new(bytes.Buffer)
+		err = EncodeCertificate(key, buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub, err := DecodeCertificate(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = VerifyData(pub, d)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		buf.Reset()
+		err = EncodePrivateKey(key, buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = DecodePrivateKey(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
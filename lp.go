@@ -0,0 +1,39 @@
+package ndn
+
+// lpPacket is an NDNLPv2 packet.
+//
+// Only Nack and unfragmented packets are handled, and other header fields
+// are decoded so that they can be skipped.
+// Nack and CachePolicy are slices to tell empty fields from absent ones.
+type lpPacket struct {
+	Sequence           uint64          `tlv:"81?"`
+	FragIndex          uint64          `tlv:"82?"`
+	FragCount          uint64          `tlv:"83?"`
+	PitToken           []byte          `tlv:"98?"`
+	Nack               []lpNack        `tlv:"800?"`
+	NextHopFaceID      uint64          `tlv:"816?"`
+	IncomingFaceID     uint64          `tlv:"817?"`
+	CachePolicy        []lpCachePolicy `tlv:"820?"`
+	CongestionMark     uint64          `tlv:"832?"`
+	Ack                []uint64        `tlv:"836?"`
+	TxSequence         uint64          `tlv:"840?"`
+	NonDiscovery       bool            `tlv:"844?"`
+	PrefixAnnouncement []byte          `tlv:"848?"`
+	Fragment           []byte          `tlv:"80?"`
+}
+
+type lpNack struct {
+	Reason uint64 `tlv:"801?"`
+}
+
+// lpCachePolicyNoCache asks not to cache the data packet in the fragment.
+const lpCachePolicyNoCache = 1
+
+type lpCachePolicy struct {
+	Type uint64 `tlv:"821"`
+}
+
+// noCache checks whether the data packet in the fragment should not be cached.
+func (p *lpPacket) noCache() bool {
+	return len(p.CachePolicy) != 0 && p.CachePolicy[0].Type == lpCachePolicyNoCache
+}
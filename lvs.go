@@ -0,0 +1,580 @@
+package ndn
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/go-ndn/lpm"
+)
+
+// ErrInvalidLVS is returned if a trust schema cannot be parsed.
+var ErrInvalidLVS = errors.New("invalid lvs trust schema")
+
+// LVSFunc is a user function in a trust schema.
+//
+// It reports whether a name component satisfies the function with args.
+type LVSFunc func(c lpm.Component, args []lpm.Component) bool
+
+// LVSSchema is a trust schema in Light VerSec (LVS) format,
+// which is shared with python-ndn and NDNd.
+//
+//	// comment
+//	#site: "a"/"blog"
+//	#KEY: "KEY"/_/_/_
+//	#root: #site/#KEY
+//	#admin: #site/"admin"/admin/#KEY <= #root
+//	#author: #site/role/author/#KEY & {role: "author" | "editor"} <= #admin
+//	#post: #site/"post"/author/year/_ & {year: $eq("2022", "2023")} <= #author
+//
+// A rule is a name pattern of components separated by "/".
+// A component is a quoted string that must be equal, "_" that matches anything,
+// a tag that matches anything and binds its value, or a reference to another rule.
+// A tag that appears twice in a pattern must match the same value.
+//
+// Constraints after "&" restrict tag values. Sets in braces are alternatives
+// joined by "|", and every constraint in a set must hold. A constraint is
+// satisfied by a quoted string, another tag, or a user function in Functions.
+//
+// Signing rules after "<=" list rules that the signer must match.
+// Tags that do not start with "_" must have the same value in both names.
+// The signer is usually named by KeyLocator, which is often a key name,
+// so the trailing components of a signing rule need not be present.
+//
+// A rule may be defined more than once as alternatives.
+// Rules that start with "_" are only used as references.
+//
+// See https://python-ndn.readthedocs.io/en/latest/src/lvs/lvs.html.
+type LVSSchema struct {
+	// Functions are user functions that are called as $name(args) in constraints.
+	Functions map[string]LVSFunc
+
+	rules map[string][]*lvsPattern
+	ids   []string
+}
+
+type lvsComponent struct {
+	value lpm.Component
+	// tag is empty for a string, and "_" matches anything.
+	tag  string
+	rule string
+}
+
+type lvsOption struct {
+	value lpm.Component
+	tag   string
+	fn    string
+	args  []lvsOption
+}
+
+type lvsConstraint struct {
+	tag     string
+	options []lvsOption
+}
+
+type lvsPattern struct {
+	components []lvsComponent
+	// constraints are alternative sets. If there is no set, any value is accepted.
+	constraints [][]lvsConstraint
+	signers     []string
+}
+
+// lvsFunctions are functions that are available in every schema.
+var lvsFunctions = map[string]LVSFunc{
+	// eq checks whether the component is equal to any argument.
+	"eq": func(c lpm.Component, args []lpm.Component) bool {
+		for _, arg := range args {
+			if bytes.Equal(c, arg) {
+				return true
+			}
+		}
+		return false
+	},
+}
+
+// ParseLVS parses a trust schema in LVS text format.
+func ParseLVS(schema []byte) (*LVSSchema, error) {
+	tokens, err := tokenizeLVS(schema)
+	if err != nil {
+		return nil, err
+	}
+	p := &lvsParser{tokens: tokens}
+	raw := make(map[string][]*lvsPattern)
+	for p.pos < len(p.tokens) {
+		tok := p.next()
+		if tok.kind != '#' || !p.accept(":") {
+			return nil, ErrInvalidLVS
+		}
+		pat, err := p.parsePattern()
+		if err != nil {
+			return nil, err
+		}
+		raw[tok.s] = append(raw[tok.s], pat)
+	}
+
+	s := &LVSSchema{
+		Functions: make(map[string]LVSFunc),
+		rules:     make(map[string][]*lvsPattern),
+	}
+	for name, f := range lvsFunctions {
+		s.Functions[name] = f
+	}
+	for id, pats := range raw {
+		_, err := s.expand(id, raw, make(map[string]bool))
+		if err != nil {
+			return nil, err
+		}
+		for _, pat := range pats {
+			for _, signer := range pat.signers {
+				if _, ok := raw[signer]; !ok {
+					return nil, ErrInvalidLVS
+				}
+			}
+		}
+		if !strings.HasPrefix(id, "_") {
+			s.ids = append(s.ids, id)
+		}
+	}
+	sort.Strings(s.ids)
+	return s, nil
+}
+
+// expand resolves rule references in the patterns of a rule.
+func (s *LVSSchema) expand(id string, raw map[string][]*lvsPattern, visiting map[string]bool) ([]*lvsPattern, error) {
+	if pats, ok := s.rules[id]; ok {
+		return pats, nil
+	}
+	alts, ok := raw[id]
+	if !ok || visiting[id] {
+		return nil, ErrInvalidLVS
+	}
+	visiting[id] = true
+	defer delete(visiting, id)
+
+	var pats []*lvsPattern
+	for _, alt := range alts {
+		partial := []*lvsPattern{{
+			constraints: alt.constraints,
+			signers:     alt.signers,
+		}}
+		for _, c := range alt.components {
+			if c.rule == "" {
+				for _, pat := range partial {
+					pat.components = append(pat.components, c)
+				}
+				continue
+			}
+			sub, err := s.expand(c.rule, raw, visiting)
+			if err != nil {
+				return nil, err
+			}
+			var next []*lvsPattern
+			for _, pat := range partial {
+				for _, ref := range sub {
+					components := make([]lvsComponent, 0, len(pat.components)+len(ref.components))
+					components = append(components, pat.components...)
+					components = append(components, ref.components...)
+					next = append(next, &lvsPattern{
+						components:  components,
+						constraints: lvsAnd(pat.constraints, ref.constraints),
+						signers:     pat.signers,
+					})
+				}
+			}
+			partial = next
+		}
+		pats = append(pats, partial...)
+	}
+	s.rules[id] = pats
+	return pats, nil
+}
+
+// lvsAnd combines two sets of alternatives, so that both must be satisfied.
+func lvsAnd(a, b [][]lvsConstraint) [][]lvsConstraint {
+	if len(a) == 0 {
+		return b
+	}
+	if len(b) == 0 {
+		return a
+	}
+	var sets [][]lvsConstraint
+	for _, x := range a {
+		for _, y := range b {
+			set := make([]lvsConstraint, 0, len(x)+len(y))
+			set = append(set, x...)
+			set = append(set, y...)
+			sets = append(sets, set)
+		}
+	}
+	return sets
+}
+
+// Match returns the rules that a name matches.
+func (s *LVSSchema) Match(name Name) []string {
+	var ids []string
+	for _, id := range s.ids {
+		if len(s.matchRule(id, name, false)) > 0 {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// Check checks whether a packet named name can be signed by signer.
+//
+// It returns ErrNoRule if name does not match any rule, and
+// ErrRuleViolation if signer does not match any signing rule.
+func (s *LVSSchema) Check(name, signer Name) error {
+	matched := false
+	for _, id := range s.ids {
+		for _, tags := range s.matchRule(id, name, false) {
+			matched = true
+			for _, signerID := range tags.pattern.signers {
+				for _, signerTags := range s.matchRule(signerID, signer, true) {
+					if lvsConsistent(tags.values, signerTags.values) {
+						return nil
+					}
+				}
+			}
+		}
+	}
+	if !matched {
+		return ErrNoRule
+	}
+	return ErrRuleViolation
+}
+
+type lvsMatch struct {
+	pattern *lvsPattern
+	values  map[string]lpm.Component
+}
+
+// matchRule returns all patterns of a rule that match name.
+//
+// If prefix is true, name can be shorter than a pattern,
+// and the missing components must not be strings.
+func (s *LVSSchema) matchRule(id string, name Name, prefix bool) (matches []lvsMatch) {
+	for _, pat := range s.rules[id] {
+		values, ok := s.match(pat, name, prefix)
+		if ok {
+			matches = append(matches, lvsMatch{
+				pattern: pat,
+				values:  values,
+			})
+		}
+	}
+	return
+}
+
+func (s *LVSSchema) match(pat *lvsPattern, name Name, prefix bool) (map[string]lpm.Component, bool) {
+	l := name.Len()
+	if l > len(pat.components) || !prefix && l < len(pat.components) {
+		return nil, false
+	}
+	values := make(map[string]lpm.Component)
+	for i, c := range pat.components {
+		if i >= l {
+			if c.tag == "" {
+				return nil, false
+			}
+			continue
+		}
+		v := name.Components[i]
+		switch c.tag {
+		case "":
+			if !bytes.Equal(c.value, v) {
+				return nil, false
+			}
+		case "_":
+		default:
+			if old, ok := values[c.tag]; ok && !bytes.Equal(old, v) {
+				return nil, false
+			}
+			values[c.tag] = v
+		}
+	}
+	if len(pat.constraints) == 0 {
+		return values, true
+	}
+	for _, set := range pat.constraints {
+		if s.satisfy(set, values, prefix) {
+			return values, true
+		}
+	}
+	return nil, false
+}
+
+// satisfy checks whether all constraints in a set hold.
+//
+// If prefix is true, constraints on missing tags are ignored.
+func (s *LVSSchema) satisfy(set []lvsConstraint, values map[string]lpm.Component, prefix bool) bool {
+	for _, cons := range set {
+		v, ok := values[cons.tag]
+		if !ok {
+			if prefix {
+				continue
+			}
+			return false
+		}
+		ok = false
+		for _, opt := range cons.options {
+			if s.option(opt, v, values) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *LVSSchema) option(opt lvsOption, v lpm.Component, values map[string]lpm.Component) bool {
+	switch {
+	case opt.fn != "":
+		f, ok := s.Functions[opt.fn]
+		if !ok {
+			return false
+		}
+		args := make([]lpm.Component, len(opt.args))
+		for i, arg := range opt.args {
+			if arg.tag == "" {
+				args[i] = arg.value
+				continue
+			}
+			args[i], ok = values[arg.tag]
+			if !ok {
+				return false
+			}
+		}
+		return f(v, args)
+	case opt.tag != "":
+		w, ok := values[opt.tag]
+		return ok && bytes.Equal(v, w)
+	default:
+		return bytes.Equal(v, opt.value)
+	}
+}
+
+// lvsConsistent checks whether tags that are bound in both names have the same value.
+func lvsConsistent(a, b map[string]lpm.Component) bool {
+	for tag, v := range a {
+		if strings.HasPrefix(tag, "_") {
+			continue
+		}
+		if w, ok := b[tag]; ok && !bytes.Equal(v, w) {
+			return false
+		}
+	}
+	return true
+}
+
+type lvsToken struct {
+	// kind is '"' for strings, 'a' for tags, '#' for rules,
+	// '$' for functions, and 0 for punctuation.
+	kind byte
+	s    string
+}
+
+func isLVSIdent(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '_'
+}
+
+func tokenizeLVS(b []byte) ([]lvsToken, error) {
+	var tokens []lvsToken
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line := scanner.Text()
+		for i := 0; i < len(line); {
+			switch c := line[i]; {
+			case c == ' ' || c == '\t' || c == '\r':
+				i++
+			case strings.HasPrefix(line[i:], "//"):
+				i = len(line)
+			case strings.HasPrefix(line[i:], "<="):
+				tokens = append(tokens, lvsToken{s: "<="})
+				i += 2
+			case strings.ContainsRune(":/&|{},()", rune(c)):
+				tokens = append(tokens, lvsToken{s: string(c)})
+				i++
+			case c == '"':
+				end := strings.IndexByte(line[i+1:], '"')
+				if end < 0 {
+					return nil, ErrInvalidLVS
+				}
+				tokens = append(tokens, lvsToken{kind: '"', s: line[i+1 : i+1+end]})
+				i += end + 2
+			case c == '#' || c == '$' || isLVSIdent(c):
+				start := i
+				if !isLVSIdent(c) {
+					start++
+				}
+				i = start
+				for i < len(line) && isLVSIdent(line[i]) {
+					i++
+				}
+				if i == start {
+					return nil, ErrInvalidLVS
+				}
+				kind := byte('a')
+				if !isLVSIdent(c) {
+					kind = c
+				}
+				tokens = append(tokens, lvsToken{kind: kind, s: line[start:i]})
+			default:
+				return nil, ErrInvalidLVS
+			}
+		}
+	}
+	err := scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+type lvsParser struct {
+	tokens []lvsToken
+	pos    int
+}
+
+func (p *lvsParser) next() lvsToken {
+	if p.pos >= len(p.tokens) {
+		return lvsToken{}
+	}
+	tok := p.tokens[p.pos]
+	p.pos++
+	return tok
+}
+
+// accept consumes punctuation s if it is the next token.
+func (p *lvsParser) accept(s string) bool {
+	if p.pos < len(p.tokens) && p.tokens[p.pos].kind == 0 && p.tokens[p.pos].s == s {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *lvsParser) parsePattern() (*lvsPattern, error) {
+	pat := new(lvsPattern)
+	p.accept("/")
+	for {
+		tok := p.next()
+		switch tok.kind {
+		case '"':
+			pat.components = append(pat.components, lvsComponent{value: unescapeComponent(tok.s)})
+		case 'a':
+			pat.components = append(pat.components, lvsComponent{tag: tok.s})
+		case '#':
+			pat.components = append(pat.components, lvsComponent{rule: tok.s})
+		default:
+			return nil, ErrInvalidLVS
+		}
+		if !p.accept("/") {
+			break
+		}
+	}
+	for p.accept("&") {
+		var sets [][]lvsConstraint
+		for {
+			set, err := p.parseConstraintSet()
+			if err != nil {
+				return nil, err
+			}
+			sets = append(sets, set)
+			if !p.accept("|") {
+				break
+			}
+		}
+		pat.constraints = lvsAnd(pat.constraints, sets)
+	}
+	if p.accept("<=") {
+		for {
+			tok := p.next()
+			if tok.kind != '#' {
+				return nil, ErrInvalidLVS
+			}
+			pat.signers = append(pat.signers, tok.s)
+			if !p.accept("|") {
+				break
+			}
+		}
+	}
+	return pat, nil
+}
+
+func (p *lvsParser) parseConstraintSet() ([]lvsConstraint, error) {
+	if !p.accept("{") {
+		return nil, ErrInvalidLVS
+	}
+	var set []lvsConstraint
+	for {
+		tok := p.next()
+		if tok.kind != 'a' || !p.accept(":") {
+			return nil, ErrInvalidLVS
+		}
+		cons := lvsConstraint{tag: tok.s}
+		for {
+			opt, err := p.parseOption()
+			if err != nil {
+				return nil, err
+			}
+			cons.options = append(cons.options, opt)
+			if !p.accept("|") {
+				break
+			}
+		}
+		set = append(set, cons)
+		if !p.accept(",") {
+			break
+		}
+	}
+	if !p.accept("}") {
+		return nil, ErrInvalidLVS
+	}
+	return set, nil
+}
+
+func (p *lvsParser) parseOption() (opt lvsOption, err error) {
+	tok := p.next()
+	switch tok.kind {
+	case '"':
+		opt.value = unescapeComponent(tok.s)
+	case 'a':
+		opt.tag = tok.s
+	case '$':
+		opt.fn = tok.s
+		if !p.accept("(") {
+			err = ErrInvalidLVS
+			return
+		}
+		if p.accept(")") {
+			return
+		}
+		for {
+			arg := p.next()
+			switch arg.kind {
+			case '"':
+				opt.args = append(opt.args, lvsOption{value: unescapeComponent(arg.s)})
+			case 'a':
+				opt.args = append(opt.args, lvsOption{tag: arg.s})
+			default:
+				err = ErrInvalidLVS
+				return
+			}
+			if !p.accept(",") {
+				break
+			}
+		}
+		if !p.accept(")") {
+			err = ErrInvalidLVS
+		}
+	default:
+		err = ErrInvalidLVS
+	}
+	return
+}
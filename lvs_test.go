@@ -0,0 +1,143 @@
+package ndn
+
+import (
+	"crypto/elliptic"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const testLVS = `
+// site prefix is /a/blog
+#site: "a"/"blog"
+#KEY: "KEY"/_/_/_
+#root: #site/#KEY
+#admin: #site/"admin"/admin/#KEY <= #root
+#author: #site/role/author/#KEY & {role: "author"} | {role: "editor"} <= #admin
+#post: #site/"post"/author/year/_ & {year: $eq("2022", "2023")} <= #author
+#_unused: "x"
+`
+
+func TestLVS(t *testing.T) {
+	s, err := ParseLVS([]byte(testLVS))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		name string
+		want []string
+	}{
+		{"/a/blog/KEY/1/self/1", []string{"root"}},
+		{"/a/blog/admin/alice/KEY/1/root/1", []string{"admin"}},
+		{"/a/blog/editor/bob/KEY/1/admin/1", []string{"author"}},
+		{"/a/blog/reader/bob/KEY/1/admin/1", nil},
+		{"/a/blog/post/bob/2022/hello", []string{"post"}},
+		{"/a/blog/post/bob/2021/hello", nil},
+		{"/x", nil},
+	} {
+		got := s.Match(NewName(test.name))
+		if !reflect.DeepEqual(got, test.want) {
+			t.Fatalf("expect %v, got %v", test.want, got)
+		}
+	}
+
+	for _, test := range []struct {
+		name, signer string
+		want         error
+	}{
+		{"/a/blog/post/bob/2022/hello", "/a/blog/author/bob/KEY/1", nil},
+		{"/a/blog/post/bob/2022/hello", "/a/blog/author/bob/KEY/1/admin/1", nil},
+		{"/a/blog/post/bob/2022/hello", "/a/blog/author/alice/KEY/1", ErrRuleViolation},
+		{"/a/blog/post/bob/2022/hello", "/a/blog/admin/bob/KEY/1", ErrRuleViolation},
+		{"/a/blog/author/bob/KEY/1/admin/1", "/a/blog/admin/alice/KEY/2", nil},
+		{"/a/blog/admin/alice/KEY/2/root/1", "/a/blog/KEY/3", nil},
+		{"/a/blog/reader/bob/KEY/1/admin/1", "/a/blog/admin/alice/KEY/2", ErrNoRule},
+	} {
+		err := s.Check(NewName(test.name), NewName(test.signer))
+		if err != test.want {
+			t.Fatalf("%s <= %s: expect %v, got %v", test.name, test.signer, test.want, err)
+		}
+	}
+
+	for _, schema := range []string{
+		`#a: #b`,
+		`#a: "x"/#a`,
+		`#a: "x" <= #b`,
+		`#a: "x" & {y: "z"`,
+		`a: "x"`,
+	} {
+		_, err := ParseLVS([]byte(schema))
+		if err != ErrInvalidLVS {
+			t.Fatalf("%s: expect %v, got %v", schema, ErrInvalidLVS, err)
+		}
+	}
+}
+
+func TestValidatorLVS(t *testing.T) {
+	rootKey, err := GenerateECDSAKey(NewName("/a/blog"), elliptic.P256())
+	if err != nil {
+		t.Fatal(err)
+	}
+	adminKey, err := GenerateECDSAKey(NewName("/a/blog/admin/alice"), elliptic.P256())
+	if err != nil {
+		t.Fatal(err)
+	}
+	anchor, err := SelfSign(rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	public, err := adminKey.Public()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	cert, err := IssueCertificate(&Certificate{
+		Name:      adminKey.Locator(),
+		IssuerID:  []byte("root"),
+		Version:   1,
+		PublicKey: public,
+		NotBefore: now.Add(-time.Hour),
+		NotAfter:  now.Add(time.Hour),
+	}, rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v := NewValidatorConfig()
+	v.Schema, err = ParseLVS([]byte(`
+#KEY: "KEY"/_/_/_
+#root: "a"/"blog"/#KEY
+#admin: "a"/"blog"/"admin"/admin/#KEY <= #root
+#article: "a"/"blog"/"article"/_ <= #admin
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = v.AddTrustAnchor(anchor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.Fetch = func(Name) (*Data, error) {
+		return cert, nil
+	}
+
+	for _, test := range []struct {
+		name string
+		key  Key
+		want error
+	}{
+		{"/a/blog/article/hello", adminKey, nil},
+		{"/a/blog/article/hello", rootKey, ErrRuleViolation},
+		{"/a/blog/hello", adminKey, ErrNoRule},
+	} {
+		d := &Data{Name: NewName(test.name)}
+		err = SignData(test.key, d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = v.ValidateData(d)
+		if err != test.want {
+			t.Fatalf("expect %v, got %v", test.want, err)
+		}
+	}
+}
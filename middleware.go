@@ -0,0 +1,29 @@
+package ndn
+
+// Middleware wraps an InterestHandler to add behavior to producers,
+// such as logging, signing or segmentation, without changing the handler.
+//
+// To change outgoing data, a middleware can pass a Sender that wraps w to the next handler.
+type Middleware func(InterestHandler) InterestHandler
+
+// ChainHandler wraps h with mws.
+// The first middleware is the outermost, so it sees interests first.
+func ChainHandler(h InterestHandler, mws ...Middleware) InterestHandler {
+	for k := len(mws) - 1; k >= 0; k-- {
+		h = mws[k](h)
+	}
+	return h
+}
+
+// FaceMiddleware wraps a face to add behavior to consumers,
+// like NewRetransmittingFace and NewValidatingFace.
+type FaceMiddleware func(Face) Face
+
+// ChainFace wraps f with mws.
+// The first middleware is the outermost, so it sees interests first.
+func ChainFace(f Face, mws ...FaceMiddleware) Face {
+	for k := len(mws) - 1; k >= 0; k-- {
+		f = mws[k](f)
+	}
+	return f
+}
@@ -0,0 +1,76 @@
+package ndn
+
+import (
+	"sync"
+
+	"github.com/go-ndn/lpm"
+)
+
+// Mux is an InterestHandler that dispatches incoming interests by name prefix,
+// like http.ServeMux.
+//
+// If more than one prefix matches, the longest one is used, so a handler of
+// "/" handles every interest that other handlers do not match.
+// Interests that no prefix matches are ignored.
+//
+// The zero value is ready to use, and Mux is safe for concurrent use.
+type Mux struct {
+	mu       sync.Mutex
+	handlers handlerMatcher
+	mws      []Middleware
+}
+
+// NewMux creates a new Mux.
+func NewMux() *Mux {
+	return new(Mux)
+}
+
+// Handle handles interests under prefix with h.
+// If prefix is already handled, h replaces the previous handler.
+func (mux *Mux) Handle(prefix string, h InterestHandler) {
+	ph := &prefixHandler{InterestHandler: h}
+	mux.mu.Lock()
+	mux.handlers.Update(NewName(prefix).Components, func(*prefixHandler) *prefixHandler {
+		return ph
+	}, false)
+	mux.mu.Unlock()
+}
+
+// HandleFunc handles interests under prefix with fn.
+func (mux *Mux) HandleFunc(prefix string, fn func(w Sender, i *Interest)) {
+	mux.Handle(prefix, InterestHandlerFunc(fn))
+}
+
+// Use wraps every handler of mux with mws when interests are dispatched.
+// Middlewares of earlier calls are outer ones.
+func (mux *Mux) Use(mws ...Middleware) {
+	mux.mu.Lock()
+	mux.mws = append(mux.mws, mws...)
+	mux.mu.Unlock()
+}
+
+// Handler returns the handler of the longest prefix of name,
+// or nil if no prefix matches.
+// The handler is wrapped with middlewares of Use.
+func (mux *Mux) Handler(name Name) InterestHandler {
+	var ph *prefixHandler
+	mux.mu.Lock()
+	mws := mux.mws
+	// every existing handler along name is visited from the root.
+	mux.handlers.UpdateAll(name.Components, func(_ []lpm.Component, v *prefixHandler) *prefixHandler {
+		ph = v
+		return v
+	}, true)
+	mux.mu.Unlock()
+	if ph == nil {
+		return nil
+	}
+	return ChainHandler(ph.InterestHandler, mws...)
+}
+
+// ServeInterest dispatches i to the handler of the longest prefix of its name.
+func (mux *Mux) ServeInterest(w Sender, i *Interest) {
+	if h := mux.Handler(i.Name); h != nil {
+		h.ServeInterest(w, i)
+	}
+}
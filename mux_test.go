@@ -0,0 +1,98 @@
+package ndn
+
+import (
+	"fmt"
+	"net"
+	"testing"
+)
+
+func TestMux(t *testing.T) {
+	mux := NewMux()
+	reply := func(content string) func(Sender, *Interest) {
+		return func(w Sender, i *Interest) {
+			w.SendData(&Data{Name: i.Name, Content: []byte(content)})
+		}
+	}
+	mux.HandleFunc("/app", reply("app"))
+	mux.HandleFunc("/app/users", reply("users"))
+	mux.HandleFunc("/other", reply("old"))
+	mux.HandleFunc("/other", reply("other"))
+
+	c1, c2 := net.Pipe()
+	producer := NewFace(c2, WithInterestHandler(mux))
+	defer producer.Close()
+	consumer := NewFace(c1)
+	defer consumer.Close()
+
+	for _, test := range []struct {
+		in   string
+		want string
+	}{
+		{"/app", "app"},
+		{"/app/users/alice", "users"},
+		{"/app/usersx", "app"},
+		{"/other/1", "other"},
+	} {
+		d, ok := <-consumer.SendInterest(&Interest{Name: NewName(test.in)})
+		if !ok {
+			t.Fatalf("%s: %v", test.in, ErrTimeout)
+		}
+		if string(d.Content) != test.want {
+			t.Fatalf("%s: expect %v, got %s", test.in, test.want, d.Content)
+		}
+	}
+	if mux.Handler(NewName("/none")) != nil {
+		t.Fatal("expect no handler")
+	}
+	_, ok := <-consumer.SendInterest(&Interest{Name: NewName("/none"), LifeTime: 50})
+	if ok {
+		t.Fatal("expect timeout")
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next InterestHandler) InterestHandler {
+			return InterestHandlerFunc(func(w Sender, i *Interest) {
+				calls = append(calls, name)
+				next.ServeInterest(w, i)
+			})
+		}
+	}
+	mux := NewMux()
+	mux.Use(trace("1"), trace("2"))
+	mux.HandleFunc("/A", func(w Sender, i *Interest) {
+		calls = append(calls, "handler")
+	})
+	mux.Use(trace("3"))
+	h := ChainHandler(mux, trace("0"))
+	h.ServeInterest(nil, &Interest{Name: NewName("/A/B")})
+	if fmt.Sprint(calls) != "[0 1 2 3 handler]" {
+		t.Fatalf("expect %v, got %v", "[0 1 2 3 handler]", calls)
+	}
+
+	calls = nil
+	h.ServeInterest(nil, &Interest{Name: NewName("/B")})
+	if fmt.Sprint(calls) != "[0]" {
+		t.Fatalf("expect %v, got %v", "[0]", calls)
+	}
+
+	c1, _ := net.Pipe()
+	f := NewFace(c1)
+	defer f.Close()
+	var faces []string
+	wrap := func(name string) FaceMiddleware {
+		return func(f Face) Face {
+			faces = append(faces, name)
+			return f
+		}
+	}
+	if ChainFace(f, wrap("outer"), wrap("inner")) != f {
+		t.Fatal("expect the same face")
+	}
+	// the innermost is applied first
+	if fmt.Sprint(faces) != "[inner outer]" {
+		t.Fatalf("expect %v, got %v", "[inner outer]", faces)
+	}
+}
@@ -0,0 +1,187 @@
+package ndn
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"errors"
+
+	"github.com/go-ndn/lpm"
+	"github.com/go-ndn/tlv"
+)
+
+// ErrInvalidNACName is returned if a name does not follow NAC naming conventions.
+var ErrInvalidNACName = errors.New("invalid nac key name")
+
+// NAC name components.
+//
+// KEK is named /<identity>/NAC/<dataset>/KEK/<key-id>.
+// KDK is named /<identity>/NAC/<dataset>/KDK/<key-id>/ENCRYPTED-BY/<member key name>.
+// CK is named /<prefix>/CK/<key-id>, and
+// CK data is named /<prefix>/CK/<key-id>/ENCRYPTED-BY/<KEK name>.
+//
+// See https://named-data.net/doc/NAC/current/spec.html.
+const (
+	nacComponentNAC         = "NAC"
+	nacComponentKEK         = "KEK"
+	nacComponentKDK         = "KDK"
+	nacComponentCK          = "CK"
+	nacComponentEncryptedBy = "ENCRYPTED-BY"
+)
+
+// appendName appends components to name without modifying name.
+func appendName(name Name, components ...lpm.Component) Name {
+	c := make([]lpm.Component, 0, name.Len()+len(components))
+	c = append(c, name.Components...)
+	c = append(c, components...)
+	return Name{Components: c}
+}
+
+// nacKeyName splits /<prefix>/<keyType>/<key-id> into prefix and key id.
+func nacKeyName(name Name, keyType string) (prefix Name, keyID lpm.Component, err error) {
+	l := name.Len()
+	if l < 2 || string(name.Components[l-2]) != keyType {
+		err = ErrInvalidNACName
+		return
+	}
+	prefix = Name{Components: name.Components[:l-2]}
+	keyID = name.Components[l-1]
+	return
+}
+
+// kdkName creates KDK name from KEK name for a member.
+func kdkName(kek Name, member Name) (Name, error) {
+	prefix, keyID, err := nacKeyName(kek, nacComponentKEK)
+	if err != nil {
+		return Name{}, err
+	}
+	name := appendName(prefix, lpm.Component(nacComponentKDK), keyID, lpm.Component(nacComponentEncryptedBy))
+	return appendName(name, member.Components...), nil
+}
+
+// encryptedByName splits /<name>/ENCRYPTED-BY/<key name>.
+func encryptedByName(name Name) (prefix, key Name, err error) {
+	for i := name.Len() - 1; i >= 0; i-- {
+		if string(name.Components[i]) == nacComponentEncryptedBy {
+			prefix = Name{Components: name.Components[:i]}
+			key = Name{Components: name.Components[i+1:]}
+			return
+		}
+	}
+	err = ErrInvalidNACName
+	return
+}
+
+// encryptOAEP encrypts with RSA-OAEP and SHA-1, which is the default of ndn-cxx.
+func encryptOAEP(public []byte, plaintext []byte) ([]byte, error) {
+	pub, err := x509.ParsePKIXPublicKey(public)
+	if err != nil {
+		return nil, err
+	}
+	rsaPub, ok := pub.(*rsa.PublicKey)
+	if !ok {
+		return nil, ErrNotSupported
+	}
+	return rsa.EncryptOAEP(sha1.New(), rand.Reader, rsaPub, plaintext, nil)
+}
+
+// decryptOAEP decrypts with RSA-OAEP and SHA-1.
+func decryptOAEP(key Key, ciphertext []byte) ([]byte, error) {
+	pri, err := privateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	rsaPri, ok := pri.(*rsa.PrivateKey)
+	if !ok {
+		return nil, ErrNotSupported
+	}
+	plaintext, err := rsa.DecryptOAEP(sha1.New(), rand.Reader, rsaPri, ciphertext, nil)
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+	return plaintext, nil
+}
+
+// nacPasswordSize is the size of the random password protecting KDK.
+const nacPasswordSize = 32
+
+// newKDKData creates KDK data for a member from the member's certificate.
+//
+// The content is EncryptedContent, where Payload is SafeBag of kdk
+// protected with a random password, and PayloadKey is the password
+// encrypted with the member's public key.
+func newKDKData(kek Name, kdk *RSAKey, member *Data, signer Key) (*Data, error) {
+	cert, err := ParseCertificate(member)
+	if err != nil {
+		return nil, err
+	}
+	name, err := kdkName(kek, cert.Name)
+	if err != nil {
+		return nil, err
+	}
+	password := make([]byte, nacPasswordSize)
+	_, err = rand.Read(password)
+	if err != nil {
+		return nil, err
+	}
+	payloadKey, err := encryptOAEP(cert.PublicKey, password)
+	if err != nil {
+		return nil, err
+	}
+	kdkCert, err := CertificateToData(kdk)
+	if err != nil {
+		return nil, err
+	}
+	keyBag, err := encryptPKCS8(kdk, password)
+	if err != nil {
+		return nil, err
+	}
+	buf := new(bytes.Buffer)
+	err = (&SafeBag{
+		Certificate:     *kdkCert,
+		EncryptedKeyBag: keyBag,
+	}).WriteTo(tlv.NewWriter(buf))
+	if err != nil {
+		return nil, err
+	}
+	content, err := marshalEncryptedContent(&EncryptedContent{
+		Payload:    buf.Bytes(),
+		PayloadKey: payloadKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+	d := &Data{
+		Name:    name,
+		Content: content,
+	}
+	err = SignData(signer, d)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// decryptKDKData decrypts KDK with the member's private key.
+func decryptKDKData(d *Data, member Key) (Key, error) {
+	ec, err := unmarshalEncryptedContent(d.Content)
+	if err != nil {
+		return nil, err
+	}
+	password, err := decryptOAEP(member, ec.PayloadKey)
+	if err != nil {
+		return nil, err
+	}
+	bag := new(SafeBag)
+	err = bag.ReadFrom(tlv.NewReader(bytes.NewReader(ec.Payload)))
+	if err != nil {
+		return nil, err
+	}
+	cert, err := ParseCertificate(&bag.Certificate)
+	if err != nil {
+		return nil, err
+	}
+	return decryptPKCS8(cert.Name, bag.EncryptedKeyBag, password)
+}
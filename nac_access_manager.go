@@ -0,0 +1,141 @@
+package ndn
+
+import (
+	"sync"
+
+	"github.com/go-ndn/lpm"
+)
+
+// kekFreshnessPeriod is the freshness period of KEK data in milliseconds.
+const kekFreshnessPeriod = 3600000 // 1 hour
+
+// AccessManager controls who can decrypt a dataset with name-based access control.
+//
+// It owns a KEK/KDK key pair. KEK is published for Encryptor, and
+// KDK is published for every member, encrypted with the member's public key.
+type AccessManager struct {
+	// Prefix is /<identity>/NAC/<dataset>.
+	Prefix Name
+	// Key signs KEK and KDK data.
+	Key Key
+
+	kdk     *RSAKey
+	kek     *Data
+	members map[string]*Data
+	kdks    map[string]*Data
+	sync.Mutex
+}
+
+// NewAccessManager creates an access manager with a new KEK/KDK key pair.
+func NewAccessManager(identity, dataset Name, key Key) (*AccessManager, error) {
+	prefix := appendName(identity, lpm.Component(nacComponentNAC))
+	m := &AccessManager{
+		Prefix:  appendName(prefix, dataset.Components...),
+		Key:     key,
+		members: make(map[string]*Data),
+	}
+	err := m.Rotate()
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Rotate creates a new KEK/KDK key pair, and encrypts KDK for current members.
+//
+// Members removed before Rotate cannot decrypt content encrypted with the new KEK.
+func (m *AccessManager) Rotate() error {
+	kdk, err := GenerateRSAKey(m.Prefix, 2048)
+	if err != nil {
+		return err
+	}
+	public, err := kdk.Public()
+	if err != nil {
+		return err
+	}
+	kek := &Data{
+		Name: appendName(m.Prefix, lpm.Component(nacComponentKEK), kdk.Name.Components[kdk.Name.Len()-1]),
+		MetaInfo: MetaInfo{
+			FreshnessPeriod: kekFreshnessPeriod,
+		},
+		Content: public,
+	}
+	err = SignData(m.Key, kek)
+	if err != nil {
+		return err
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	kdks := make(map[string]*Data)
+	for name, cert := range m.members {
+		d, err := newKDKData(kek.Name, kdk, cert, m.Key)
+		if err != nil {
+			return err
+		}
+		kdks[name] = d
+	}
+	m.kdk = kdk
+	m.kek = kek
+	m.kdks = kdks
+	return nil
+}
+
+// AddMember grants access to the key in cert.
+func (m *AccessManager) AddMember(cert *Data) error {
+	c, err := ParseCertificate(cert)
+	if err != nil {
+		return err
+	}
+	m.Lock()
+	defer m.Unlock()
+	d, err := newKDKData(m.kek.Name, m.kdk, cert, m.Key)
+	if err != nil {
+		return err
+	}
+	name := c.Name.String()
+	m.members[name] = cert
+	m.kdks[name] = d
+	return nil
+}
+
+// RemoveMember stops publishing KDK for the key name.
+//
+// Rotate should be invoked afterwards, because the member may still have the current KDK.
+func (m *AccessManager) RemoveMember(key Name) {
+	m.Lock()
+	defer m.Unlock()
+	delete(m.members, key.String())
+	delete(m.kdks, key.String())
+}
+
+// Members returns key names of current members.
+func (m *AccessManager) Members() []Name {
+	m.Lock()
+	defer m.Unlock()
+	names := make([]Name, 0, len(m.members))
+	for _, cert := range m.members {
+		c, err := ParseCertificate(cert)
+		if err != nil {
+			continue
+		}
+		names = append(names, c.Name)
+	}
+	sortNames(names)
+	return names
+}
+
+// ServeInterest returns KEK or KDK data that matches i.
+func (m *AccessManager) ServeInterest(i *Interest) (*Data, error) {
+	m.Lock()
+	defer m.Unlock()
+	if isPrefix(i.Name, m.kek.Name) {
+		return m.kek, nil
+	}
+	for _, d := range m.kdks {
+		if isPrefix(i.Name, d.Name) {
+			return d, nil
+		}
+	}
+	return nil, ErrInvalidNACName
+}
@@ -0,0 +1,149 @@
+package ndn
+
+import (
+	"context"
+	"sync"
+
+	"github.com/go-ndn/lpm"
+)
+
+// Decryptor decrypts content for name-based access control.
+//
+// It fetches CK data named in EncryptedContent, and the KDK that
+// the access manager encrypts for Key. KDK decrypts CK, and CK decrypts content.
+type Decryptor struct {
+	Sender
+	// Key is the member key, which must be an RSA key.
+	Key Key
+	// If Validator is not nil, CK and KDK data must be accepted by Validator.
+	Validator Validator
+
+	cks map[string][]byte
+	sync.Mutex
+}
+
+// NewDecryptor creates a decryptor for a member key.
+func NewDecryptor(s Sender, key Key) *Decryptor {
+	return &Decryptor{
+		Sender: s,
+		Key:    key,
+		cks:    make(map[string][]byte),
+	}
+}
+
+// Decrypt decrypts content encrypted by Encryptor.
+func (dec *Decryptor) Decrypt(ec *EncryptedContent) ([]byte, error) {
+	ck, err := dec.contentKey(ec.KeyName)
+	if err != nil {
+		return nil, err
+	}
+	return DecryptCBC(ck, ec)
+}
+
+// DecryptData decrypts the content of d.
+func (dec *Decryptor) DecryptData(d *Data) ([]byte, error) {
+	ec, err := unmarshalEncryptedContent(d.Content)
+	if err != nil {
+		return nil, err
+	}
+	return dec.Decrypt(ec)
+}
+
+// contentKey returns CK, which is fetched and cached on first use.
+func (dec *Decryptor) contentKey(ckName Name) ([]byte, error) {
+	dec.Lock()
+	defer dec.Unlock()
+	if ck, ok := dec.cks[ckName.String()]; ok {
+		return ck, nil
+	}
+	ckData, err := dec.fetch(appendName(ckName, lpm.Component(nacComponentEncryptedBy)))
+	if err != nil {
+		return nil, err
+	}
+	_, kek, err := encryptedByName(ckData.Name)
+	if err != nil {
+		return nil, err
+	}
+	name, err := kdkName(kek, dec.Key.Locator())
+	if err != nil {
+		return nil, err
+	}
+	kdkData, err := dec.fetch(name)
+	if err != nil {
+		return nil, err
+	}
+	kdk, err := decryptKDKData(kdkData, dec.Key)
+	if err != nil {
+		return nil, err
+	}
+	ec, err := unmarshalEncryptedContent(ckData.Content)
+	if err != nil {
+		return nil, err
+	}
+	ck, err := decryptOAEP(kdk, ec.Payload)
+	if err != nil {
+		return nil, err
+	}
+	dec.cks[ckName.String()] = ck
+	return ck, nil
+}
+
+func (dec *Decryptor) fetch(name Name) (*Data, error) {
+	d, ok := <-dec.SendInterest(&Interest{Name: name})
+	if !ok {
+		return nil, ErrTimeout
+	}
+	if dec.Validator != nil {
+		err := dec.Validator.ValidateData(d)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// NewDecryptingFace creates a face that delivers data packets with content
+// decrypted by dec.
+//
+// dec should send interests with f, not with the returned face.
+// Because the content is replaced, signatures must be validated before decryption,
+// for example, with NewValidatingFace inside the returned face.
+func NewDecryptingFace(f Face, dec *Decryptor) Face {
+	return &decryptingFace{
+		Face: f,
+		dec:  dec,
+	}
+}
+
+type decryptingFace struct {
+	Face
+	dec *Decryptor
+}
+
+func (f *decryptingFace) SendInterest(i *Interest) <-chan *Data {
+	return f.SendInterestContext(context.Background(), i)
+}
+
+func (f *decryptingFace) SendInterestContext(ctx context.Context, i *Interest) <-chan *Data {
+	return f.ExpressInterest(ctx, i).ch
+}
+
+// ExpressInterest reports the decryption error in Response.Err.
+func (f *decryptingFace) ExpressInterest(ctx context.Context, i *Interest) *Response {
+	resp, ch := newResponse(ctx)
+	pending := ExpressInterest(ctx, f.Face, i)
+	go func() {
+		d := pending.Data()
+		if d == nil {
+			resp.err = pending.Err()
+		} else if plaintext, err := f.dec.DecryptData(d); err != nil {
+			resp.err = err
+		} else {
+			decrypted := *d
+			decrypted.Content = plaintext
+			ch <- &decrypted
+		}
+		close(ch)
+	}()
+	return resp
+}
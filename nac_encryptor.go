@@ -0,0 +1,175 @@
+package ndn
+
+import (
+	"crypto/rand"
+	"sync"
+
+	"github.com/go-ndn/lpm"
+)
+
+// Encryptor encrypts content for name-based access control.
+//
+// Content is encrypted with a content key (CK), and CK is encrypted with
+// the latest key-encryption key (KEK) published by the access manager.
+// CK data must be served with ServeInterest, so that consumers can decrypt content.
+type Encryptor struct {
+	Sender
+	// AccessPrefix is /<identity>/NAC/<dataset> of the access manager.
+	AccessPrefix Name
+	// CKPrefix is the prefix of CK names.
+	CKPrefix Name
+	// Key signs CK data.
+	Key Key
+	// If Validator is not nil, KEK must be accepted by Validator.
+	Validator Validator
+
+	ck     []byte
+	ckName Name
+	ckData map[string]*Data
+	sync.Mutex
+}
+
+// NewEncryptor creates an encryptor.
+func NewEncryptor(s Sender, accessPrefix, ckPrefix Name, key Key) *Encryptor {
+	return &Encryptor{
+		Sender:       s,
+		AccessPrefix: accessPrefix,
+		CKPrefix:     ckPrefix,
+		Key:          key,
+		ckData:       make(map[string]*Data),
+	}
+}
+
+// RegenerateCK creates a new CK, and encrypts it with the latest KEK.
+func (e *Encryptor) RegenerateCK() error {
+	e.Lock()
+	defer e.Unlock()
+	return e.regenerateCK()
+}
+
+func (e *Encryptor) regenerateCK() error {
+	ck := make([]byte, 16)
+	_, err := rand.Read(ck)
+	if err != nil {
+		return err
+	}
+	keyID := make([]byte, 8)
+	_, err = rand.Read(keyID)
+	if err != nil {
+		return err
+	}
+	ckName := appendName(e.CKPrefix, lpm.Component(nacComponentCK), lpm.Component(keyID))
+
+	kek, ok := <-e.SendInterest(&Interest{
+		Name: appendName(e.AccessPrefix, lpm.Component(nacComponentKEK)),
+		Selectors: Selectors{
+			MustBeFresh: true,
+		},
+	})
+	if !ok {
+		return ErrTimeout
+	}
+	if e.Validator != nil {
+		err = e.Validator.ValidateData(kek)
+		if err != nil {
+			return err
+		}
+	}
+	payload, err := encryptOAEP(kek.Content, ck)
+	if err != nil {
+		return err
+	}
+	content, err := marshalEncryptedContent(&EncryptedContent{Payload: payload})
+	if err != nil {
+		return err
+	}
+	d := &Data{
+		Name:    appendName(appendName(ckName, lpm.Component(nacComponentEncryptedBy)), kek.Name.Components...),
+		Content: content,
+	}
+	err = SignData(e.Key, d)
+	if err != nil {
+		return err
+	}
+	e.ck = ck
+	e.ckName = ckName
+	e.ckData[ckName.String()] = d
+	return nil
+}
+
+// Encrypt encrypts plaintext with CK in AES-CBC.
+//
+// CK is created on first use.
+func (e *Encryptor) Encrypt(plaintext []byte) (*EncryptedContent, error) {
+	e.Lock()
+	defer e.Unlock()
+	if e.ck == nil {
+		err := e.regenerateCK()
+		if err != nil {
+			return nil, err
+		}
+	}
+	ec, err := EncryptCBC(e.ck, plaintext)
+	if err != nil {
+		return nil, err
+	}
+	ec.KeyName = e.ckName
+	return ec, nil
+}
+
+// EncryptData replaces the content of d with EncryptedContent.
+//
+// It should be invoked before d is signed.
+func (e *Encryptor) EncryptData(d *Data) error {
+	ec, err := e.Encrypt(d.Content)
+	if err != nil {
+		return err
+	}
+	d.Content, err = marshalEncryptedContent(ec)
+	return err
+}
+
+// ServeInterest returns CK data that matches i.
+func (e *Encryptor) ServeInterest(i *Interest) (*Data, error) {
+	e.Lock()
+	defer e.Unlock()
+	for _, d := range e.ckData {
+		if isPrefix(i.Name, d.Name) {
+			return d, nil
+		}
+	}
+	return nil, ErrInvalidNACName
+}
+
+// Middleware encrypts the content of data sent by handlers with EncryptData,
+// and answers interests for CK data with ServeInterest.
+//
+// CKPrefix should be under the prefix of the handler, so that CK data is found.
+// It should be inside signing middleware, such as KeyChain.SignMiddleware,
+// so that encrypted data is signed.
+func (e *Encryptor) Middleware() Middleware {
+	return func(next InterestHandler) InterestHandler {
+		return InterestHandlerFunc(func(w Sender, i *Interest) {
+			if d, err := e.ServeInterest(i); err == nil {
+				w.SendData(d)
+				return
+			}
+			next.ServeInterest(&encryptSender{Sender: w, e: e}, i)
+		})
+	}
+}
+
+// encryptSender encrypts data sent by a handler of Encryptor.Middleware.
+type encryptSender struct {
+	Sender
+	e *Encryptor
+}
+
+func (s *encryptSender) SendData(d *Data) {
+	encrypted := *d
+	err := s.e.EncryptData(&encrypted)
+	if err != nil {
+		return
+	}
+	s.Sender.SendData(&encrypted)
+}
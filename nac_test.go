@@ -0,0 +1,136 @@
+package ndn
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+)
+
+func TestNAC(t *testing.T) {
+	m, err := NewAccessManager(NewName("/access"), NewName("/sensor"), ecdsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	member, err := CertificateToData(rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = m.AddMember(member)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if members := m.Members(); len(members) != 1 || members[0].Compare(rsaKey.Locator()) != 0 {
+		t.Fatalf("expect [%v], got %v", rsaKey.Locator(), members)
+	}
+
+	var enc *Encryptor
+	consumer, producer := newPipe(func(i *Interest) *Data {
+		if d, err := m.ServeInterest(i); err == nil {
+			return d
+		}
+		d, _ := enc.ServeInterest(i)
+		return d
+	})
+	defer producer.Close()
+	defer consumer.Close()
+
+	enc = NewEncryptor(consumer, m.Prefix, NewName("/sensor/temperature"), ecdsaKey)
+	d := &Data{
+		Name:    NewName("/sensor/temperature/0"),
+		Content: []byte("21"),
+	}
+	err = enc.EncryptData(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(d.Content, []byte("21")) {
+		t.Fatal("content is not encrypted")
+	}
+
+	dec := NewDecryptor(consumer, rsaKey)
+	plaintext, err := dec.DecryptData(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(plaintext, []byte("21")) {
+		t.Fatalf("expect 21, got %s", plaintext)
+	}
+
+	// removed members no longer receive KDK
+	m.RemoveMember(rsaKey.Locator())
+	err = m.Rotate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	kek, err := m.ServeInterest(&Interest{Name: m.Prefix})
+	if err != nil {
+		t.Fatal(err)
+	}
+	name, err := kdkName(kek.Name, rsaKey.Locator())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = m.ServeInterest(&Interest{Name: name})
+	if err != ErrInvalidNACName {
+		t.Fatalf("expect %v, got %v", ErrInvalidNACName, err)
+	}
+}
+
+func TestNACMiddleware(t *testing.T) {
+	m, err := NewAccessManager(NewName("/access"), NewName("/sensor"), ecdsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	member, err := CertificateToData(rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = m.AddMember(member)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c1, c2 := net.Pipe()
+	consumer := NewFace(c1)
+	defer consumer.Close()
+	enc := NewEncryptor(consumer, m.Prefix, NewName("/sensor/temperature"), ecdsaKey)
+	mux := NewMux()
+	mux.HandleFunc("/access", func(w Sender, i *Interest) {
+		if d, err := m.ServeInterest(i); err == nil {
+			w.SendData(d)
+		}
+	})
+	mux.Handle("/sensor", ChainHandler(InterestHandlerFunc(func(w Sender, i *Interest) {
+		w.SendData(&Data{Name: i.Name, Content: []byte("21")})
+	}), enc.Middleware()))
+	producer := NewFace(c2, WithInterestHandler(mux))
+	defer producer.Close()
+
+	i := &Interest{Name: NewName("/sensor/temperature/0")}
+	d, err := SendInterestContext(context.Background(), consumer, i)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(d.Content, []byte("21")) {
+		t.Fatal("content is not encrypted")
+	}
+
+	f := NewDecryptingFace(consumer, NewDecryptor(consumer, rsaKey))
+	d, err = SendInterestContext(context.Background(), f, &Interest{Name: i.Name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(d.Content, []byte("21")) {
+		t.Fatalf("expect 21, got %s", d.Content)
+	}
+
+	// unencrypted content is not delivered
+	_, err = SendInterestContext(context.Background(), f, &Interest{
+		Name:      m.Prefix,
+		Selectors: Selectors{MustBeFresh: true},
+	})
+	if err == nil {
+		t.Fatal("expect error")
+	}
+}
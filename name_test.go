@@ -1,6 +1,10 @@
 package ndn
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/go-ndn/lpm"
+)
 
 func TestName(t *testing.T) {
 	name := NewName("/A/B")
@@ -21,3 +25,34 @@ func TestName(t *testing.T) {
 		}
 	}
 }
+
+func TestKeyCertificateName(t *testing.T) {
+	identity := NewName("/ndn/guest/alice")
+	key := KeyName(identity, lpm.Component("abc"))
+	if want := NewName("/ndn/guest/alice/KEY/abc"); key.Compare(want) != 0 {
+		t.Fatalf("KeyName == %v, got %v", want, key)
+	}
+	id, keyID, ok := ParseKeyName(key)
+	if !ok || id.Compare(identity) != 0 || string(keyID) != "abc" {
+		t.Fatalf("ParseKeyName(%v) == %v, got %v", key, identity, id)
+	}
+
+	cert := CertificateName(key, lpm.Component("self"), 1)
+	if cert.Len() != key.Len()+2 {
+		t.Fatalf("CertificateName == %v/self/<version>, got %v", key, cert)
+	}
+	k, issuerID, version, ok := ParseCertificateName(cert)
+	if !ok || k.Compare(key) != 0 || string(issuerID) != "self" || version != 1 {
+		t.Fatalf("ParseCertificateName(%v) == %v, got %v", cert, key, k)
+	}
+
+	for _, name := range []Name{
+		identity,
+		key,
+		NewName("/ndn/guest/alice/KEY/abc/self"),
+	} {
+		if _, _, _, ok := ParseCertificateName(name); ok {
+			t.Fatalf("ParseCertificateName(%v) == false, got true", name)
+		}
+	}
+}
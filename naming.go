@@ -0,0 +1,136 @@
+package ndn
+
+import "github.com/go-ndn/lpm"
+
+// Markers of NDN naming conventions.
+//
+// See https://named-data.net/publications/techreports/ndn-tr-22-ndn-memo-naming-conventions.
+const (
+	markerSegment  = 0x00
+	markerVersion  = 0xFD
+	markerSequence = 0xFE
+)
+
+// appendNonNegativeInteger appends v in 1, 2, 4 or 8 bytes.
+func appendNonNegativeInteger(b []byte, v uint64) []byte {
+	switch {
+	case v <= 0xFF:
+		return append(b, byte(v))
+	case v <= 0xFFFF:
+		return append(b, byte(v>>8), byte(v))
+	case v <= 0xFFFFFFFF:
+		return append(b, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+	default:
+		return append(b,
+			byte(v>>56), byte(v>>48), byte(v>>40), byte(v>>32),
+			byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+	}
+}
+
+// parseNonNegativeInteger decodes v in 1, 2, 4 or 8 bytes.
+func parseNonNegativeInteger(b []byte) (v uint64, ok bool) {
+	switch len(b) {
+	case 1, 2, 4, 8:
+	default:
+		return
+	}
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	ok = true
+	return
+}
+
+// markedComponent encodes v as a nonNegativeInteger prefixed by marker.
+func markedComponent(marker byte, v uint64) lpm.Component {
+	return appendNonNegativeInteger(lpm.Component{marker}, v)
+}
+
+// parseMarkedComponent decodes a component created by markedComponent.
+func parseMarkedComponent(marker byte, c lpm.Component) (v uint64, ok bool) {
+	if len(c) == 0 || c[0] != marker {
+		return
+	}
+	return parseNonNegativeInteger(c[1:])
+}
+
+// VersionComponent creates a version component.
+func VersionComponent(version uint64) lpm.Component {
+	return markedComponent(markerVersion, version)
+}
+
+// ParseVersion decodes a version component.
+func ParseVersion(c lpm.Component) (uint64, bool) {
+	return parseMarkedComponent(markerVersion, c)
+}
+
+// SegmentComponent creates a segment component.
+func SegmentComponent(segment uint64) lpm.Component {
+	return markedComponent(markerSegment, segment)
+}
+
+// ParseSegment decodes a segment component.
+func ParseSegment(c lpm.Component) (uint64, bool) {
+	return parseMarkedComponent(markerSegment, c)
+}
+
+// SequenceComponent creates a sequence number component.
+func SequenceComponent(seq uint64) lpm.Component {
+	return markedComponent(markerSequence, seq)
+}
+
+// ParseSequence decodes a sequence number component.
+func ParseSequence(c lpm.Component) (uint64, bool) {
+	return parseMarkedComponent(markerSequence, c)
+}
+
+// keyComponent separates identity and key id in key names.
+const keyComponent = "KEY"
+
+// KeyName creates a key name /<identity>/KEY/<key-id>.
+//
+// See https://named-data.net/doc/ndn-cxx/current/specs/certificate-format.html.
+func KeyName(identity Name, keyID lpm.Component) Name {
+	components := make([]lpm.Component, 0, identity.Len()+2)
+	components = append(components, identity.Components...)
+	components = append(components, lpm.Component(keyComponent), keyID)
+	return Name{Components: components}
+}
+
+// ParseKeyName splits a key name into identity and key id.
+func ParseKeyName(name Name) (identity Name, keyID lpm.Component, ok bool) {
+	l := name.Len()
+	if l < 2 || string(name.Components[l-2]) != keyComponent {
+		return
+	}
+	identity = Name{Components: name.Components[:l-2]}
+	keyID = name.Components[l-1]
+	ok = true
+	return
+}
+
+// CertificateName creates a certificate name /<key name>/<issuer-id>/<version>.
+func CertificateName(key Name, issuerID lpm.Component, version uint64) Name {
+	components := make([]lpm.Component, 0, key.Len()+2)
+	components = append(components, key.Components...)
+	components = append(components, issuerID, VersionComponent(version))
+	return Name{Components: components}
+}
+
+// ParseCertificateName splits a certificate name into key name, issuer id and version.
+func ParseCertificateName(name Name) (key Name, issuerID lpm.Component, version uint64, ok bool) {
+	l := name.Len()
+	if l < 4 {
+		return
+	}
+	prefix := Name{Components: name.Components[:l-2]}
+	if _, _, ok = ParseKeyName(prefix); !ok {
+		return
+	}
+	if version, ok = ParseVersion(name.Components[l-1]); !ok {
+		return
+	}
+	key = prefix
+	issuerID = name.Components[l-2]
+	return
+}
@@ -10,17 +10,21 @@ import (
 	"hash"
 	"hash/crc32"
 	"math/rand"
+	"net"
 
 	"github.com/go-ndn/lpm"
 	"github.com/go-ndn/tlv"
 )
 
 // Interest carries a name that identifies the desired data.
+//
+// Parameters is ApplicationParameters, which carries arbitrary data to the producer.
 type Interest struct {
-	Name      Name      `tlv:"7"`
-	Selectors Selectors `tlv:"9?"`
-	Nonce     uint64    `tlv:"10"`
-	LifeTime  uint64    `tlv:"12?"`
+	Name       Name      `tlv:"7"`
+	Selectors  Selectors `tlv:"9?"`
+	Nonce      uint64    `tlv:"10"`
+	LifeTime   uint64    `tlv:"12?"`
+	Parameters []byte    `tlv:"36?"`
 }
 
 // Selectors are optional elements that further qualify Data that may match the Interest.
@@ -57,6 +61,44 @@ func (sel *Selectors) Match(d *Data, interestLen int) bool {
 	return true
 }
 
+// Match checks whether d satisfies i, which is also how pending interests of faces
+// are satisfied:
+// the name of i is a prefix of the name of d, or the full name of d if it has
+// ImplicitDigestSHA256; d matches Selectors; and if MustBeFresh is set,
+// d has FreshnessPeriod, because data without it is stale on arrival.
+//
+// The name of i can always be a prefix, like CanBePrefix in NDN packet format v0.3.
+// An exact match is requested with MaxComponents equal to the length of the name.
+func (i *Interest) Match(d *Data) bool {
+	n := i.Name.Len()
+	if n > d.Name.Len() {
+		return false
+	}
+	for k := 0; k < n; k++ {
+		if !bytes.Equal(i.Name.Components[k], d.Name.Components[k]) {
+			return false
+		}
+	}
+	return matchData(&i.Selectors, i.Name.ImplicitDigestSHA256, d, n, func() []byte {
+		digest, _ := dataDigest(d)
+		return digest
+	})
+}
+
+// matchData checks the rules of Interest.Match except the name prefix.
+//
+// interestLen is the length of the interest name, and
+// dataDigest returns the implicit digest of d.
+func matchData(sel *Selectors, digest lpm.Component, d *Data, interestLen int, dataDigest func() []byte) bool {
+	if len(digest) != 0 && (interestLen != d.Name.Len() || !bytes.Equal(digest, dataDigest())) {
+		return false
+	}
+	if sel.MustBeFresh && d.MetaInfo.FreshnessPeriod == 0 {
+		return false
+	}
+	return sel.Match(d, interestLen)
+}
+
 // Data represents some arbitrary binary data (held in the Content element) together
 // with its Name, some additional bits of information (MetaInfo), and a digital Signature of the other three elements.
 type Data struct {
@@ -108,10 +150,16 @@ const (
 // SignatureInfo is included in signature calculation and fully describes the signature,
 // signature algorithm, and any other relevant information to obtain parent certificate(s),
 // such as KeyLocator.
+//
+// Nonce, Time and SeqNum protect signed interests against replay attacks.
+// See SignatureOptions.
 type SignatureInfo struct {
 	SignatureType  uint64         `tlv:"27"`
 	KeyLocator     KeyLocator     `tlv:"28?"`
 	ValidityPeriod ValidityPeriod `tlv:"253?"`
+	Nonce          []byte         `tlv:"38?"`
+	Time           uint64         `tlv:"40?"`
+	SeqNum         uint64         `tlv:"42?"`
 }
 
 // SignatureType specifies signing algorithm for data packets.
@@ -121,6 +169,12 @@ const (
 	SignatureTypeDigestCRC32C           = 2
 	SignatureTypeSHA256WithECDSA        = 3
 	SignatureTypeSHA256WithHMAC         = 4
+	SignatureTypeEd25519                = 5
+	// The following types are not assigned by NDN packet format,
+	// so they are only understood by peers that use this package.
+	SignatureTypeSHA256WithRSAPSS = 200
+	SignatureTypeSHA384WithECDSA  = 201
+	SignatureTypeSHA512WithECDSA  = 202
 )
 
 // KeyLocator specifies either Name that points to another Data packet containing
@@ -169,25 +223,115 @@ func NewCRC32C() hash.Hash {
 //
 // SHA256 digest will be populated if SignatureValue is empty.
 func (d *Data) WriteTo(w tlv.Writer) error {
-	if len(d.SignatureValue) == 0 {
-		var f func() hash.Hash
-		switch d.SignatureInfo.SignatureType {
-		case SignatureTypeDigestSHA256:
-			f = sha256.New
-		case SignatureTypeDigestCRC32C:
-			f = NewCRC32C
-		default:
-			return ErrNotSupported
-		}
-		var err error
-		d.SignatureValue, err = tlv.Hash(f, d)
-		if err != nil {
-			return err
-		}
+	err := d.digest()
+	if err != nil {
+		return err
 	}
 	return w.Write(d, 6)
 }
 
+func (d *Data) digest() error {
+	if len(d.SignatureValue) != 0 {
+		return nil
+	}
+	var f func() hash.Hash
+	switch d.SignatureInfo.SignatureType {
+	case SignatureTypeDigestSHA256:
+		f = sha256.New
+	case SignatureTypeDigestCRC32C:
+		f = NewCRC32C
+	default:
+		return ErrNotSupported
+	}
+	var err error
+	d.SignatureValue, err = tlv.Hash(f, d)
+	return err
+}
+
+// verifyDigest checks SignatureValue of DigestSha256 or DigestCRC32C.
+func (d *Data) verifyDigest() error {
+	var f func() hash.Hash
+	switch d.SignatureInfo.SignatureType {
+	case SignatureTypeDigestSHA256:
+		f = sha256.New
+	case SignatureTypeDigestCRC32C:
+		f = NewCRC32C
+	default:
+		return ErrNotSupported
+	}
+	digest, err := tlv.Hash(f, d)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(digest, d.SignatureValue) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Buffers encodes a data packet as a list of buffers that can be
+// flushed with a single vectored write.
+//
+// Content and SignatureValue are not copied.
+// SHA256 digest will be populated if SignatureValue is empty.
+func (d *Data) Buffers() (net.Buffers, error) {
+	err := d.digest()
+	if err != nil {
+		return nil, err
+	}
+	name, err := tlv.Marshal(&d.Name, 7)
+	if err != nil {
+		return nil, err
+	}
+	metaInfo, err := tlv.Marshal(&d.MetaInfo, 20)
+	if err != nil {
+		return nil, err
+	}
+	sigInfo, err := tlv.Marshal(&d.SignatureInfo, 22)
+	if err != nil {
+		return nil, err
+	}
+	content := appendHeader(nil, 21, len(d.Content))
+	sigValue := appendHeader(nil, 23, len(d.SignatureValue))
+
+	var size int
+	for _, b := range [][]byte{name, metaInfo, content, d.Content, sigInfo, sigValue, d.SignatureValue} {
+		size += len(b)
+	}
+	return net.Buffers{
+		appendHeader(nil, 6, size),
+		name,
+		metaInfo,
+		content,
+		d.Content,
+		sigInfo,
+		sigValue,
+		d.SignatureValue,
+	}, nil
+}
+
+// appendHeader appends tlv type and length to b.
+func appendHeader(b []byte, t uint64, l int) []byte {
+	b = appendNumber(b, t)
+	return appendNumber(b, uint64(l))
+}
+
+// appendNumber appends a variable-length number to b.
+func appendNumber(b []byte, v uint64) []byte {
+	switch {
+	case v < 253:
+		return append(b, byte(v))
+	case v <= 0xFFFF:
+		return append(b, 253, byte(v>>8), byte(v))
+	case v <= 0xFFFFFFFF:
+		return append(b, 254, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+	default:
+		return append(b, 255,
+			byte(v>>56), byte(v>>48), byte(v>>40), byte(v>>32),
+			byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+	}
+}
+
 // ReadFrom implements tlv.ReadFrom.
 //
 // Signature will not be verified.
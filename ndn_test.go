@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"testing"
 
+	"github.com/go-ndn/lpm"
 	"github.com/go-ndn/tlv"
 )
 
@@ -15,6 +16,30 @@ var (
 	discard = tlv.NewWriter(ioutil.Discard)
 )
 
+func TestDataBuffers(t *testing.T) {
+	d := &Data{
+		Name:    NewName("/hello/world"),
+		Content: bytes.Repeat([]byte("0123456789"), 100),
+	}
+	want := new(bytes.Buffer)
+	err := d.WriteTo(tlv.NewWriter(want))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bufs, err := d.Buffers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := new(bytes.Buffer)
+	_, err = bufs.WriteTo(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(want.Bytes(), got.Bytes()) {
+		t.Fatalf("expect %v, got %v", want.Bytes(), got.Bytes())
+	}
+}
+
 func BenchmarkDataEncodeRSA(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		err := SignData(rsaKey, data)
@@ -63,6 +88,19 @@ func BenchmarkDataEncode(b *testing.B) {
 	}
 }
 
+func BenchmarkDataBuffers(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		bufs, err := data.Buffers()
+		if err != nil {
+			b.Fatal(err)
+		}
+		_, err = bufs.WriteTo(ioutil.Discard)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 func BenchmarkDataDecode(b *testing.B) {
 	buf := new(bytes.Buffer)
 	data.WriteTo(tlv.NewWriter(buf))
@@ -103,3 +141,37 @@ func BenchmarkInterestDecode(b *testing.B) {
 		}
 	}
 }
+
+func TestInterestMatch(t *testing.T) {
+	d := &Data{Name: NewName("/A/B")}
+	digest, err := dataDigest(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		name   string
+		digest []byte
+		sel    Selectors
+		fresh  uint64
+		want   bool
+	}{
+		{name: "/A", want: true},
+		{name: "/A/B", want: true},
+		{name: "/A/C"},
+		{name: "/A/B/C"},
+		{name: "/A/B", digest: digest, want: true},
+		{name: "/A/B", digest: []byte{1}},
+		{name: "/A", digest: digest},
+		{name: "/A", sel: Selectors{MaxComponents: 1}},
+		{name: "/A", sel: Selectors{MustBeFresh: true}},
+		{name: "/A", sel: Selectors{MustBeFresh: true}, fresh: 1000, want: true},
+		{name: "/A", sel: Selectors{Exclude: Exclude{{Component: lpm.Component("B")}}}},
+	} {
+		d.MetaInfo.FreshnessPeriod = test.fresh
+		i := &Interest{Name: NewName(test.name), Selectors: test.sel}
+		i.Name.ImplicitDigestSHA256 = test.digest
+		if got := i.Match(d); got != test.want {
+			t.Fatalf("%s %+v: expect %v, got %v", test.name, test.sel, test.want, got)
+		}
+	}
+}
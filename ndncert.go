@@ -0,0 +1,386 @@
+package ndn
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"sort"
+
+	"github.com/go-ndn/lpm"
+	"github.com/go-ndn/tlv"
+)
+
+// Errors introduced by NDNCERT.
+var (
+	ErrChallengeFailed = errors.New("ndncert challenge failed")
+	ErrInvalidRequest  = errors.New("invalid ndncert request")
+)
+
+// NDNCERT request status.
+const (
+	CertStatusBeforeChallenge uint64 = 0
+	CertStatusChallenge              = 1
+	CertStatusPending                = 2
+	CertStatusSuccess                = 3
+	CertStatusFailure                = 4
+)
+
+// NDNCERT tlv types.
+const (
+	tlvParameterKey      = 133
+	tlvParameterValue    = 135
+	tlvECDHPub           = 145
+	tlvCertRequest       = 147
+	tlvSalt              = 149
+	tlvRequestID         = 151
+	tlvChallenge         = 153
+	tlvStatus            = 155
+	tlvIV                = 157
+	tlvEncryptedPayload  = 159
+	tlvSelectedChallenge = 161
+	tlvChallengeStatus   = 163
+	tlvRemainingTries    = 165
+	tlvRemainingTime     = 167
+	tlvIssuedCertName    = 169
+	tlvAuthTag           = 175
+)
+
+// NDNCERT name components after CA prefix.
+const (
+	certComponentCA        = "CA"
+	certComponentNew       = "NEW"
+	certComponentChallenge = "CHALLENGE"
+)
+
+// certNewRequest is the parameters of NEW interest.
+type certNewRequest struct {
+	ECDHPub     []byte
+	CertRequest *Data
+}
+
+// certNewResponse is the content of NEW response.
+type certNewResponse struct {
+	ECDHPub    []byte
+	Salt       []byte
+	RequestID  []byte
+	Challenges []string
+}
+
+// certChallengeRequest is the plaintext of CHALLENGE interest.
+type certChallengeRequest struct {
+	Challenge  string
+	Parameters map[string]string
+}
+
+// certChallengeResponse is the plaintext of CHALLENGE response.
+type certChallengeResponse struct {
+	Status          uint64
+	ChallengeStatus string
+	RemainingTries  uint64
+	RemainingTime   uint64
+	IssuedCertName  Name
+}
+
+func (req *certNewRequest) MarshalBinary() ([]byte, error) {
+	cert, err := marshalData(req.CertRequest)
+	if err != nil {
+		return nil, err
+	}
+	buf := new(bytes.Buffer)
+	w := tlv.NewWriter(buf)
+	err = w.Write(req.ECDHPub, tlvECDHPub)
+	if err != nil {
+		return nil, err
+	}
+	err = w.Write(cert, tlvCertRequest)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (req *certNewRequest) UnmarshalBinary(b []byte) error {
+	r := tlv.NewReader(bytes.NewReader(b))
+	err := r.Read(&req.ECDHPub, tlvECDHPub)
+	if err != nil {
+		return err
+	}
+	var cert []byte
+	err = r.Read(&cert, tlvCertRequest)
+	if err != nil {
+		return err
+	}
+	req.CertRequest, err = unmarshalData(cert)
+	return err
+}
+
+func (resp *certNewResponse) MarshalBinary() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	w := tlv.NewWriter(buf)
+	for _, f := range []struct {
+		v []byte
+		t uint64
+	}{
+		{resp.ECDHPub, tlvECDHPub},
+		{resp.Salt, tlvSalt},
+		{resp.RequestID, tlvRequestID},
+	} {
+		err := w.Write(f.v, f.t)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, c := range resp.Challenges {
+		err := w.Write(c, tlvChallenge)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func (resp *certNewResponse) UnmarshalBinary(b []byte) error {
+	r := tlv.NewReader(bytes.NewReader(b))
+	for _, f := range []struct {
+		v *[]byte
+		t uint64
+	}{
+		{&resp.ECDHPub, tlvECDHPub},
+		{&resp.Salt, tlvSalt},
+		{&resp.RequestID, tlvRequestID},
+	} {
+		err := r.Read(f.v, f.t)
+		if err != nil {
+			return err
+		}
+	}
+	for r.Peek() == tlvChallenge {
+		var c string
+		err := r.Read(&c, tlvChallenge)
+		if err != nil {
+			return err
+		}
+		resp.Challenges = append(resp.Challenges, c)
+	}
+	return nil
+}
+
+func (req *certChallengeRequest) MarshalBinary() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	w := tlv.NewWriter(buf)
+	err := w.Write(req.Challenge, tlvSelectedChallenge)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(req.Parameters))
+	for k := range req.Parameters {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		err = w.Write(k, tlvParameterKey)
+		if err != nil {
+			return nil, err
+		}
+		err = w.Write(req.Parameters[k], tlvParameterValue)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func (req *certChallengeRequest) UnmarshalBinary(b []byte) error {
+	r := tlv.NewReader(bytes.NewReader(b))
+	err := r.Read(&req.Challenge, tlvSelectedChallenge)
+	if err != nil {
+		return err
+	}
+	req.Parameters = make(map[string]string)
+	for r.Peek() == tlvParameterKey {
+		var k, v string
+		err = r.Read(&k, tlvParameterKey)
+		if err != nil {
+			return err
+		}
+		err = r.Read(&v, tlvParameterValue)
+		if err != nil {
+			return err
+		}
+		req.Parameters[k] = v
+	}
+	return nil
+}
+
+func (resp *certChallengeResponse) MarshalBinary() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	w := tlv.NewWriter(buf)
+	err := w.Write(resp.Status, tlvStatus)
+	if err != nil {
+		return nil, err
+	}
+	if resp.ChallengeStatus != "" {
+		err = w.Write(resp.ChallengeStatus, tlvChallengeStatus)
+		if err != nil {
+			return nil, err
+		}
+		err = w.Write(resp.RemainingTries, tlvRemainingTries)
+		if err != nil {
+			return nil, err
+		}
+		err = w.Write(resp.RemainingTime, tlvRemainingTime)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if resp.IssuedCertName.Len() != 0 {
+		name, err := marshalName(resp.IssuedCertName)
+		if err != nil {
+			return nil, err
+		}
+		err = w.Write(name, tlvIssuedCertName)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func (resp *certChallengeResponse) UnmarshalBinary(b []byte) error {
+	r := tlv.NewReader(bytes.NewReader(b))
+	err := r.Read(&resp.Status, tlvStatus)
+	if err != nil {
+		return err
+	}
+	if r.Peek() == tlvChallengeStatus {
+		err = r.Read(&resp.ChallengeStatus, tlvChallengeStatus)
+		if err != nil {
+			return err
+		}
+		err = r.Read(&resp.RemainingTries, tlvRemainingTries)
+		if err != nil {
+			return err
+		}
+		err = r.Read(&resp.RemainingTime, tlvRemainingTime)
+		if err != nil {
+			return err
+		}
+	}
+	if r.Peek() == tlvIssuedCertName {
+		var name []byte
+		err = r.Read(&name, tlvIssuedCertName)
+		if err != nil {
+			return err
+		}
+		resp.IssuedCertName, err = unmarshalName(name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// certSession encrypts CHALLENGE messages with AES-GCM.
+//
+// The AES key is derived from ECDH shared secret with HKDF-SHA256,
+// and the request id is used as additional data.
+type certSession struct {
+	requestID []byte
+	aead      cipher.AEAD
+}
+
+func newCertSession(pri *ecdh.PrivateKey, peer, salt, requestID []byte) (*certSession, error) {
+	pub, err := ecdh.P256().NewPublicKey(peer)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := pri.ECDH(pub)
+	if err != nil {
+		return nil, err
+	}
+	key, err := hkdf.Key(sha256.New, secret, salt, string(requestID), 16)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &certSession{
+		requestID: requestID,
+		aead:      aead,
+	}, nil
+}
+
+// seal encrypts plaintext into initialization-vector, auth-tag and encrypted-payload.
+func (s *certSession) seal(plaintext []byte) ([]byte, error) {
+	iv := make([]byte, s.aead.NonceSize())
+	_, err := rand.Read(iv)
+	if err != nil {
+		return nil, err
+	}
+	sealed := s.aead.Seal(nil, iv, plaintext, s.requestID)
+	tagStart := len(sealed) - s.aead.Overhead()
+
+	buf := new(bytes.Buffer)
+	w := tlv.NewWriter(buf)
+	for _, f := range []struct {
+		v []byte
+		t uint64
+	}{
+		{iv, tlvIV},
+		{sealed[tagStart:], tlvAuthTag},
+		{sealed[:tagStart], tlvEncryptedPayload},
+	} {
+		err = w.Write(f.v, f.t)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// open decrypts a message created by seal.
+func (s *certSession) open(b []byte) ([]byte, error) {
+	var iv, tag, payload []byte
+	r := tlv.NewReader(bytes.NewReader(b))
+	for _, f := range []struct {
+		v *[]byte
+		t uint64
+	}{
+		{&iv, tlvIV},
+		{&tag, tlvAuthTag},
+		{&payload, tlvEncryptedPayload},
+	} {
+		err := r.Read(f.v, f.t)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(iv) != s.aead.NonceSize() {
+		return nil, ErrInvalidRequest
+	}
+	return s.aead.Open(nil, iv, append(payload, tag...), s.requestID)
+}
+
+// certInterestName creates /<ca>/CA/<command>/<suffix...>/<parameters digest>.
+//
+// The digest makes interests with different parameters distinct.
+func certInterestName(ca Name, command string, parameters []byte, suffix ...lpm.Component) Name {
+	digest := sha256.Sum256(parameters)
+	components := make([]lpm.Component, 0, ca.Len()+3+len(suffix))
+	components = append(components, ca.Components...)
+	components = append(components, lpm.Component(certComponentCA), lpm.Component(command))
+	components = append(components, suffix...)
+	components = append(components, lpm.Component(digest[:]))
+	return Name{Components: components}
+}
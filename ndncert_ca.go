@@ -0,0 +1,241 @@
+package ndn
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/x509"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-ndn/lpm"
+)
+
+// CertAuthority issues certificates with NDNCERT.
+//
+// Requested key names must be under Prefix. Responses and issued certificates
+// are signed by Key. Invalid requests are dropped.
+type CertAuthority struct {
+	Prefix     Name
+	Key        Key
+	Challenges []CertChallenge
+	// Validity is the validity period of issued certificates.
+	Validity time.Duration
+	// RequestLifetime is how long a request can stay in challenge.
+	RequestLifetime time.Duration
+
+	requests *expiryCache
+	issued   Cache
+}
+
+type certRequest struct {
+	key       *Certificate
+	session   *certSession
+	challenge CertChallenge
+	state     ChallengeState
+	sync.Mutex
+}
+
+// NewCertAuthority creates a CA that issues 30-day certificates.
+func NewCertAuthority(prefix Name, key Key, challenges ...CertChallenge) *CertAuthority {
+	return &CertAuthority{
+		Prefix:          prefix,
+		Key:             key,
+		Challenges:      challenges,
+		Validity:        30 * 24 * time.Hour,
+		RequestLifetime: time.Hour,
+		requests:        newExpiryCache(1000),
+		issued:          NewCache(1000),
+	}
+}
+
+// Serve answers NDNCERT interests from recv with s.
+//
+// It returns when recv is closed.
+func (ca *CertAuthority) Serve(s Sender, recv <-chan *Interest) {
+	for i := range recv {
+		d, err := ca.ServeInterest(i)
+		if err != nil {
+			continue
+		}
+		s.SendData(d)
+	}
+}
+
+// ServeInterest creates the response of an NDNCERT interest,
+// or returns an issued certificate.
+func (ca *CertAuthority) ServeInterest(i *Interest) (*Data, error) {
+	l := ca.Prefix.Len()
+	if i.Name.Len() < l+3 || !isPrefix(ca.Prefix, i.Name) ||
+		string(i.Name.Components[l]) != certComponentCA {
+		if d := ca.issued.Get(i); d != nil {
+			return d, nil
+		}
+		return nil, ErrInvalidRequest
+	}
+	var content []byte
+	var err error
+	switch string(i.Name.Components[l+1]) {
+	case certComponentNew:
+		content, err = ca.serveNew(i.Parameters)
+	case certComponentChallenge:
+		if i.Name.Len() < l+4 {
+			return nil, ErrInvalidRequest
+		}
+		content, err = ca.serveChallenge(i.Name.Components[l+2], i.Parameters)
+	default:
+		err = ErrInvalidRequest
+	}
+	if err != nil {
+		return nil, err
+	}
+	d := &Data{
+		Name:    i.Name,
+		Content: content,
+	}
+	err = SignData(ca.Key, d)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (ca *CertAuthority) serveNew(params []byte) ([]byte, error) {
+	var req certNewRequest
+	err := req.UnmarshalBinary(params)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := ParseCertificate(req.CertRequest)
+	if err != nil {
+		return nil, err
+	}
+	if !isPrefix(ca.Prefix, cert.Name) {
+		return nil, ErrInvalidRequest
+	}
+	// the self-signed certificate proves possession of the private key
+	pub, err := x509.ParsePKIXPublicKey(cert.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	key, err := publicKey(cert.Name, pub)
+	if err != nil {
+		return nil, err
+	}
+	err = VerifyData(key, req.CertRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	ecdhKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	resp := &certNewResponse{
+		ECDHPub:   ecdhKey.PublicKey().Bytes(),
+		Salt:      make([]byte, 32),
+		RequestID: make([]byte, 8),
+	}
+	for _, b := range [][]byte{resp.Salt, resp.RequestID} {
+		_, err = rand.Read(b)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, c := range ca.Challenges {
+		resp.Challenges = append(resp.Challenges, c.Name())
+	}
+	session, err := newCertSession(ecdhKey, req.ECDHPub, resp.Salt, resp.RequestID)
+	if err != nil {
+		return nil, err
+	}
+	ca.requests.Add(fmt.Sprintf("%x", resp.RequestID), &certRequest{
+		key:     cert,
+		session: session,
+	}, ca.RequestLifetime)
+	return resp.MarshalBinary()
+}
+
+func (ca *CertAuthority) serveChallenge(requestID lpm.Component, params []byte) ([]byte, error) {
+	v, ok := ca.requests.Get(fmt.Sprintf("%x", []byte(requestID)))
+	if !ok {
+		return nil, ErrInvalidRequest
+	}
+	req := v.(*certRequest)
+	req.Lock()
+	defer req.Unlock()
+
+	plaintext, err := req.session.open(params)
+	if err != nil {
+		return nil, err
+	}
+	var challengeReq certChallengeRequest
+	err = challengeReq.UnmarshalBinary(plaintext)
+	if err != nil {
+		return nil, err
+	}
+	if req.challenge == nil {
+		for _, c := range ca.Challenges {
+			if c.Name() == challengeReq.Challenge {
+				req.challenge = c
+				req.state.Key = req.key.Name
+				break
+			}
+		}
+	}
+	if req.challenge == nil || req.challenge.Name() != challengeReq.Challenge {
+		return nil, ErrInvalidRequest
+	}
+
+	resp, err := ca.handleChallenge(req, challengeReq.Parameters)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Status == CertStatusSuccess || resp.Status == CertStatusFailure {
+		// expire the request immediately
+		ca.requests.Add(fmt.Sprintf("%x", []byte(requestID)), req, 0)
+	}
+	b, err := resp.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return req.session.seal(b)
+}
+
+func (ca *CertAuthority) handleChallenge(req *certRequest, parameters map[string]string) (*certChallengeResponse, error) {
+	now := time.Now()
+	if req.state.Status != "" && (req.state.RemainingTries == 0 || now.After(req.state.Expire)) {
+		return &certChallengeResponse{Status: CertStatusFailure}, nil
+	}
+	ok, err := req.challenge.Handle(&req.state, parameters)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if req.state.RemainingTries == 0 {
+			return &certChallengeResponse{Status: CertStatusFailure}, nil
+		}
+		return &certChallengeResponse{
+			Status:          CertStatusChallenge,
+			ChallengeStatus: req.state.Status,
+			RemainingTries:  req.state.RemainingTries,
+			RemainingTime:   uint64(req.state.Expire.Sub(now) / time.Second),
+		}, nil
+	}
+	d, err := IssueCertificate(&Certificate{
+		Name:      req.key.Name,
+		IssuerID:  lpm.Component("NDNCERT"),
+		Version:   uint64(now.UnixNano() / 1000000),
+		PublicKey: req.key.PublicKey,
+		NotBefore: now,
+		NotAfter:  now.Add(ca.Validity),
+	}, ca.Key)
+	if err != nil {
+		return nil, err
+	}
+	ca.issued.Add(d)
+	return &certChallengeResponse{
+		Status:         CertStatusSuccess,
+		IssuedCertName: d.Name,
+	}, nil
+}
@@ -0,0 +1,116 @@
+package ndn
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// NDNCERT challenge status.
+const (
+	ChallengeStatusNeedCode  = "need-code"
+	ChallengeStatusWrongCode = "wrong-code"
+)
+
+// ChallengeState is the state of a challenge for one request.
+type ChallengeState struct {
+	// Key is the requested key name.
+	Key Name
+	// Status is the challenge status, e.g. ChallengeStatusNeedCode.
+	Status         string
+	RemainingTries uint64
+	Expire         time.Time
+	// Secret is private to CertChallenge.
+	Secret string
+}
+
+// CertChallenge verifies the identity of a requester for CertAuthority.
+type CertChallenge interface {
+	// Name is the challenge type offered to requesters, e.g. "pin".
+	Name() string
+	// Handle processes parameters from the requester.
+	//
+	// It returns true if the challenge succeeds. Otherwise, it updates state for
+	// the next round. If RemainingTries becomes 0 or Expire passes,
+	// the challenge fails.
+	Handle(state *ChallengeState, parameters map[string]string) (bool, error)
+}
+
+// codeChallenge sends a secret code to the requester out-of-band,
+// and asks the requester to enter the code.
+type codeChallenge struct {
+	name     string
+	deliver  func(key Name, parameters map[string]string) (func(code string) error, error)
+	tries    uint64
+	lifetime time.Duration
+}
+
+// NewPINChallenge creates the "pin" challenge.
+//
+// display is invoked with a random 6-digit PIN, which should be shown
+// to the requester by the CA operator.
+// The requester has 3 tries within 1 hour.
+func NewPINChallenge(display func(key Name, pin string) error) CertChallenge {
+	return &codeChallenge{
+		name: "pin",
+		deliver: func(key Name, _ map[string]string) (func(string) error, error) {
+			return func(code string) error {
+				return display(key, code)
+			}, nil
+		},
+		tries:    3,
+		lifetime: time.Hour,
+	}
+}
+
+// NewEmailChallenge creates the "email" challenge.
+//
+// The requester provides "email" in the first round, and send is invoked
+// with this address and a random 6-digit code.
+// The requester has 3 tries within 5 minutes.
+func NewEmailChallenge(send func(email, code string) error) CertChallenge {
+	return &codeChallenge{
+		name: "email",
+		deliver: func(_ Name, parameters map[string]string) (func(string) error, error) {
+			email := parameters["email"]
+			if email == "" {
+				return nil, ErrInvalidRequest
+			}
+			return func(code string) error {
+				return send(email, code)
+			}, nil
+		},
+		tries:    3,
+		lifetime: 5 * time.Minute,
+	}
+}
+
+func (c *codeChallenge) Name() string {
+	return c.name
+}
+
+func (c *codeChallenge) Handle(state *ChallengeState, parameters map[string]string) (bool, error) {
+	if state.Status == "" {
+		send, err := c.deliver(state.Key, parameters)
+		if err != nil {
+			return false, err
+		}
+		n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+		if err != nil {
+			return false, err
+		}
+		state.Secret = fmt.Sprintf("%06d", n)
+		state.Status = ChallengeStatusNeedCode
+		state.RemainingTries = c.tries
+		state.Expire = time.Now().Add(c.lifetime)
+		return false, send(state.Secret)
+	}
+	if subtle.ConstantTimeCompare([]byte(parameters["code"]), []byte(state.Secret)) == 1 {
+		return true, nil
+	}
+	state.Status = ChallengeStatusWrongCode
+	state.RemainingTries--
+	return false, nil
+}
@@ -0,0 +1,133 @@
+package ndn
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+
+	"github.com/go-ndn/lpm"
+)
+
+// ChallengeFunc provides parameters for a challenge.
+//
+// status is empty on the first call, and then it is the challenge status
+// returned by the CA, e.g. "need-code" for email and PIN challenges.
+type ChallengeFunc func(status string) (map[string]string, error)
+
+// CertClient requests certificates from an NDNCERT CA.
+//
+// See https://github.com/named-data/ndncert/wiki/NDNCERT-Protocol-0.3.
+type CertClient struct {
+	Sender
+	// CA is the CA prefix.
+	CA Name
+	// If CAKey is not nil, responses must be signed by CAKey.
+	CAKey Key
+}
+
+// Request obtains a certificate of key from the CA.
+//
+// The CA should offer challenge, and solve is invoked until the challenge
+// succeeds or fails. The issued certificate is returned.
+func (c *CertClient) Request(key Key, challenge string, solve ChallengeFunc) (*Data, error) {
+	cert, err := CertificateToData(key)
+	if err != nil {
+		return nil, err
+	}
+	ecdhKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	// NEW
+	params, err := (&certNewRequest{
+		ECDHPub:     ecdhKey.PublicKey().Bytes(),
+		CertRequest: cert,
+	}).MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	d, err := c.send(&Interest{
+		Name:       certInterestName(c.CA, certComponentNew, params),
+		Parameters: params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var newResp certNewResponse
+	err = newResp.UnmarshalBinary(d.Content)
+	if err != nil {
+		return nil, err
+	}
+	var offered bool
+	for _, name := range newResp.Challenges {
+		if name == challenge {
+			offered = true
+			break
+		}
+	}
+	if !offered {
+		return nil, ErrNotSupported
+	}
+	session, err := newCertSession(ecdhKey, newResp.ECDHPub, newResp.Salt, newResp.RequestID)
+	if err != nil {
+		return nil, err
+	}
+
+	// CHALLENGE
+	var status string
+	for {
+		parameters, err := solve(status)
+		if err != nil {
+			return nil, err
+		}
+		plaintext, err := (&certChallengeRequest{
+			Challenge:  challenge,
+			Parameters: parameters,
+		}).MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		params, err := session.seal(plaintext)
+		if err != nil {
+			return nil, err
+		}
+		d, err := c.send(&Interest{
+			Name:       certInterestName(c.CA, certComponentChallenge, params, lpm.Component(newResp.RequestID)),
+			Parameters: params,
+		})
+		if err != nil {
+			return nil, err
+		}
+		plaintext, err = session.open(d.Content)
+		if err != nil {
+			return nil, err
+		}
+		var resp certChallengeResponse
+		err = resp.UnmarshalBinary(plaintext)
+		if err != nil {
+			return nil, err
+		}
+		switch resp.Status {
+		case CertStatusSuccess:
+			return c.send(&Interest{Name: resp.IssuedCertName})
+		case CertStatusFailure:
+			return nil, ErrChallengeFailed
+		}
+		status = resp.ChallengeStatus
+	}
+}
+
+// send expresses an interest, and verifies the response with CAKey.
+func (c *CertClient) send(i *Interest) (*Data, error) {
+	d, ok := <-c.SendInterest(i)
+	if !ok {
+		return nil, ErrTimeout
+	}
+	if c.CAKey != nil {
+		err := VerifyData(c.CAKey, d)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
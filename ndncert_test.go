@@ -0,0 +1,72 @@
+package ndn
+
+import (
+	"testing"
+)
+
+func TestNDNCERT(t *testing.T) {
+	var pin string
+	ca := NewCertAuthority(NewName("/ndn/guest/alice"), rsaKey,
+		NewPINChallenge(func(key Name, code string) error {
+			pin = code
+			return nil
+		}))
+	consumer, producer := newPipe(func(i *Interest) *Data {
+		d, _ := ca.ServeInterest(i)
+		return d
+	})
+	defer producer.Close()
+	defer consumer.Close()
+
+	client := &CertClient{
+		Sender: consumer,
+		CA:     ca.Prefix,
+		CAKey:  rsaKey,
+	}
+
+	// wrong pin
+	_, err := client.Request(ecdsaKey, "pin", func(status string) (map[string]string, error) {
+		if status == "" {
+			return nil, nil
+		}
+		return map[string]string{"code": "wrong"}, nil
+	})
+	if err != ErrChallengeFailed {
+		t.Fatalf("expect %v, got %v", ErrChallengeFailed, err)
+	}
+
+	_, err = client.Request(ecdsaKey, "email", nil)
+	if err != ErrNotSupported {
+		t.Fatalf("expect %v, got %v", ErrNotSupported, err)
+	}
+
+	d, err := client.Request(ecdsaKey, "pin", func(status string) (map[string]string, error) {
+		if status == "" {
+			return nil, nil
+		}
+		if status != ChallengeStatusNeedCode {
+			t.Fatalf("expect %s, got %s", ChallengeStatusNeedCode, status)
+		}
+		return map[string]string{"code": pin}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := ParseCertificate(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cert.Name.Compare(ecdsaKey.Locator()) != 0 {
+		t.Fatalf("expect %v, got %v", ecdsaKey.Locator(), cert.Name)
+	}
+	if string(cert.IssuerID) != "NDNCERT" {
+		t.Fatalf("expect NDNCERT, got %s", cert.IssuerID)
+	}
+	key, err := CertificateFromData(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.SignatureType() != ecdsaKey.SignatureType() {
+		t.Fatalf("expect %v, got %v", ecdsaKey.SignatureType(), key.SignatureType())
+	}
+}
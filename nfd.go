@@ -12,6 +12,8 @@ import (
 var (
 	ErrTimeout        = errors.New("timeout")
 	ErrResponseStatus = errors.New("bad command response status")
+	ErrNoForwarder    = errors.New("forwarder socket not found")
+	ErrInvalidDataset = errors.New("invalid status dataset")
 )
 
 // Command alters forwarder state.
@@ -43,6 +45,50 @@ type parametersComponent struct {
 	Parameters Parameters `tlv:"104"`
 }
 
+// maskedCommand is Command with Flags always encoded in its parameters.
+//
+// It is sent instead of Command if Mask is set, because Flags 0 with Mask
+// clears the flags in Mask, but a zero Flags of Parameters is not encoded.
+type maskedCommand struct {
+	Local          string                    `tlv:"8"`
+	NFD            string                    `tlv:"8"`
+	Module         string                    `tlv:"8"`
+	Command        string                    `tlv:"8"`
+	Parameters     maskedParametersComponent `tlv:"8"`
+	Timestamp      uint64                    `tlv:"8"`
+	Nonce          uint64                    `tlv:"8"`
+	SignatureInfo  signatureInfoComponent    `tlv:"8"`
+	SignatureValue signatureValueComponent   `tlv:"8*"`
+}
+
+// WriteTo implements tlv.WriteTo.
+func (cmd *maskedCommand) WriteTo(w tlv.Writer) error {
+	return w.Write(cmd, 7)
+}
+
+type maskedParametersComponent struct {
+	Parameters maskedParameters `tlv:"104"`
+}
+
+// maskedParameters has the same fields as Parameters, but Flags is not optional.
+type maskedParameters struct {
+	Name                Name     `tlv:"7?"`
+	FaceID              uint64   `tlv:"105?"`
+	URI                 string   `tlv:"114?"`
+	LocalURI            string   `tlv:"129?"`
+	LocalControlFeature uint64   `tlv:"110?"`
+	Origin              uint64   `tlv:"111?"`
+	Cost                uint64   `tlv:"106?"`
+	Capacity            uint64   `tlv:"131?"`
+	Count               uint64   `tlv:"132?"`
+	Flags               uint64   `tlv:"108"`
+	Mask                uint64   `tlv:"112?"`
+	Strategy            Strategy `tlv:"107?"`
+	ExpirationPeriod    uint64   `tlv:"109?"`
+	FacePersistency     uint64   `tlv:"133?"`
+	MTU                 uint64   `tlv:"137?"`
+}
+
 type signatureInfoComponent struct {
 	SignatureInfo SignatureInfo `tlv:"22"`
 }
@@ -56,15 +102,54 @@ type Parameters struct {
 	Name                Name     `tlv:"7?"`
 	FaceID              uint64   `tlv:"105?"`
 	URI                 string   `tlv:"114?"`
+	LocalURI            string   `tlv:"129?"`
 	LocalControlFeature uint64   `tlv:"110?"`
 	Origin              uint64   `tlv:"111?"`
 	Cost                uint64   `tlv:"106?"`
+	Capacity            uint64   `tlv:"131?"`
+	Count               uint64   `tlv:"132?"`
 	Flags               uint64   `tlv:"108?"`
+	Mask                uint64   `tlv:"112?"`
 	Strategy            Strategy `tlv:"107?"`
 	ExpirationPeriod    uint64   `tlv:"109?"`
 	FacePersistency     uint64   `tlv:"133?"`
+	MTU                 uint64   `tlv:"137?"`
 }
 
+// Route flags in Parameters.Flags.
+//
+// If Flags is 0, the forwarder uses RouteFlagChildInherit.
+const (
+	RouteFlagChildInherit = 1
+	RouteFlagCapture      = 2
+)
+
+// Route origins in Parameters.Origin.
+const (
+	RouteOriginApp       = 0
+	RouteOriginAutoreg   = 64
+	RouteOriginClient    = 65
+	RouteOriginAutoconf  = 66
+	RouteOriginNLSR      = 128
+	RouteOriginPrefixAnn = 129
+	RouteOriginStatic    = 255
+)
+
+// Content store flags in Parameters.Flags, which are changed according to Parameters.Mask.
+const (
+	CSFlagAdmit = 1
+	CSFlagServe = 2
+)
+
+// Forwarding strategies of NFD, which are names without versions.
+const (
+	StrategyBestRoute    = "/localhost/nfd/strategy/best-route"
+	StrategyMulticast    = "/localhost/nfd/strategy/multicast"
+	StrategyASF          = "/localhost/nfd/strategy/asf"
+	StrategyAccess       = "/localhost/nfd/strategy/access"
+	StrategySelfLearning = "/localhost/nfd/strategy/self-learning"
+)
+
 // Strategy is a forwarding strategy for a namespace.
 type Strategy struct {
 	Name Name `tlv:"7"`
@@ -81,39 +166,45 @@ type CommandResponse struct {
 
 // ForwarderStatus is not available in go-nfd.
 type ForwarderStatus struct {
-	NFDVersion       string `tlv:"128"`
-	StartTimestamp   uint64 `tlv:"129"`
-	CurrentTimestamp uint64 `tlv:"130"`
-	NameTreeEntry    uint64 `tlv:"131"`
-	FIBEntry         uint64 `tlv:"132"`
-	PITEntry         uint64 `tlv:"133"`
-	MeasurementEntry uint64 `tlv:"134"`
-	CSEntry          uint64 `tlv:"135"`
-	InInterest       uint64 `tlv:"144"`
-	InData           uint64 `tlv:"145"`
-	InNack           uint64 `tlv:"151"`
-	OutInterest      uint64 `tlv:"146"`
-	OutData          uint64 `tlv:"147"`
-	OutNack          uint64 `tlv:"152"`
+	NFDVersion          string `tlv:"128"`
+	StartTimestamp      uint64 `tlv:"129"`
+	CurrentTimestamp    uint64 `tlv:"130"`
+	NameTreeEntry       uint64 `tlv:"131"`
+	FIBEntry            uint64 `tlv:"132"`
+	PITEntry            uint64 `tlv:"133"`
+	MeasurementEntry    uint64 `tlv:"134"`
+	CSEntry             uint64 `tlv:"135"`
+	InInterest          uint64 `tlv:"144"`
+	InData              uint64 `tlv:"145"`
+	InNack              uint64 `tlv:"151"`
+	OutInterest         uint64 `tlv:"146"`
+	OutData             uint64 `tlv:"147"`
+	OutNack             uint64 `tlv:"152"`
+	SatisfiedInterest   uint64 `tlv:"153?"`
+	UnsatisfiedInterest uint64 `tlv:"154?"`
 }
 
 // FaceStatus is not available in go-nfd.
 type FaceStatus struct {
-	FaceID           uint64 `tlv:"105"`
-	URI              string `tlv:"114"`
-	LocalURI         string `tlv:"129"`
-	ExpirationPeriod uint64 `tlv:"109?"`
-	Scope            uint64 `tlv:"132"`
-	Persistency      uint64 `tlv:"133"`
-	LinkType         uint64 `tlv:"134"`
-	InInterest       uint64 `tlv:"144"`
-	InData           uint64 `tlv:"145"`
-	InNack           uint64 `tlv:"151"`
-	OutInterest      uint64 `tlv:"146"`
-	OutData          uint64 `tlv:"147"`
-	OutNack          uint64 `tlv:"152"`
-	InByte           uint64 `tlv:"148"`
-	OutByte          uint64 `tlv:"149"`
+	FaceID                        uint64 `tlv:"105"`
+	URI                           string `tlv:"114"`
+	LocalURI                      string `tlv:"129"`
+	ExpirationPeriod              uint64 `tlv:"109?"`
+	Scope                         uint64 `tlv:"132"`
+	Persistency                   uint64 `tlv:"133"`
+	LinkType                      uint64 `tlv:"134"`
+	BaseCongestionMarkingInterval uint64 `tlv:"135?"`
+	DefaultCongestionThreshold    uint64 `tlv:"136?"`
+	MTU                           uint64 `tlv:"137?"`
+	InInterest                    uint64 `tlv:"144"`
+	InData                        uint64 `tlv:"145"`
+	InNack                        uint64 `tlv:"151"`
+	OutInterest                   uint64 `tlv:"146"`
+	OutData                       uint64 `tlv:"147"`
+	OutNack                       uint64 `tlv:"152"`
+	InByte                        uint64 `tlv:"148"`
+	OutByte                       uint64 `tlv:"149"`
+	Flags                         uint64 `tlv:"108?"`
 }
 
 // FIBEntry is not available in go-nfd.
@@ -149,43 +240,200 @@ type StrategyChoice struct {
 	Strategy Strategy `tlv:"107"`
 }
 
-// SendControl sends command and waits for its response.
-//
-// ErrResponseStatus is returned if the status code is not 200.
-func SendControl(w Sender, module, command string, params *Parameters, key Key) error {
+// commandSignatureOptions populates SignatureNonce and SignatureTime
+// of command interests for forwarders that follow signed interest format v0.3.
+var commandSignatureOptions = &SignatureOptions{
+	Nonce: true,
+	Time:  true,
+}
+
+// Scopes of commands in Command.Local.
+const (
+	// commandScopeLocalhost is the local forwarder.
+	commandScopeLocalhost = "localhost"
+	// commandScopeLocalhop is the forwarder one hop away, such as a testbed hub.
+	commandScopeLocalhop = "localhop"
+)
+
+// newCommandInterest creates a signed command interest to the forwarder in scope.
+func newCommandInterest(scope, module, command string, params *Parameters, key Signer) (*Interest, error) {
 	cmd := &Command{
-		Local:     "localhost",
+		Local:     scope,
 		NFD:       "nfd",
 		Module:    module,
 		Command:   command,
 		Timestamp: uint64(time.Now().UnixNano() / 1000000),
 		Nonce:     uint64(rand.Uint32()),
 	}
-	var err error
 	cmd.Parameters.Parameters = *params
+	err := commandSignatureOptions.Populate(&cmd.SignatureInfo.SignatureInfo)
+	if err != nil {
+		return nil, err
+	}
 	cmd.SignatureInfo.SignatureInfo.SignatureType = key.SignatureType()
 	cmd.SignatureInfo.SignatureInfo.KeyLocator.Name = key.Locator()
-	cmd.SignatureValue.SignatureValue, err = key.Sign(cmd)
+
+	i := new(Interest)
+	if params.Mask == 0 {
+		cmd.SignatureValue.SignatureValue, err = key.Sign(cmd)
+		if err != nil {
+			return nil, err
+		}
+		err = tlv.Copy(&i.Name, cmd)
+	} else {
+		masked := &maskedCommand{
+			Local:         cmd.Local,
+			NFD:           cmd.NFD,
+			Module:        cmd.Module,
+			Command:       cmd.Command,
+			Timestamp:     cmd.Timestamp,
+			Nonce:         cmd.Nonce,
+			SignatureInfo: cmd.SignatureInfo,
+		}
+		masked.Parameters.Parameters = maskedParameters(*params)
+		masked.SignatureValue.SignatureValue, err = key.Sign(masked)
+		if err != nil {
+			return nil, err
+		}
+		err = tlv.Copy(&i.Name, masked)
+	}
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return i, nil
+}
 
-	i := new(Interest)
-	err = tlv.Copy(&i.Name, cmd)
+// SendControl sends command signed by key and waits for its response.
+//
+// ErrResponseStatus is returned if the status code is not 200.
+func SendControl(w Sender, module, command string, params *Parameters, key Signer) error {
+	_, err := SendCommand(w, module, command, params, key)
+	return err
+}
+
+// SendCommand is like SendControl, but returns the parameters in the response,
+// which tell what the forwarder has actually done.
+func SendCommand(w Sender, module, command string, params *Parameters, key Signer) (*Parameters, error) {
+	return sendCommand(w, commandScopeLocalhost, module, command, params, key)
+}
+
+func sendCommand(w Sender, scope, module, command string, params *Parameters, key Signer) (*Parameters, error) {
+	i, err := newCommandInterest(scope, module, command, params, key)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	d, ok := <-w.SendInterest(i)
 	if !ok {
-		return ErrTimeout
+		return nil, ErrTimeout
 	}
 	var resp CommandResponse
 	err = tlv.Unmarshal(d.Content, &resp, 101)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if resp.StatusCode != 200 {
-		return ErrResponseStatus
+		return nil, ErrResponseStatus
 	}
-	return nil
+	return &resp.Parameters, nil
+}
+
+// Register adds a route to params.Name.
+//
+// FaceID, Origin, Cost, Flags and ExpirationPeriod (in milliseconds) of params are optional.
+// If FaceID is 0, the route points to the face that sends the command.
+// The returned parameters describe the installed route.
+func Register(w Sender, params *Parameters, key Signer) (*Parameters, error) {
+	return SendCommand(w, "rib", "register", params, key)
+}
+
+// Unregister removes the route to params.Name with FaceID and Origin of params.
+func Unregister(w Sender, params *Parameters, key Signer) (*Parameters, error) {
+	return SendCommand(w, "rib", "unregister", &Parameters{
+		Name:   params.Name,
+		FaceID: params.FaceID,
+		Origin: params.Origin,
+	}, key)
+}
+
+// RegisterRemote is like Register, but adds the route on the forwarder one hop away,
+// such as a testbed hub, with a /localhop/nfd/rib/register command.
+//
+// The route points to the face of the hub towards this node, so that interests
+// under params.Name reach this node from the network.
+// The local forwarder must have a route to /localhop/nfd towards the hub, and
+// the hub must trust key, which is usually certified by the testbed.
+// Origin of params is usually RouteOriginClient.
+func RegisterRemote(w Sender, params *Parameters, key Signer) (*Parameters, error) {
+	return sendCommand(w, commandScopeLocalhop, "rib", "register", params, key)
+}
+
+// UnregisterRemote removes the route added by RegisterRemote.
+func UnregisterRemote(w Sender, params *Parameters, key Signer) (*Parameters, error) {
+	return sendCommand(w, commandScopeLocalhop, "rib", "unregister", &Parameters{
+		Name:   params.Name,
+		FaceID: params.FaceID,
+		Origin: params.Origin,
+	}, key)
+}
+
+// AddNextHop adds a next hop to faceID with cost to the FIB entry of name,
+// or updates the cost of an existing next hop.
+//
+// If faceID is 0, the face that sends the command is used.
+// The returned parameters contain the actual FaceID.
+func AddNextHop(w Sender, name Name, faceID, cost uint64, key Signer) (*Parameters, error) {
+	return SendCommand(w, "fib", "add-nexthop", &Parameters{
+		Name:   name,
+		FaceID: faceID,
+		Cost:   cost,
+	}, key)
+}
+
+// RemoveNextHop removes the next hop to faceID from the FIB entry of name.
+//
+// If faceID is 0, the face that sends the command is used.
+func RemoveNextHop(w Sender, name Name, faceID uint64, key Signer) error {
+	return SendControl(w, "fib", "remove-nexthop", &Parameters{
+		Name:   name,
+		FaceID: faceID,
+	}, key)
+}
+
+// SetStrategy chooses strategy, e.g. NewName(StrategyMulticast), for the namespace of name.
+//
+// The returned parameters contain the actual strategy with its version.
+func SetStrategy(w Sender, name, strategy Name, key Signer) (*Parameters, error) {
+	return SendCommand(w, "strategy-choice", "set", &Parameters{
+		Name:     name,
+		Strategy: Strategy{Name: strategy},
+	}, key)
+}
+
+// UnsetStrategy removes the strategy choice of name, so that the namespace
+// inherits the strategy of its parent.
+func UnsetStrategy(w Sender, name Name, key Signer) error {
+	return SendControl(w, "strategy-choice", "unset", &Parameters{
+		Name: name,
+	}, key)
+}
+
+// ConfigureCS changes Capacity, and Flags with Mask of the content store, e.g.
+// Flags 0 with Mask CSFlagAdmit stops caching new data.
+//
+// Capacity is unchanged if it is 0.
+// The returned parameters contain the current Capacity and Flags.
+func ConfigureCS(w Sender, params *Parameters, key Signer) (*Parameters, error) {
+	return SendCommand(w, "cs", "config", params, key)
+}
+
+// EraseCS erases at most count cached data under prefix.
+// If count is 0, the forwarder erases as many as it allows in one command.
+//
+// Count of the returned parameters is the number of erased data, and
+// Capacity is set if the limit is reached and more data may remain.
+func EraseCS(w Sender, prefix Name, count uint64, key Signer) (*Parameters, error) {
+	return SendCommand(w, "cs", "erase", &Parameters{
+		Name:  prefix,
+		Count: count,
+	}, key)
 }
@@ -0,0 +1,111 @@
+package ndn
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-ndn/tlv"
+)
+
+// ErrNoHub is returned if hub discovery finds no hub.
+var ErrNoHub = errors.New("hub not found")
+
+// FCHServer is the NDN Find Closest Hub service used by QueryFCH.
+var FCHServer = "https://ndn-fch.named-data.net/"
+
+// multicastDiscoveryTimeout bounds the multicast stage of FindHub.
+const multicastDiscoveryTimeout = 2 * time.Second
+
+// QueryFCH asks FCHServer for at most k hubs that are closest to this host,
+// and returns their udp uris, e.g. udp://hub.example.net.
+func QueryFCH(ctx context.Context, k int) ([]string, error) {
+	u, err := url.Parse(FCHServer)
+	if err != nil {
+		return nil, err
+	}
+	q := u.Query()
+	q.Set("cap", "udp")
+	q.Set("k", strconv.Itoa(k))
+	u.RawQuery = q.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, ErrNoHub
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return nil, err
+	}
+	// the response is a comma-separated list of hosts.
+	var uris []string
+	for _, host := range strings.Split(string(b), ",") {
+		host = strings.TrimSpace(host)
+		if host == "" {
+			continue
+		}
+		uris = append(uris, "udp://"+host)
+	}
+	if len(uris) == 0 {
+		return nil, ErrNoHub
+	}
+	return uris, nil
+}
+
+// DiscoverHub asks ndn-autoconfig-server on the local network for a hub with f,
+// which is usually a multicast face, and returns the uri of the hub.
+func DiscoverHub(ctx context.Context, f Sender) (string, error) {
+	r := ExpressInterest(ctx, f, &Interest{
+		Name: NewName("/localhop/ndn-autoconf/hub"),
+		Selectors: Selectors{
+			MustBeFresh: true,
+		},
+	})
+	d := r.Data()
+	if d == nil {
+		return "", r.Err()
+	}
+	var uri string
+	err := tlv.Unmarshal(d.Content, &uri, 114)
+	if err != nil {
+		return "", err
+	}
+	return uri, nil
+}
+
+// FindHub runs the stages of ndn-autoconfig in order: DiscoverHub on a
+// multicast face, and QueryFCH.
+//
+// The returned uri is canonicalized with CanonicalFaceURI, e.g. udp4://192.0.2.1:6363.
+func FindHub(ctx context.Context) (string, error) {
+	f, err := NewMulticastFace(MulticastOptions{})
+	if err == nil {
+		mctx, cancel := context.WithTimeout(ctx, multicastDiscoveryTimeout)
+		uri, err := DiscoverHub(mctx, f)
+		cancel()
+		f.Close()
+		if err == nil {
+			return CanonicalFaceURI(uri)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	uris, err := QueryFCH(ctx, 1)
+	if err != nil {
+		return "", err
+	}
+	return CanonicalFaceURI(uris[0])
+}
@@ -0,0 +1,164 @@
+package ndn
+
+import (
+	"bytes"
+
+	"github.com/go-ndn/lpm"
+	"github.com/go-ndn/tlv"
+)
+
+// FetchDataset fetches all segments of the status dataset under prefix, e.g.
+// /localhost/nfd/faces/list, and returns the reassembled content.
+//
+// The first segment must be fresh, so that an outdated dataset is not returned
+// from caches. Later segments are fetched with the version of the first segment.
+//
+// See https://redmine.named-data.net/projects/nfd/wiki/StatusDataset.
+func FetchDataset(w Sender, prefix Name) ([]byte, error) {
+	d, ok := <-w.SendInterest(&Interest{
+		Name: prefix,
+		Selectors: Selectors{
+			MustBeFresh: true,
+		},
+	})
+	if !ok {
+		return nil, ErrTimeout
+	}
+	l := prefix.Len()
+	if d.Name.Len() != l+2 {
+		return nil, ErrInvalidDataset
+	}
+	if _, ok := ParseVersion(d.Name.Components[l]); !ok {
+		return nil, ErrInvalidDataset
+	}
+	version := Name{Components: d.Name.Components[:l+1]}
+
+	var content []byte
+	for segment := uint64(0); ; segment++ {
+		if n, ok := ParseSegment(d.Name.Components[l+1]); !ok || n != segment {
+			var err error
+			d, err = fetchSegment(w, version, segment)
+			if err != nil {
+				return nil, err
+			}
+		}
+		content = append(content, d.Content...)
+		if bytes.Equal(d.MetaInfo.FinalBlockID.Component, d.Name.Components[l+1]) {
+			return content, nil
+		}
+	}
+}
+
+func fetchSegment(w Sender, version Name, segment uint64) (*Data, error) {
+	components := make([]lpm.Component, 0, version.Len()+1)
+	components = append(components, version.Components...)
+	name := Name{Components: append(components, SegmentComponent(segment))}
+	d, ok := <-w.SendInterest(&Interest{
+		Name: name,
+	})
+	if !ok {
+		return nil, ErrTimeout
+	}
+	if d.Name.Compare(name) != 0 {
+		return nil, ErrInvalidDataset
+	}
+	return d, nil
+}
+
+// readDataset fetches /localhost/nfd/<dataset>, and reads every entry with read.
+func readDataset(w Sender, dataset string, read func(tlv.Reader) error) error {
+	content, err := FetchDataset(w, NewName("/localhost/nfd/"+dataset))
+	if err != nil {
+		return err
+	}
+	r := tlv.NewReader(bytes.NewReader(content))
+	for {
+		switch r.Peek() {
+		case 128:
+			err := read(r)
+			if err != nil {
+				return err
+			}
+		case 0:
+			return nil
+		default:
+			return ErrInvalidDataset
+		}
+	}
+}
+
+// GeneralStatus fetches status/general of the forwarder.
+func GeneralStatus(w Sender) (*ForwarderStatus, error) {
+	content, err := FetchDataset(w, NewName("/localhost/nfd/status/general"))
+	if err != nil {
+		return nil, err
+	}
+	// the fields are not wrapped in one element.
+	b := append(appendHeader(nil, 128, len(content)), content...)
+	status := new(ForwarderStatus)
+	err = tlv.Unmarshal(b, status, 128)
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// ListFaces fetches faces/list of the forwarder.
+func ListFaces(w Sender) ([]FaceStatus, error) {
+	var faces []FaceStatus
+	err := readDataset(w, "faces/list", func(r tlv.Reader) error {
+		var face FaceStatus
+		err := r.Read(&face, 128)
+		if err != nil {
+			return err
+		}
+		faces = append(faces, face)
+		return nil
+	})
+	return faces, err
+}
+
+// ListFIB fetches fib/list of the forwarder.
+func ListFIB(w Sender) ([]FIBEntry, error) {
+	var entries []FIBEntry
+	err := readDataset(w, "fib/list", func(r tlv.Reader) error {
+		var entry FIBEntry
+		err := r.Read(&entry, 128)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+		return nil
+	})
+	return entries, err
+}
+
+// ListRIB fetches rib/list of the forwarder.
+func ListRIB(w Sender) ([]RIBEntry, error) {
+	var entries []RIBEntry
+	err := readDataset(w, "rib/list", func(r tlv.Reader) error {
+		var entry RIBEntry
+		err := r.Read(&entry, 128)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+		return nil
+	})
+	return entries, err
+}
+
+// ListStrategyChoices fetches strategy-choice/list of the forwarder.
+func ListStrategyChoices(w Sender) ([]StrategyChoice, error) {
+	var choices []StrategyChoice
+	err := readDataset(w, "strategy-choice/list", func(r tlv.Reader) error {
+		var choice StrategyChoice
+		err := r.Read(&choice, 128)
+		if err != nil {
+			return err
+		}
+		choices = append(choices, choice)
+		return nil
+	})
+	return choices, err
+}
@@ -0,0 +1,124 @@
+package ndn
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidFaceURI is returned if a face uri cannot be canonicalized.
+var ErrInvalidFaceURI = errors.New("invalid face uri")
+
+// Face persistency in Parameters.FacePersistency.
+const (
+	FacePersistencyPersistent = 0
+	FacePersistencyOnDemand   = 1
+	FacePersistencyPermanent  = 2
+)
+
+// Face flags in Parameters.Flags, which are changed according to Parameters.Mask.
+const (
+	FaceFlagLocalFields       = 1
+	FaceFlagLpReliability     = 2
+	FaceFlagCongestionMarking = 4
+)
+
+// CanonicalFaceURI returns the canonical form of a face uri, which the forwarder accepts.
+//
+// udp and tcp uris are resolved to udp4, udp6, tcp4 or tcp6 with an IP address,
+// and port 6363 is used if it is missing, e.g. udp://localhost becomes udp4://127.0.0.1:6363.
+// ether uris have a lowercase MAC address, and dev uris are unchanged.
+func CanonicalFaceURI(uri string) (string, error) {
+	scheme, host, ok := strings.Cut(uri, "://")
+	if !ok {
+		return "", ErrInvalidFaceURI
+	}
+	switch scheme {
+	case "udp", "udp4", "udp6", "tcp", "tcp4", "tcp6":
+		u, err := url.Parse(uri)
+		if err != nil || u.Hostname() == "" || strings.Trim(u.Path, "/") != "" {
+			return "", ErrInvalidFaceURI
+		}
+		return canonicalIPFaceURI(u)
+	case "ether":
+		// a MAC address is not a valid url host.
+		mac, err := net.ParseMAC(strings.TrimSuffix(strings.TrimPrefix(host, "["), "]"))
+		if err != nil {
+			return "", ErrInvalidFaceURI
+		}
+		return "ether://[" + mac.String() + "]", nil
+	case "dev":
+		if host == "" || strings.Contains(host, "/") {
+			return "", ErrInvalidFaceURI
+		}
+		return uri, nil
+	}
+	return "", ErrNotSupported
+}
+
+func canonicalIPFaceURI(u *url.URL) (string, error) {
+	port := u.Port()
+	if port == "" {
+		port = "6363"
+	}
+	if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+		return "", ErrInvalidFaceURI
+	}
+	family := u.Scheme[3:]
+	match := func(ip net.IP) bool {
+		return family == "" || (family == "4") == (ip.To4() != nil)
+	}
+	ip := net.ParseIP(u.Hostname())
+	if ip == nil {
+		ips, err := net.LookupIP(u.Hostname())
+		if err != nil {
+			return "", err
+		}
+		for _, addr := range ips {
+			if match(addr) {
+				ip = addr
+				break
+			}
+		}
+	}
+	if ip == nil || !match(ip) {
+		return "", ErrInvalidFaceURI
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		family, ip = "4", ip4
+	} else {
+		family = "6"
+	}
+	return u.Scheme[:3] + family + "://" + net.JoinHostPort(ip.String(), port), nil
+}
+
+// CreateFace creates a face to params.URI on the forwarder, e.g. a tunnel to
+// another node. The uri is canonicalized with CanonicalFaceURI first.
+//
+// LocalURI, FacePersistency, Flags with Mask, and MTU of params are optional.
+// The returned parameters contain FaceID of the new face.
+func CreateFace(w Sender, params *Parameters, key Signer) (*Parameters, error) {
+	uri, err := CanonicalFaceURI(params.URI)
+	if err != nil {
+		return nil, err
+	}
+	p := *params
+	p.URI = uri
+	return SendCommand(w, "faces", "create", &p, key)
+}
+
+// UpdateFace changes FacePersistency, and Flags with Mask of the face with params.FaceID.
+//
+// If FaceID is 0, the face that sends the command is updated.
+func UpdateFace(w Sender, params *Parameters, key Signer) (*Parameters, error) {
+	return SendCommand(w, "faces", "update", params, key)
+}
+
+// DestroyFace destroys the face with faceID on the forwarder.
+func DestroyFace(w Sender, faceID uint64, key Signer) error {
+	return SendControl(w, "faces", "destroy", &Parameters{
+		FaceID: faceID,
+	}, key)
+}
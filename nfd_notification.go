@@ -0,0 +1,125 @@
+package ndn
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-ndn/lpm"
+	"github.com/go-ndn/tlv"
+)
+
+// Face event kinds in FaceEventNotification.
+const (
+	FaceEventCreated   = 1
+	FaceEventDestroyed = 2
+	FaceEventUp        = 3
+	FaceEventDown      = 4
+)
+
+// FaceEventNotification is published in /localhost/nfd/faces/events.
+type FaceEventNotification struct {
+	Kind        uint64 `tlv:"193"`
+	FaceID      uint64 `tlv:"105"`
+	URI         string `tlv:"114"`
+	LocalURI    string `tlv:"129"`
+	Scope       uint64 `tlv:"132"`
+	Persistency uint64 `tlv:"133"`
+	LinkType    uint64 `tlv:"134"`
+	Flags       uint64 `tlv:"108?"`
+}
+
+// notificationRetryDelay is how long Subscribe waits after a nack.
+const notificationRetryDelay = time.Second
+
+// Subscribe receives the notification stream under prefix, e.g. /localhost/nfd/faces/events,
+// until ctx is done.
+//
+// The first interest fetches the latest fresh notification, and later interests
+// fetch notifications by sequence number. After a timeout or a nack, the stream is
+// fetched from the latest notification again, so notifications may be lost but are not repeated.
+// The returned channel is closed when ctx is done, or if w fails, e.g. w is closed.
+//
+// See https://redmine.named-data.net/projects/nfd/wiki/Notification.
+func Subscribe(ctx context.Context, w Sender, prefix Name) <-chan *Data {
+	ch := make(chan *Data)
+	go func() {
+		defer close(ch)
+		l := prefix.Len()
+		var last uint64
+		var synced, delivered bool
+		for {
+			i := &Interest{
+				Name: prefix,
+				Selectors: Selectors{
+					MustBeFresh: true,
+				},
+			}
+			if synced {
+				components := make([]lpm.Component, 0, l+1)
+				components = append(components, prefix.Components...)
+				i.Name.Components = append(components, SequenceComponent(last+1))
+			} else {
+				// rightmost
+				i.Selectors.ChildSelector = 1
+			}
+			r := ExpressInterest(ctx, w, i)
+			if d := r.Data(); d != nil {
+				if d.Name.Len() != l+1 {
+					synced = false
+					continue
+				}
+				seq, ok := ParseSequence(d.Name.Components[l])
+				if !ok {
+					synced = false
+					continue
+				}
+				if !delivered || seq != last {
+					select {
+					case ch <- d:
+					case <-ctx.Done():
+						return
+					}
+				}
+				last, synced, delivered = seq, true, true
+				continue
+			}
+			synced = false
+			err := r.Err()
+			if _, ok := err.(*NackError); ok {
+				select {
+				case <-time.After(notificationRetryDelay):
+				case <-ctx.Done():
+					return
+				}
+				continue
+			}
+			if err != ErrTimeout {
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// SubscribeFaceEvents receives /localhost/nfd/faces/events with Subscribe.
+//
+// Malformed notifications are dropped.
+func SubscribeFaceEvents(ctx context.Context, w Sender) <-chan *FaceEventNotification {
+	ch := make(chan *FaceEventNotification)
+	go func() {
+		defer close(ch)
+		for d := range Subscribe(ctx, w, NewName("/localhost/nfd/faces/events")) {
+			ev := new(FaceEventNotification)
+			err := tlv.Unmarshal(d.Content, ev, 192)
+			if err != nil {
+				continue
+			}
+			select {
+			case ch <- ev:
+			case <-ctx.Done():
+				// drain the stream until it is closed.
+			}
+		}
+	}()
+	return ch
+}
@@ -0,0 +1,50 @@
+package ndn
+
+import (
+	"net"
+	"os"
+	"strings"
+)
+
+// NFDSocketPaths are unix sockets of the local NFD, in the order they are tried.
+var NFDSocketPaths = []string{
+	"/run/nfd/nfd.sock",
+	"/run/nfd.sock",
+	"/var/run/nfd/nfd.sock",
+	"/var/run/nfd.sock",
+}
+
+// nfdTransportEnv overrides the forwarder transport like ndn-cxx, e.g. unix:///run/nfd.sock.
+const nfdTransportEnv = "NDN_CLIENT_TRANSPORT"
+
+// NFDSocketPath finds the unix socket of the local NFD.
+//
+// If NDN_CLIENT_TRANSPORT is a unix transport, its path is returned.
+// Otherwise, the first existing socket in NFDSocketPaths is returned.
+func NFDSocketPath() (string, error) {
+	if transport := os.Getenv(nfdTransportEnv); strings.HasPrefix(transport, "unix://") {
+		return strings.TrimPrefix(transport, "unix://"), nil
+	}
+	for _, path := range NFDSocketPaths {
+		fi, err := os.Stat(path)
+		if err == nil && fi.Mode()&os.ModeSocket != 0 {
+			return path, nil
+		}
+	}
+	return "", ErrNoForwarder
+}
+
+// DialNFD connects to the local NFD over its unix socket.
+//
+// opts are passed to NewFace.
+func DialNFD(opts ...FaceOption) (Face, error) {
+	path, err := NFDSocketPath()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	return NewFace(conn, opts...), nil
+}
@@ -0,0 +1,594 @@
+package ndn
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-ndn/lpm"
+	"github.com/go-ndn/tlv"
+)
+
+// testForwarder answers command interests like a forwarder.
+type testForwarder struct {
+	handle func(*Command) *CommandResponse
+}
+
+func (f *testForwarder) SendInterest(i *Interest) <-chan *Data {
+	ch := make(chan *Data, 1)
+	defer close(ch)
+	cmd := new(Command)
+	err := tlv.Copy(cmd, &i.Name)
+	if err != nil {
+		return ch
+	}
+	content, err := tlv.Marshal(f.handle(cmd), 101)
+	if err != nil {
+		return ch
+	}
+	ch <- &Data{Name: i.Name, Content: content}
+	return ch
+}
+
+func (f *testForwarder) SendData(*Data) {}
+
+func TestRegister(t *testing.T) {
+	fw := &testForwarder{
+		handle: func(cmd *Command) *CommandResponse {
+			if cmd.Module != "rib" || cmd.Command != "register" {
+				return &CommandResponse{StatusCode: 501}
+			}
+			params := cmd.Parameters.Parameters
+			params.FaceID = 300
+			return &CommandResponse{
+				StatusCode: 200,
+				Parameters: params,
+			}
+		},
+	}
+	params, err := Register(fw, &Parameters{
+		Name:             NewName("/A"),
+		Origin:           RouteOriginClient,
+		Cost:             10,
+		Flags:            RouteFlagCapture,
+		ExpirationPeriod: 60000,
+	}, rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Parameters{
+		Name:             NewName("/A"),
+		FaceID:           300,
+		Origin:           RouteOriginClient,
+		Cost:             10,
+		Flags:            RouteFlagCapture,
+		ExpirationPeriod: 60000,
+	}
+	if params.Name.Compare(want.Name) != 0 || params.FaceID != want.FaceID ||
+		params.Origin != want.Origin || params.Cost != want.Cost ||
+		params.Flags != want.Flags || params.ExpirationPeriod != want.ExpirationPeriod {
+		t.Fatalf("expect %+v, got %+v", want, params)
+	}
+
+	_, err = Unregister(fw, params, rsaKey)
+	if err != ErrResponseStatus {
+		t.Fatalf("expect %v, got %v", ErrResponseStatus, err)
+	}
+}
+
+func TestRegisterRemote(t *testing.T) {
+	fw := &testForwarder{
+		handle: func(cmd *Command) *CommandResponse {
+			if cmd.Local != "localhop" || cmd.Module != "rib" {
+				return &CommandResponse{StatusCode: 403}
+			}
+			return &CommandResponse{
+				StatusCode: 200,
+				Parameters: cmd.Parameters.Parameters,
+			}
+		},
+	}
+	params, err := RegisterRemote(fw, &Parameters{
+		Name:   NewName("/ndn/edu/example/alice"),
+		Origin: RouteOriginClient,
+	}, rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.Origin != RouteOriginClient {
+		t.Fatalf("expect origin %d, got %d", RouteOriginClient, params.Origin)
+	}
+	_, err = UnregisterRemote(fw, params, rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Register(fw, params, rsaKey)
+	if err != ErrResponseStatus {
+		t.Fatalf("expect %v, got %v", ErrResponseStatus, err)
+	}
+}
+
+func TestCanonicalFaceURI(t *testing.T) {
+	for _, test := range []struct {
+		in, want string
+		err      error
+	}{
+		{"udp://192.0.2.1", "udp4://192.0.2.1:6363", nil},
+		{"tcp4://192.0.2.1:56363", "tcp4://192.0.2.1:56363", nil},
+		{"udp://[2001:db8::1]:6363", "udp6://[2001:db8::1]:6363", nil},
+		{"udp6://192.0.2.1", "", ErrInvalidFaceURI},
+		{"tcp://192.0.2.1:70000", "", ErrInvalidFaceURI},
+		{"ether://[08:00:27:AB:CD:EF]", "ether://[08:00:27:ab:cd:ef]", nil},
+		{"dev://eth0", "dev://eth0", nil},
+		{"dev://", "", ErrInvalidFaceURI},
+		{"udp:192.0.2.1", "", ErrInvalidFaceURI},
+		{"unix:///run/nfd.sock", "", ErrNotSupported},
+		{"ws://192.0.2.1", "", ErrNotSupported},
+	} {
+		got, err := CanonicalFaceURI(test.in)
+		if err != test.err || got != test.want {
+			t.Fatalf("CanonicalFaceURI(%s) == %s, %v, got %s, %v", test.in, test.want, test.err, got, err)
+		}
+	}
+}
+
+func TestCreateFace(t *testing.T) {
+	var got []Parameters
+	fw := &testForwarder{
+		handle: func(cmd *Command) *CommandResponse {
+			params := cmd.Parameters.Parameters
+			got = append(got, params)
+			params.FaceID = 300
+			return &CommandResponse{
+				StatusCode: 200,
+				Parameters: params,
+			}
+		},
+	}
+	params, err := CreateFace(fw, &Parameters{
+		URI:             "udp://192.0.2.1",
+		FacePersistency: FacePersistencyPermanent,
+	}, rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.FaceID != 300 || got[0].URI != "udp4://192.0.2.1:6363" {
+		t.Fatalf("unexpected parameters %+v", got[0])
+	}
+
+	// clear a flag
+	_, err = UpdateFace(fw, &Parameters{
+		FaceID: params.FaceID,
+		Mask:   FaceFlagLocalFields,
+	}, rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[1].Mask != FaceFlagLocalFields || got[1].Flags != 0 {
+		t.Fatalf("unexpected flags %d with mask %d", got[1].Flags, got[1].Mask)
+	}
+
+	err = DestroyFace(fw, params.FaceID, rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[2].FaceID != params.FaceID {
+		t.Fatalf("expect %d, got %d", params.FaceID, got[2].FaceID)
+	}
+}
+
+func TestNextHop(t *testing.T) {
+	var got []*Command
+	fw := &testForwarder{
+		handle: func(cmd *Command) *CommandResponse {
+			got = append(got, cmd)
+			return &CommandResponse{
+				StatusCode: 200,
+				Parameters: cmd.Parameters.Parameters,
+			}
+		},
+	}
+	params, err := AddNextHop(fw, NewName("/A"), 300, 10, rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[0].Command != "add-nexthop" || params.FaceID != 300 || params.Cost != 10 {
+		t.Fatalf("unexpected %s %+v", got[0].Command, params)
+	}
+	err = RemoveNextHop(fw, NewName("/A"), 300, rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[1].Module != "fib" || got[1].Command != "remove-nexthop" || got[1].Parameters.Parameters.FaceID != 300 {
+		t.Fatalf("unexpected %s/%s %+v", got[1].Module, got[1].Command, got[1].Parameters.Parameters)
+	}
+}
+
+func TestStrategyChoice(t *testing.T) {
+	var got []*Command
+	fw := &testForwarder{
+		handle: func(cmd *Command) *CommandResponse {
+			got = append(got, cmd)
+			params := cmd.Parameters.Parameters
+			if cmd.Command == "set" {
+				params.Strategy.Name.Components = append(params.Strategy.Name.Components, []byte("v3"))
+			}
+			return &CommandResponse{
+				StatusCode: 200,
+				Parameters: params,
+			}
+		},
+	}
+	params, err := SetStrategy(fw, NewName("/A"), NewName(StrategyMulticast), rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := NewName(StrategyMulticast + "/v3"); params.Strategy.Name.Compare(want) != 0 {
+		t.Fatalf("expect %v, got %v", want, params.Strategy.Name)
+	}
+	err = UnsetStrategy(fw, NewName("/A"), rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[1].Module != "strategy-choice" || got[1].Command != "unset" {
+		t.Fatalf("unexpected %s/%s", got[1].Module, got[1].Command)
+	}
+}
+
+func TestMaskedFlags(t *testing.T) {
+	// Flags 0 is encoded if Mask is set, and only then.
+	zeroFlags := []byte{108, 1, 0}
+	for _, test := range []struct {
+		mask uint64
+		want bool
+	}{
+		{0, false},
+		{CSFlagAdmit, true},
+		{^uint64(0), true},
+	} {
+		i, err := newCommandInterest(commandScopeLocalhost, "cs", "config", &Parameters{
+			Mask: test.mask,
+		}, rsaKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		params := i.Name.Components[4]
+		if got := bytes.Contains(params, zeroFlags); got != test.want {
+			t.Fatalf("mask %x: expect %v, got %v", test.mask, test.want, got)
+		}
+		cmd := new(Command)
+		err = tlv.Copy(cmd, &i.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := cmd.Parameters.Parameters.Mask; got != test.mask {
+			t.Fatalf("expect %x, got %x", test.mask, got)
+		}
+		// the signature covers the parameters as sent
+		err = rsaKey.Verify(&Name{Components: i.Name.Components[:8]}, cmd.SignatureValue.SignatureValue)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestContentStore(t *testing.T) {
+	var got []Parameters
+	fw := &testForwarder{
+		handle: func(cmd *Command) *CommandResponse {
+			params := cmd.Parameters.Parameters
+			got = append(got, params)
+			if cmd.Command == "erase" {
+				params.Count = 2
+			}
+			return &CommandResponse{
+				StatusCode: 200,
+				Parameters: params,
+			}
+		},
+	}
+	_, err := ConfigureCS(fw, &Parameters{
+		Capacity: 1000,
+		Mask:     CSFlagAdmit,
+	}, rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[0].Capacity != 1000 || got[0].Mask != CSFlagAdmit || got[0].Flags != 0 {
+		t.Fatalf("unexpected parameters %+v", got[0])
+	}
+	params, err := EraseCS(fw, NewName("/A"), 0, rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.Count != 2 {
+		t.Fatalf("expect 2, got %d", params.Count)
+	}
+}
+
+// testDataset serves content in segments like a status dataset.
+type testDataset struct {
+	prefix  Name
+	content []byte
+	size    int
+}
+
+func (ds *testDataset) SendInterest(i *Interest) <-chan *Data {
+	ch := make(chan *Data, 1)
+	defer close(ch)
+	l := ds.prefix.Len()
+	var segment uint64
+	switch i.Name.Len() {
+	case l:
+		if !i.Selectors.MustBeFresh {
+			return ch
+		}
+	case l + 2:
+		segment, _ = ParseSegment(i.Name.Components[l+1])
+	default:
+		return ch
+	}
+	start := int(segment) * ds.size
+	if start >= len(ds.content) {
+		return ch
+	}
+	end := start + ds.size
+	if end > len(ds.content) {
+		end = len(ds.content)
+	}
+	var components []lpm.Component
+	components = append(components, ds.prefix.Components...)
+	d := &Data{
+		Name:    Name{Components: append(components, VersionComponent(1), SegmentComponent(segment))},
+		Content: ds.content[start:end],
+	}
+	if end == len(ds.content) {
+		d.MetaInfo.FinalBlockID.Component = SegmentComponent(segment)
+	}
+	ch <- d
+	return ch
+}
+
+func (ds *testDataset) SendData(*Data) {}
+
+func TestListFaces(t *testing.T) {
+	want := []FaceStatus{
+		{FaceID: 1, URI: "internal://", LocalURI: "internal://"},
+		{FaceID: 300, URI: "udp4://192.0.2.1:6363", LocalURI: "udp4://192.0.2.2:6363", InByte: 1000},
+	}
+	var content []byte
+	for i := range want {
+		b, err := tlv.Marshal(&want[i], 128)
+		if err != nil {
+			t.Fatal(err)
+		}
+		content = append(content, b...)
+	}
+	faces, err := ListFaces(&testDataset{
+		prefix:  NewName("/localhost/nfd/faces/list"),
+		content: content,
+		size:    10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(faces) != len(want) {
+		t.Fatalf("expect %d faces, got %d", len(want), len(faces))
+	}
+	for i := range want {
+		if faces[i] != want[i] {
+			t.Fatalf("expect %+v, got %+v", want[i], faces[i])
+		}
+	}
+
+	_, err = ListFIB(&testDataset{
+		prefix:  NewName("/localhost/nfd/fib/list"),
+		content: []byte{1, 0},
+		size:    10,
+	})
+	if err != ErrInvalidDataset {
+		t.Fatalf("expect %v, got %v", ErrInvalidDataset, err)
+	}
+}
+
+func TestGeneralStatus(t *testing.T) {
+	want := ForwarderStatus{NFDVersion: "22.12", InInterest: 10}
+	b, err := tlv.Marshal(&want, 128)
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, err := GeneralStatus(&testDataset{
+		prefix: NewName("/localhost/nfd/status/general"),
+		// without the 2-byte header
+		content: b[2:],
+		size:    100,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *status != want {
+		t.Fatalf("expect %+v, got %+v", want, status)
+	}
+}
+
+// testStream publishes notifications from sequence number 5.
+type testStream struct {
+	prefix Name
+	events []FaceEventNotification
+}
+
+func (s *testStream) SendInterest(i *Interest) <-chan *Data {
+	ch := make(chan *Data, 1)
+	l := s.prefix.Len()
+	seq := uint64(5)
+	if i.Name.Len() == l+1 {
+		seq, _ = ParseSequence(i.Name.Components[l])
+	}
+	if seq-5 >= uint64(len(s.events)) {
+		// pending until the next notification
+		return ch
+	}
+	content, err := tlv.Marshal(&s.events[seq-5], 192)
+	if err == nil {
+		var components []lpm.Component
+		components = append(components, s.prefix.Components...)
+		ch <- &Data{
+			Name:    Name{Components: append(components, SequenceComponent(seq))},
+			Content: content,
+		}
+	}
+	close(ch)
+	return ch
+}
+
+func (s *testStream) SendData(*Data) {}
+
+func TestSubscribeFaceEvents(t *testing.T) {
+	want := []FaceEventNotification{
+		{Kind: FaceEventCreated, FaceID: 300, URI: "udp4://192.0.2.1:6363"},
+		{Kind: FaceEventDown, FaceID: 300, URI: "udp4://192.0.2.1:6363"},
+		{Kind: FaceEventDestroyed, FaceID: 300, URI: "udp4://192.0.2.1:6363"},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	events := SubscribeFaceEvents(ctx, &testStream{
+		prefix: NewName("/localhost/nfd/faces/events"),
+		events: want,
+	})
+	for i := range want {
+		ev, ok := <-events
+		if !ok {
+			t.Fatal("expect event")
+		}
+		if *ev != want[i] {
+			t.Fatalf("expect %+v, got %+v", want[i], ev)
+		}
+	}
+	cancel()
+	for range events {
+		t.Fatal("expect no more event")
+	}
+}
+
+func TestQueryFCH(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("k") != "2" {
+			http.Error(w, "bad k", http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, "hub1.example.net, hub2.example.net")
+	}))
+	defer ts.Close()
+	server := FCHServer
+	defer func() {
+		FCHServer = server
+	}()
+	FCHServer = ts.URL
+
+	uris, err := QueryFCH(context.Background(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"udp://hub1.example.net", "udp://hub2.example.net"}
+	if fmt.Sprint(uris) != fmt.Sprint(want) {
+		t.Fatalf("expect %v, got %v", want, uris)
+	}
+	_, err = QueryFCH(context.Background(), 1)
+	if err != ErrNoHub {
+		t.Fatalf("expect %v, got %v", ErrNoHub, err)
+	}
+}
+
+// testAutoconfServer answers hub discovery like ndn-autoconfig-server.
+type testAutoconfServer string
+
+func (s testAutoconfServer) SendInterest(i *Interest) <-chan *Data {
+	ch := make(chan *Data, 1)
+	content, err := tlv.Marshal(string(s), 114)
+	if err == nil && i.Selectors.MustBeFresh {
+		ch <- &Data{Name: i.Name, Content: content}
+	}
+	close(ch)
+	return ch
+}
+
+func (s testAutoconfServer) SendData(*Data) {}
+
+func TestDiscoverHub(t *testing.T) {
+	uri, err := DiscoverHub(context.Background(), testAutoconfServer("udp://192.0.2.1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uri != "udp://192.0.2.1" {
+		t.Fatalf("expect udp://192.0.2.1, got %s", uri)
+	}
+}
+
+func TestReconnectFaceRoutes(t *testing.T) {
+	cmds := make(chan *Command, 8)
+	fw := &testForwarder{
+		handle: func(cmd *Command) *CommandResponse {
+			cmds <- cmd
+			return &CommandResponse{
+				StatusCode: 200,
+				Parameters: cmd.Parameters.Parameters,
+			}
+		},
+	}
+	// conns are the forwarder side of connections.
+	conns := make(chan Face, 2)
+	states := make(chan FaceState, 3)
+	f, err := NewReconnectFace(ReconnectOptions{
+		Dial: func(opts ...FaceOption) (Face, error) {
+			c1, c2 := net.Pipe()
+			conns <- NewFace(c2, WithInterestHandler(InterestHandlerFunc(func(w Sender, i *Interest) {
+				if d, ok := <-fw.SendInterest(i); ok {
+					w.SendData(d)
+				}
+			})))
+			return NewFace(c1, opts...), nil
+		},
+		Key:        rsaKey,
+		MinBackoff: time.Millisecond,
+		StateChanged: func(s FaceState) {
+			states <- s
+		},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := func(command string, names ...string) {
+		for _, name := range names {
+			cmd := <-cmds
+			if cmd.Command != command || cmd.Parameters.Parameters.Name.String() != name {
+				t.Fatalf("expect %s %s, got %s %v", command, name, cmd.Command, cmd.Parameters.Parameters.Name)
+			}
+		}
+	}
+
+	err = f.RegisterAll([]string{"/A", "/B"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect("register", "/A", "/B")
+
+	// routes are registered again after the connection drops
+	(<-conns).Close()
+	expect("register", "/A", "/B")
+	<-conns
+	for _, want := range []FaceState{FaceDown, FaceUp} {
+		if s := <-states; s != want {
+			t.Fatalf("expect %v, got %v", want, s)
+		}
+	}
+
+	err = f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect("unregister", "/A", "/B")
+}
@@ -0,0 +1,297 @@
+package ndn
+
+import "sync"
+
+// PIB stores public information of identities, keys and certificates.
+//
+// An identity has many keys, and a key has many certificates.
+// If there is no default identity, the next added identity becomes
+// the default. The same applies to the default key of an identity
+// and the default certificate of a key.
+type PIB interface {
+	AddIdentity(identity Name) error
+	Identities() ([]Name, error)
+	// DeleteIdentity also deletes its keys and certificates.
+	DeleteIdentity(identity Name) error
+
+	// AddKey also adds its identity if it does not exist.
+	AddKey(identity, key Name, public []byte) error
+	Keys(identity Name) ([]Name, error)
+	PublicKey(key Name) ([]byte, error)
+	// DeleteKey also deletes its certificates.
+	DeleteKey(key Name) error
+
+	AddCertificate(key Name, cert *Data) error
+	Certificate(name Name) (*Data, error)
+	Certificates(key Name) ([]*Data, error)
+	DeleteCertificate(name Name) error
+
+	SetDefaultIdentity(identity Name) error
+	DefaultIdentity() (Name, error)
+	// SetDefaultKey sets the default key of its identity.
+	SetDefaultKey(key Name) error
+	DefaultKey(identity Name) (Name, error)
+	// SetDefaultCertificate sets the default certificate of its key.
+	SetDefaultCertificate(name Name) error
+	DefaultCertificate(key Name) (*Data, error)
+}
+
+// NewMemoryPIB creates a new thread-safe in-memory PIB.
+func NewMemoryPIB() PIB {
+	return &memoryPIB{
+		identity:    make(map[string]Name),
+		key:         make(map[string]memoryPIBKey),
+		cert:        make(map[string]memoryPIBCertificate),
+		defaultKey:  make(map[string]string),
+		defaultCert: make(map[string]string),
+	}
+}
+
+type memoryPIB struct {
+	identity map[string]Name
+	key      map[string]memoryPIBKey
+	cert     map[string]memoryPIBCertificate
+
+	defaultIdentity string
+	// identity -> key
+	defaultKey map[string]string
+	// key -> certificate
+	defaultCert map[string]string
+	sync.Mutex
+}
+
+type memoryPIBKey struct {
+	identity Name
+	name     Name
+	public   []byte
+}
+
+type memoryPIBCertificate struct {
+	key Name
+	*Data
+}
+
+func (pib *memoryPIB) AddIdentity(identity Name) error {
+	pib.Lock()
+	defer pib.Unlock()
+	pib.addIdentity(identity)
+	return nil
+}
+
+func (pib *memoryPIB) addIdentity(identity Name) {
+	id := identity.String()
+	pib.identity[id] = identity
+	if _, ok := pib.identity[pib.defaultIdentity]; !ok {
+		pib.defaultIdentity = id
+	}
+}
+
+func (pib *memoryPIB) Identities() ([]Name, error) {
+	pib.Lock()
+	defer pib.Unlock()
+	var names []Name
+	for _, name := range pib.identity {
+		names = append(names, name)
+	}
+	sortNames(names)
+	return names, nil
+}
+
+func (pib *memoryPIB) DeleteIdentity(identity Name) error {
+	pib.Lock()
+	defer pib.Unlock()
+	id := identity.String()
+	if _, ok := pib.identity[id]; !ok {
+		return ErrIdentityNotFound
+	}
+	delete(pib.identity, id)
+	for k, key := range pib.key {
+		if key.identity.String() == id {
+			pib.deleteKey(k)
+		}
+	}
+	return nil
+}
+
+func (pib *memoryPIB) AddKey(identity, key Name, public []byte) error {
+	pib.Lock()
+	defer pib.Unlock()
+	pib.addIdentity(identity)
+	id, k := identity.String(), key.String()
+	pib.key[k] = memoryPIBKey{
+		identity: identity,
+		name:     key,
+		public:   public,
+	}
+	if _, ok := pib.key[pib.defaultKey[id]]; !ok {
+		pib.defaultKey[id] = k
+	}
+	return nil
+}
+
+func (pib *memoryPIB) Keys(identity Name) ([]Name, error) {
+	pib.Lock()
+	defer pib.Unlock()
+	id := identity.String()
+	if _, ok := pib.identity[id]; !ok {
+		return nil, ErrIdentityNotFound
+	}
+	var names []Name
+	for _, key := range pib.key {
+		if key.identity.String() == id {
+			names = append(names, key.name)
+		}
+	}
+	sortNames(names)
+	return names, nil
+}
+
+func (pib *memoryPIB) PublicKey(key Name) ([]byte, error) {
+	pib.Lock()
+	defer pib.Unlock()
+	k, ok := pib.key[key.String()]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return k.public, nil
+}
+
+func (pib *memoryPIB) DeleteKey(key Name) error {
+	pib.Lock()
+	defer pib.Unlock()
+	k := key.String()
+	if _, ok := pib.key[k]; !ok {
+		return ErrKeyNotFound
+	}
+	pib.deleteKey(k)
+	return nil
+}
+
+func (pib *memoryPIB) deleteKey(k string) {
+	delete(pib.key, k)
+	for c, cert := range pib.cert {
+		if cert.key.String() == k {
+			delete(pib.cert, c)
+		}
+	}
+}
+
+func (pib *memoryPIB) AddCertificate(key Name, cert *Data) error {
+	pib.Lock()
+	defer pib.Unlock()
+	k, c := key.String(), cert.Name.String()
+	if _, ok := pib.key[k]; !ok {
+		return ErrKeyNotFound
+	}
+	pib.cert[c] = memoryPIBCertificate{
+		key:  key,
+		Data: cert,
+	}
+	if _, ok := pib.cert[pib.defaultCert[k]]; !ok {
+		pib.defaultCert[k] = c
+	}
+	return nil
+}
+
+func (pib *memoryPIB) Certificate(name Name) (*Data, error) {
+	pib.Lock()
+	defer pib.Unlock()
+	cert, ok := pib.cert[name.String()]
+	if !ok {
+		return nil, ErrCertificateNotFound
+	}
+	return cert.Data, nil
+}
+
+func (pib *memoryPIB) Certificates(key Name) ([]*Data, error) {
+	pib.Lock()
+	defer pib.Unlock()
+	k := key.String()
+	if _, ok := pib.key[k]; !ok {
+		return nil, ErrKeyNotFound
+	}
+	var certs []*Data
+	for _, cert := range pib.cert {
+		if cert.key.String() == k {
+			certs = append(certs, cert.Data)
+		}
+	}
+	sortCertificates(certs)
+	return certs, nil
+}
+
+func (pib *memoryPIB) DeleteCertificate(name Name) error {
+	pib.Lock()
+	defer pib.Unlock()
+	c := name.String()
+	if _, ok := pib.cert[c]; !ok {
+		return ErrCertificateNotFound
+	}
+	delete(pib.cert, c)
+	return nil
+}
+
+func (pib *memoryPIB) SetDefaultIdentity(identity Name) error {
+	pib.Lock()
+	defer pib.Unlock()
+	id := identity.String()
+	if _, ok := pib.identity[id]; !ok {
+		return ErrIdentityNotFound
+	}
+	pib.defaultIdentity = id
+	return nil
+}
+
+func (pib *memoryPIB) DefaultIdentity() (Name, error) {
+	pib.Lock()
+	defer pib.Unlock()
+	identity, ok := pib.identity[pib.defaultIdentity]
+	if !ok {
+		return Name{}, ErrIdentityNotFound
+	}
+	return identity, nil
+}
+
+func (pib *memoryPIB) SetDefaultKey(key Name) error {
+	pib.Lock()
+	defer pib.Unlock()
+	k := key.String()
+	ent, ok := pib.key[k]
+	if !ok {
+		return ErrKeyNotFound
+	}
+	pib.defaultKey[ent.identity.String()] = k
+	return nil
+}
+
+func (pib *memoryPIB) DefaultKey(identity Name) (Name, error) {
+	pib.Lock()
+	defer pib.Unlock()
+	ent, ok := pib.key[pib.defaultKey[identity.String()]]
+	if !ok {
+		return Name{}, ErrKeyNotFound
+	}
+	return ent.name, nil
+}
+
+func (pib *memoryPIB) SetDefaultCertificate(name Name) error {
+	pib.Lock()
+	defer pib.Unlock()
+	c := name.String()
+	cert, ok := pib.cert[c]
+	if !ok {
+		return ErrCertificateNotFound
+	}
+	pib.defaultCert[cert.key.String()] = c
+	return nil
+}
+
+func (pib *memoryPIB) DefaultCertificate(key Name) (*Data, error) {
+	pib.Lock()
+	defer pib.Unlock()
+	cert, ok := pib.cert[pib.defaultCert[key.String()]]
+	if !ok {
+		return nil, ErrCertificateNotFound
+	}
+	return cert.Data, nil
+}
@@ -0,0 +1,424 @@
+package ndn
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// NewFilePIB creates a PIB that stores public information in dir.
+//
+// Every identity, key and certificate is named by the hex-encoded SHA256
+// of its wire-encoded name:
+//
+//	dir/<identity>/name
+//	dir/<identity>/<key>/name
+//	dir/<identity>/<key>/public
+//	dir/<identity>/<key>/<certificate>.ndncert
+//
+// Certificates are saved in the same base64 encoding as EncodeCertificate.
+// The default identity, key and certificate are recorded in dir/default,
+// dir/<identity>/default and dir/<identity>/<key>/default.
+func NewFilePIB(dir string) PIB {
+	return &filePIB{dir: dir}
+}
+
+// NewFileKeyChain creates a key chain that persists in dir.
+//
+// Private keys are stored in dir/ndnsec-key-file, and public information is stored in dir/pib.
+func NewFileKeyChain(dir string) *KeyChain {
+	return NewKeyChain(
+		NewFilePIB(filepath.Join(dir, "pib")),
+		NewFileTPM(filepath.Join(dir, "ndnsec-key-file")),
+	)
+}
+
+type filePIB struct {
+	dir string
+	sync.Mutex
+}
+
+const (
+	filePIBName        = "name"
+	filePIBPublic      = "public"
+	filePIBCertificate = ".ndncert"
+	filePIBDefault     = "default"
+)
+
+func writeName(file string, name Name) error {
+	b, err := marshalName(name)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, b, 0644)
+}
+
+func readName(file string) (name Name, err error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return
+	}
+	return unmarshalName(b)
+}
+
+func readCertificate(file string) (*Data, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return decodeCertificateData(bytes.NewReader(b))
+}
+
+// readDefault returns the path of the default entry in dir.
+func readDefault(dir string, notFound error) (string, error) {
+	b, err := ioutil.ReadFile(filepath.Join(dir, filePIBDefault))
+	if os.IsNotExist(err) {
+		return "", notFound
+	}
+	if err != nil {
+		return "", err
+	}
+	file := filepath.Join(dir, string(b))
+	_, err = os.Stat(file)
+	if os.IsNotExist(err) {
+		return "", notFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return file, nil
+}
+
+// writeDefault makes file the default entry in its directory.
+func writeDefault(file string) error {
+	return ioutil.WriteFile(filepath.Join(filepath.Dir(file), filePIBDefault), []byte(filepath.Base(file)), 0644)
+}
+
+// initDefault makes file the default entry if there is no default entry.
+func initDefault(file string) error {
+	_, err := readDefault(filepath.Dir(file), os.ErrNotExist)
+	if err == os.ErrNotExist {
+		return writeDefault(file)
+	}
+	return err
+}
+
+// glob returns the only path that matches pattern.
+func (pib *filePIB) glob(notFound error, elem ...string) (string, error) {
+	matches, err := filepath.Glob(filepath.Join(append([]string{pib.dir}, elem...)...))
+	if err != nil {
+		return "", err
+	}
+	if len(matches) == 0 {
+		return "", notFound
+	}
+	return matches[0], nil
+}
+
+func (pib *filePIB) identityDir(identity Name) (string, error) {
+	h, err := nameHash(identity)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(pib.dir, h), nil
+}
+
+func (pib *filePIB) keyDir(key Name) (string, error) {
+	h, err := nameHash(key)
+	if err != nil {
+		return "", err
+	}
+	return pib.glob(ErrKeyNotFound, "*", h)
+}
+
+func (pib *filePIB) addIdentity(identity Name) (string, error) {
+	dir, err := pib.identityDir(identity)
+	if err != nil {
+		return "", err
+	}
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return "", err
+	}
+	err = writeName(filepath.Join(dir, filePIBName), identity)
+	if err != nil {
+		return "", err
+	}
+	return dir, initDefault(dir)
+}
+
+func (pib *filePIB) AddIdentity(identity Name) error {
+	pib.Lock()
+	defer pib.Unlock()
+	_, err := pib.addIdentity(identity)
+	return err
+}
+
+func (pib *filePIB) Identities() ([]Name, error) {
+	pib.Lock()
+	defer pib.Unlock()
+	matches, err := filepath.Glob(filepath.Join(pib.dir, "*", filePIBName))
+	if err != nil {
+		return nil, err
+	}
+	var names []Name
+	for _, file := range matches {
+		name, err := readName(file)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	sortNames(names)
+	return names, nil
+}
+
+func (pib *filePIB) DeleteIdentity(identity Name) error {
+	pib.Lock()
+	defer pib.Unlock()
+	dir, err := pib.identityDir(identity)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stat(dir)
+	if os.IsNotExist(err) {
+		return ErrIdentityNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(dir)
+}
+
+func (pib *filePIB) AddKey(identity, key Name, public []byte) error {
+	pib.Lock()
+	defer pib.Unlock()
+	dir, err := pib.addIdentity(identity)
+	if err != nil {
+		return err
+	}
+	h, err := nameHash(key)
+	if err != nil {
+		return err
+	}
+	dir = filepath.Join(dir, h)
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+	err = writeName(filepath.Join(dir, filePIBName), key)
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, filePIBPublic), public, 0644)
+	if err != nil {
+		return err
+	}
+	return initDefault(dir)
+}
+
+func (pib *filePIB) Keys(identity Name) ([]Name, error) {
+	pib.Lock()
+	defer pib.Unlock()
+	dir, err := pib.identityDir(identity)
+	if err != nil {
+		return nil, err
+	}
+	_, err = os.Stat(dir)
+	if os.IsNotExist(err) {
+		return nil, ErrIdentityNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	matches, err := filepath.Glob(filepath.Join(dir, "*", filePIBName))
+	if err != nil {
+		return nil, err
+	}
+	var names []Name
+	for _, file := range matches {
+		name, err := readName(file)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	sortNames(names)
+	return names, nil
+}
+
+func (pib *filePIB) PublicKey(key Name) ([]byte, error) {
+	pib.Lock()
+	defer pib.Unlock()
+	dir, err := pib.keyDir(key)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadFile(filepath.Join(dir, filePIBPublic))
+}
+
+func (pib *filePIB) DeleteKey(key Name) error {
+	pib.Lock()
+	defer pib.Unlock()
+	dir, err := pib.keyDir(key)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(dir)
+}
+
+func (pib *filePIB) AddCertificate(key Name, cert *Data) error {
+	pib.Lock()
+	defer pib.Unlock()
+	dir, err := pib.keyDir(key)
+	if err != nil {
+		return err
+	}
+	h, err := nameHash(cert.Name)
+	if err != nil {
+		return err
+	}
+	buf := new(bytes.Buffer)
+	err = encodeCertificateData(cert, buf)
+	if err != nil {
+		return err
+	}
+	file := filepath.Join(dir, h+filePIBCertificate)
+	err = ioutil.WriteFile(file, buf.Bytes(), 0644)
+	if err != nil {
+		return err
+	}
+	return initDefault(file)
+}
+
+func (pib *filePIB) Certificate(name Name) (*Data, error) {
+	pib.Lock()
+	defer pib.Unlock()
+	h, err := nameHash(name)
+	if err != nil {
+		return nil, err
+	}
+	file, err := pib.glob(ErrCertificateNotFound, "*", "*", h+filePIBCertificate)
+	if err != nil {
+		return nil, err
+	}
+	return readCertificate(file)
+}
+
+func (pib *filePIB) Certificates(key Name) ([]*Data, error) {
+	pib.Lock()
+	defer pib.Unlock()
+	dir, err := pib.keyDir(key)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := filepath.Glob(filepath.Join(dir, "*"+filePIBCertificate))
+	if err != nil {
+		return nil, err
+	}
+	var certs []*Data
+	for _, file := range matches {
+		cert, err := readCertificate(file)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	sortCertificates(certs)
+	return certs, nil
+}
+
+func (pib *filePIB) DeleteCertificate(name Name) error {
+	pib.Lock()
+	defer pib.Unlock()
+	h, err := nameHash(name)
+	if err != nil {
+		return err
+	}
+	file, err := pib.glob(ErrCertificateNotFound, "*", "*", h+filePIBCertificate)
+	if err != nil {
+		return err
+	}
+	return os.Remove(file)
+}
+
+func (pib *filePIB) SetDefaultIdentity(identity Name) error {
+	pib.Lock()
+	defer pib.Unlock()
+	dir, err := pib.identityDir(identity)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stat(dir)
+	if os.IsNotExist(err) {
+		return ErrIdentityNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return writeDefault(dir)
+}
+
+func (pib *filePIB) DefaultIdentity() (Name, error) {
+	pib.Lock()
+	defer pib.Unlock()
+	dir, err := readDefault(pib.dir, ErrIdentityNotFound)
+	if err != nil {
+		return Name{}, err
+	}
+	return readName(filepath.Join(dir, filePIBName))
+}
+
+func (pib *filePIB) SetDefaultKey(key Name) error {
+	pib.Lock()
+	defer pib.Unlock()
+	dir, err := pib.keyDir(key)
+	if err != nil {
+		return err
+	}
+	return writeDefault(dir)
+}
+
+func (pib *filePIB) DefaultKey(identity Name) (Name, error) {
+	pib.Lock()
+	defer pib.Unlock()
+	dir, err := pib.identityDir(identity)
+	if err != nil {
+		return Name{}, err
+	}
+	dir, err = readDefault(dir, ErrKeyNotFound)
+	if err != nil {
+		return Name{}, err
+	}
+	return readName(filepath.Join(dir, filePIBName))
+}
+
+func (pib *filePIB) SetDefaultCertificate(name Name) error {
+	pib.Lock()
+	defer pib.Unlock()
+	h, err := nameHash(name)
+	if err != nil {
+		return err
+	}
+	file, err := pib.glob(ErrCertificateNotFound, "*", "*", h+filePIBCertificate)
+	if err != nil {
+		return err
+	}
+	return writeDefault(file)
+}
+
+func (pib *filePIB) DefaultCertificate(key Name) (*Data, error) {
+	pib.Lock()
+	defer pib.Unlock()
+	dir, err := pib.keyDir(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := readDefault(dir, ErrCertificateNotFound)
+	if err != nil {
+		return nil, err
+	}
+	return readCertificate(file)
+}
@@ -0,0 +1,565 @@
+package ndn
+
+import (
+	"bytes"
+	"database/sql"
+
+	"github.com/go-ndn/tlv"
+)
+
+// sqlitePIBSchema is the schema of ndn-cxx pib.db.
+const sqlitePIBSchema = `
+CREATE TABLE IF NOT EXISTS
+  tpmInfo(
+    tpm_locator           BLOB
+  );
+
+CREATE TABLE IF NOT EXISTS
+  identities(
+    id                    INTEGER PRIMARY KEY,
+    identity              BLOB NOT NULL,
+    is_default            INTEGER DEFAULT 0
+  );
+
+CREATE UNIQUE INDEX IF NOT EXISTS
+  identityIndex ON identities(identity);
+
+CREATE TRIGGER IF NOT EXISTS
+  identity_default_before_insert_trigger
+  BEFORE INSERT ON identities
+  FOR EACH ROW
+  WHEN NEW.is_default=1
+  BEGIN
+    UPDATE identities SET is_default=0;
+  END;
+
+CREATE TRIGGER IF NOT EXISTS
+  identity_default_after_insert_trigger
+  AFTER INSERT ON identities
+  FOR EACH ROW
+  WHEN NOT EXISTS
+    (SELECT id
+       FROM identities
+       WHERE is_default=1)
+  BEGIN
+    UPDATE identities
+      SET is_default=1
+      WHERE identity=NEW.identity;
+  END;
+
+CREATE TRIGGER IF NOT EXISTS
+  identity_default_update_trigger
+  BEFORE UPDATE ON identities
+  FOR EACH ROW
+  WHEN NEW.is_default=1 AND OLD.is_default=0
+  BEGIN
+    UPDATE identities SET is_default=0;
+  END;
+
+CREATE TABLE IF NOT EXISTS
+  keys(
+    id                    INTEGER PRIMARY KEY,
+    identity_id           INTEGER NOT NULL,
+    key_name              BLOB NOT NULL,
+    key_bits              BLOB NOT NULL,
+    is_default            INTEGER DEFAULT 0,
+    FOREIGN KEY(identity_id)
+      REFERENCES identities(id)
+      ON DELETE CASCADE
+      ON UPDATE CASCADE
+  );
+
+CREATE UNIQUE INDEX IF NOT EXISTS
+  keyIndex ON keys(key_name);
+
+CREATE TRIGGER IF NOT EXISTS
+  key_default_before_insert_trigger
+  BEFORE INSERT ON keys
+  FOR EACH ROW
+  WHEN NEW.is_default=1
+  BEGIN
+    UPDATE keys
+      SET is_default=0
+      WHERE identity_id=NEW.identity_id;
+  END;
+
+CREATE TRIGGER IF NOT EXISTS
+  key_default_after_insert_trigger
+  AFTER INSERT ON keys
+  FOR EACH ROW
+  WHEN NOT EXISTS
+    (SELECT id
+       FROM keys
+       WHERE is_default=1
+         AND identity_id=NEW.identity_id)
+  BEGIN
+    UPDATE keys
+      SET is_default=1
+      WHERE key_name=NEW.key_name;
+  END;
+
+CREATE TRIGGER IF NOT EXISTS
+  key_default_update_trigger
+  BEFORE UPDATE ON keys
+  FOR EACH ROW
+  WHEN NEW.is_default=1 AND OLD.is_default=0
+  BEGIN
+    UPDATE keys
+      SET is_default=0
+      WHERE identity_id=NEW.identity_id;
+  END;
+
+CREATE TABLE IF NOT EXISTS
+  certificates(
+    id                    INTEGER PRIMARY KEY,
+    key_id                INTEGER NOT NULL,
+    certificate_name      BLOB NOT NULL,
+    certificate_data      BLOB NOT NULL,
+    is_default            INTEGER DEFAULT 0,
+    FOREIGN KEY(key_id)
+      REFERENCES keys(id)
+      ON DELETE CASCADE
+      ON UPDATE CASCADE
+  );
+
+CREATE UNIQUE INDEX IF NOT EXISTS
+  certIndex ON certificates(certificate_name);
+
+CREATE TRIGGER IF NOT EXISTS
+  cert_default_before_insert_trigger
+  BEFORE INSERT ON certificates
+  FOR EACH ROW
+  WHEN NEW.is_default=1
+  BEGIN
+    UPDATE certificates
+      SET is_default=0
+      WHERE key_id=NEW.key_id;
+  END;
+
+CREATE TRIGGER IF NOT EXISTS
+  cert_default_after_insert_trigger
+  AFTER INSERT ON certificates
+  FOR EACH ROW
+  WHEN NOT EXISTS
+    (SELECT id
+       FROM certificates
+       WHERE is_default=1
+         AND key_id=NEW.key_id)
+  BEGIN
+    UPDATE certificates
+      SET is_default=1
+      WHERE certificate_name=NEW.certificate_name;
+  END;
+
+CREATE TRIGGER IF NOT EXISTS
+  cert_default_update_trigger
+  BEFORE UPDATE ON certificates
+  FOR EACH ROW
+  WHEN NEW.is_default=1 AND OLD.is_default=0
+  BEGIN
+    UPDATE certificates
+      SET is_default=0
+      WHERE key_id=NEW.key_id;
+  END;
+`
+
+// NewSQLitePIB creates a PIB backed by an SQLite database
+// that is compatible with ndn-cxx pib.db.
+//
+// db must be opened with an SQLite driver, which is imported by the application.
+// The schema is created if it does not exist.
+func NewSQLitePIB(db *sql.DB) (PIB, error) {
+	_, err := db.Exec(sqlitePIBSchema)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlitePIB{DB: db}, nil
+}
+
+type sqlitePIB struct {
+	*sql.DB
+}
+
+func marshalName(name Name) ([]byte, error) {
+	return tlv.Marshal(&name, 7)
+}
+
+func unmarshalName(b []byte) (name Name, err error) {
+	err = tlv.Unmarshal(b, &name, 7)
+	return
+}
+
+func marshalData(d *Data) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	err := d.WriteTo(tlv.NewWriter(buf))
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func unmarshalData(b []byte) (*Data, error) {
+	d := new(Data)
+	err := d.ReadFrom(tlv.NewReader(bytes.NewReader(b)))
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// queryNames returns names in the first column of all rows.
+func (pib *sqlitePIB) queryNames(query string, args ...interface{}) ([]Name, error) {
+	rows, err := pib.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var names []Name
+	for rows.Next() {
+		var b []byte
+		err = rows.Scan(&b)
+		if err != nil {
+			return nil, err
+		}
+		name, err := unmarshalName(b)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	sortNames(names)
+	return names, nil
+}
+
+// queryCertificates returns certificates in the first column of all rows.
+func (pib *sqlitePIB) queryCertificates(query string, args ...interface{}) ([]*Data, error) {
+	rows, err := pib.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var certs []*Data
+	for rows.Next() {
+		var b []byte
+		err = rows.Scan(&b)
+		if err != nil {
+			return nil, err
+		}
+		cert, err := unmarshalData(b)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	sortCertificates(certs)
+	return certs, nil
+}
+
+// exists checks whether the query returns any row.
+func (pib *sqlitePIB) exists(query string, args ...interface{}) (bool, error) {
+	var id int64
+	err := pib.QueryRow(query, args...).Scan(&id)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (pib *sqlitePIB) AddIdentity(identity Name) error {
+	b, err := marshalName(identity)
+	if err != nil {
+		return err
+	}
+	_, err = pib.Exec("INSERT OR IGNORE INTO identities (identity) values (?)", b)
+	return err
+}
+
+func (pib *sqlitePIB) Identities() ([]Name, error) {
+	return pib.queryNames("SELECT identity FROM identities")
+}
+
+func (pib *sqlitePIB) DeleteIdentity(identity Name) error {
+	b, err := marshalName(identity)
+	if err != nil {
+		return err
+	}
+	ok, err := pib.exists("SELECT id FROM identities WHERE identity=?", b)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrIdentityNotFound
+	}
+	// foreign key constraints are disabled by default in sqlite
+	_, err = pib.Exec(`DELETE FROM certificates WHERE key_id IN
+		(SELECT keys.id FROM keys JOIN identities ON keys.identity_id=identities.id
+			WHERE identities.identity=?)`, b)
+	if err != nil {
+		return err
+	}
+	_, err = pib.Exec("DELETE FROM keys WHERE identity_id IN (SELECT id FROM identities WHERE identity=?)", b)
+	if err != nil {
+		return err
+	}
+	_, err = pib.Exec("DELETE FROM identities WHERE identity=?", b)
+	return err
+}
+
+func (pib *sqlitePIB) AddKey(identity, key Name, public []byte) error {
+	err := pib.AddIdentity(identity)
+	if err != nil {
+		return err
+	}
+	id, err := marshalName(identity)
+	if err != nil {
+		return err
+	}
+	k, err := marshalName(key)
+	if err != nil {
+		return err
+	}
+	if public == nil {
+		public = []byte{}
+	}
+	ok, err := pib.exists("SELECT id FROM keys WHERE key_name=?", k)
+	if err != nil {
+		return err
+	}
+	if ok {
+		_, err = pib.Exec("UPDATE keys SET key_bits=? WHERE key_name=?", public, k)
+		return err
+	}
+	_, err = pib.Exec(`INSERT INTO keys (identity_id, key_name, key_bits)
+		VALUES ((SELECT id FROM identities WHERE identity=?), ?, ?)`, id, k, public)
+	return err
+}
+
+func (pib *sqlitePIB) Keys(identity Name) ([]Name, error) {
+	id, err := marshalName(identity)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := pib.exists("SELECT id FROM identities WHERE identity=?", id)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrIdentityNotFound
+	}
+	return pib.queryNames(`SELECT key_name FROM keys JOIN identities ON keys.identity_id=identities.id
+		WHERE identities.identity=?`, id)
+}
+
+func (pib *sqlitePIB) PublicKey(key Name) ([]byte, error) {
+	k, err := marshalName(key)
+	if err != nil {
+		return nil, err
+	}
+	var public []byte
+	err = pib.QueryRow("SELECT key_bits FROM keys WHERE key_name=?", k).Scan(&public)
+	if err == sql.ErrNoRows {
+		return nil, ErrKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return public, nil
+}
+
+func (pib *sqlitePIB) DeleteKey(key Name) error {
+	k, err := marshalName(key)
+	if err != nil {
+		return err
+	}
+	ok, err := pib.exists("SELECT id FROM keys WHERE key_name=?", k)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrKeyNotFound
+	}
+	_, err = pib.Exec("DELETE FROM certificates WHERE key_id IN (SELECT id FROM keys WHERE key_name=?)", k)
+	if err != nil {
+		return err
+	}
+	_, err = pib.Exec("DELETE FROM keys WHERE key_name=?", k)
+	return err
+}
+
+func (pib *sqlitePIB) AddCertificate(key Name, cert *Data) error {
+	k, err := marshalName(key)
+	if err != nil {
+		return err
+	}
+	ok, err := pib.exists("SELECT id FROM keys WHERE key_name=?", k)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrKeyNotFound
+	}
+	c, err := marshalName(cert.Name)
+	if err != nil {
+		return err
+	}
+	b, err := marshalData(cert)
+	if err != nil {
+		return err
+	}
+	ok, err = pib.exists("SELECT id FROM certificates WHERE certificate_name=?", c)
+	if err != nil {
+		return err
+	}
+	if ok {
+		_, err = pib.Exec("UPDATE certificates SET certificate_data=? WHERE certificate_name=?", b, c)
+		return err
+	}
+	_, err = pib.Exec(`INSERT INTO certificates (key_id, certificate_name, certificate_data)
+		VALUES ((SELECT id FROM keys WHERE key_name=?), ?, ?)`, k, c, b)
+	return err
+}
+
+func (pib *sqlitePIB) Certificate(name Name) (*Data, error) {
+	c, err := marshalName(name)
+	if err != nil {
+		return nil, err
+	}
+	var b []byte
+	err = pib.QueryRow("SELECT certificate_data FROM certificates WHERE certificate_name=?", c).Scan(&b)
+	if err == sql.ErrNoRows {
+		return nil, ErrCertificateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalData(b)
+}
+
+func (pib *sqlitePIB) Certificates(key Name) ([]*Data, error) {
+	k, err := marshalName(key)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := pib.exists("SELECT id FROM keys WHERE key_name=?", k)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return pib.queryCertificates(`SELECT certificate_data FROM certificates JOIN keys ON certificates.key_id=keys.id
+		WHERE keys.key_name=?`, k)
+}
+
+func (pib *sqlitePIB) DeleteCertificate(name Name) error {
+	c, err := marshalName(name)
+	if err != nil {
+		return err
+	}
+	res, err := pib.Exec("DELETE FROM certificates WHERE certificate_name=?", c)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrCertificateNotFound
+	}
+	return nil
+}
+
+// setDefault marks the row that matches query as default.
+func (pib *sqlitePIB) setDefault(notFound error, query string, args ...interface{}) error {
+	res, err := pib.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return notFound
+	}
+	return nil
+}
+
+// queryDefaultName returns the name in the first column of the first row.
+func (pib *sqlitePIB) queryDefaultName(notFound error, query string, args ...interface{}) (Name, error) {
+	var b []byte
+	err := pib.QueryRow(query, args...).Scan(&b)
+	if err == sql.ErrNoRows {
+		return Name{}, notFound
+	}
+	if err != nil {
+		return Name{}, err
+	}
+	return unmarshalName(b)
+}
+
+func (pib *sqlitePIB) SetDefaultIdentity(identity Name) error {
+	id, err := marshalName(identity)
+	if err != nil {
+		return err
+	}
+	return pib.setDefault(ErrIdentityNotFound, "UPDATE identities SET is_default=1 WHERE identity=?", id)
+}
+
+func (pib *sqlitePIB) DefaultIdentity() (Name, error) {
+	return pib.queryDefaultName(ErrIdentityNotFound, "SELECT identity FROM identities WHERE is_default=1")
+}
+
+func (pib *sqlitePIB) SetDefaultKey(key Name) error {
+	k, err := marshalName(key)
+	if err != nil {
+		return err
+	}
+	return pib.setDefault(ErrKeyNotFound, "UPDATE keys SET is_default=1 WHERE key_name=?", k)
+}
+
+func (pib *sqlitePIB) DefaultKey(identity Name) (Name, error) {
+	id, err := marshalName(identity)
+	if err != nil {
+		return Name{}, err
+	}
+	return pib.queryDefaultName(ErrKeyNotFound, `SELECT key_name FROM keys JOIN identities ON keys.identity_id=identities.id
+		WHERE identities.identity=? AND keys.is_default=1`, id)
+}
+
+func (pib *sqlitePIB) SetDefaultCertificate(name Name) error {
+	c, err := marshalName(name)
+	if err != nil {
+		return err
+	}
+	return pib.setDefault(ErrCertificateNotFound, "UPDATE certificates SET is_default=1 WHERE certificate_name=?", c)
+}
+
+func (pib *sqlitePIB) DefaultCertificate(key Name) (*Data, error) {
+	k, err := marshalName(key)
+	if err != nil {
+		return nil, err
+	}
+	var b []byte
+	err = pib.QueryRow(`SELECT certificate_data FROM certificates JOIN keys ON certificates.key_id=keys.id
+		WHERE keys.key_name=? AND certificates.is_default=1`, k).Scan(&b)
+	if err == sql.ErrNoRows {
+		return nil, ErrCertificateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalData(b)
+}
@@ -0,0 +1,131 @@
+//go:build sqlite
+
+package ndn
+
+import (
+	"bytes"
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Run with "go test -tags sqlite".
+
+func openSQLitePIB(t *testing.T) (*sql.DB, PIB) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// each connection to :memory: opens a new database
+	db.SetMaxOpenConns(1)
+	pib, err := NewSQLitePIB(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db, pib
+}
+
+func TestSQLitePIB(t *testing.T) {
+	db, pib := openSQLitePIB(t)
+	defer db.Close()
+	testPIB(t, pib)
+}
+
+func TestSQLitePIBCompatibility(t *testing.T) {
+	// testdata/pib-ndn-cxx.sql is a dump of pib.db that is created by the
+	// ndn-cxx schema and statements with key/default.ndncert.
+	dump, err := ioutil.ReadFile("testdata/pib-ndn-cxx.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	_, err = db.Exec(string(dump))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pib, err := NewSQLitePIB(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open("key/default.ndncert")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	want, err := decodeCertificateData(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	identity, err := pib.DefaultIdentity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if identity.String() != "/ndn/guest/alice" {
+		t.Fatalf("expect /ndn/guest/alice, got %v", identity)
+	}
+	key, err := pib.DefaultKey(identity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.String() != "/ndn/guest/alice/KEY/1434508942077" {
+		t.Fatalf("expect /ndn/guest/alice/KEY/1434508942077, got %v", key)
+	}
+	public, err := pib.PublicKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(want.Content, public) {
+		t.Fatalf("expect public key in %v", want.Name)
+	}
+	cert, err := pib.DefaultCertificate(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := marshalData(cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantWire, err := marshalData(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, wantWire) {
+		t.Fatalf("expect %v, got %v", want.Name, cert.Name)
+	}
+
+	// rows written by this package are readable by ndn-cxx
+	err = pib.AddIdentity(NewName("/ndn/guest/bob"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var isDefault int
+	err = db.QueryRow("SELECT is_default FROM identities WHERE identity=?", []byte("\x07\x11\x08\x03ndn\x08\x05guest\x08\x03bob")).Scan(&isDefault)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if isDefault != 0 {
+		t.Fatalf("expect 0, got %v", isDefault)
+	}
+	err = pib.DeleteIdentity(identity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var n int
+	err = db.QueryRow("SELECT (SELECT COUNT(*) FROM keys) + (SELECT COUNT(*) FROM certificates)").Scan(&n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Fatalf("expect 0, got %v", n)
+	}
+}
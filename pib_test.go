@@ -0,0 +1,167 @@
+package ndn
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+// testPIB checks that pib behaves like ndn-cxx pib.db, where the first
+// identity, key and certificate become the default, and deletion cascades.
+func testPIB(t *testing.T, pib PIB) {
+	names := func(names []Name, err error) string {
+		if err != nil {
+			return err.Error()
+		}
+		return fmt.Sprint(names)
+	}
+	name := func(name Name, err error) string {
+		if err != nil {
+			return err.Error()
+		}
+		return name.String()
+	}
+	cert := func(d *Data, err error) string {
+		if err != nil {
+			return err.Error()
+		}
+		return fmt.Sprintf("%v:%s", d.Name, d.Content)
+	}
+	certs := func(ds []*Data, err error) string {
+		if err != nil {
+			return err.Error()
+		}
+		var s []string
+		for _, d := range ds {
+			s = append(s, fmt.Sprintf("%v:%s", d.Name, d.Content))
+		}
+		return fmt.Sprint(s)
+	}
+	public := func(b []byte, err error) string {
+		if err != nil {
+			return err.Error()
+		}
+		return string(b)
+	}
+	newCert := func(s, content string) *Data {
+		d := &Data{Name: NewName(s), Content: []byte(content)}
+		err := SignData(nil, d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	expect := func(want, got string) {
+		t.Helper()
+		if got != want {
+			t.Fatalf("expect %v, got %v", want, got)
+		}
+	}
+	expectErr := func(want, got error) {
+		t.Helper()
+		if got != want {
+			t.Fatalf("expect %v, got %v", want, got)
+		}
+	}
+	ok := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	a, b, c := NewName("/A"), NewName("/B"), NewName("/C")
+	k1, k2, k3 := NewName("/A/KEY/1"), NewName("/A/KEY/2"), NewName("/C/KEY/3")
+
+	// empty
+	expect("[]", names(pib.Identities()))
+	expect(ErrIdentityNotFound.Error(), name(pib.DefaultIdentity()))
+	expect(ErrIdentityNotFound.Error(), names(pib.Keys(a)))
+	expect(ErrKeyNotFound.Error(), name(pib.DefaultKey(a)))
+	expect(ErrKeyNotFound.Error(), public(pib.PublicKey(k1)))
+	expect(ErrKeyNotFound.Error(), certs(pib.Certificates(k1)))
+	expect(ErrCertificateNotFound.Error(), cert(pib.Certificate(NewName("/A/KEY/1/self/1"))))
+	expectErr(ErrIdentityNotFound, pib.SetDefaultIdentity(a))
+	expectErr(ErrKeyNotFound, pib.SetDefaultKey(k1))
+	expectErr(ErrCertificateNotFound, pib.SetDefaultCertificate(NewName("/A/KEY/1/self/1")))
+	expectErr(ErrIdentityNotFound, pib.DeleteIdentity(a))
+	expectErr(ErrKeyNotFound, pib.DeleteKey(k1))
+	expectErr(ErrCertificateNotFound, pib.DeleteCertificate(NewName("/A/KEY/1/self/1")))
+	expectErr(ErrKeyNotFound, pib.AddCertificate(k1, newCert("/A/KEY/1/self/1", "1")))
+
+	// the first identity, key and certificate are the default
+	ok(pib.AddIdentity(b))
+	ok(pib.AddIdentity(a))
+	ok(pib.AddIdentity(a))
+	expect("[/A /B]", names(pib.Identities()))
+	expect("/B", name(pib.DefaultIdentity()))
+	expect("[]", names(pib.Keys(a)))
+	ok(pib.AddKey(a, k2, []byte("2")))
+	ok(pib.AddKey(a, k1, []byte("old")))
+	ok(pib.AddKey(a, k1, []byte("1")))
+	// the identity is added with its key
+	ok(pib.AddKey(c, k3, []byte("3")))
+	expect("[/A /B /C]", names(pib.Identities()))
+	expect("[/A/KEY/1 /A/KEY/2]", names(pib.Keys(a)))
+	expect("/A/KEY/2", name(pib.DefaultKey(a)))
+	expect("/C/KEY/3", name(pib.DefaultKey(c)))
+	expect(ErrKeyNotFound.Error(), name(pib.DefaultKey(b)))
+	expect("1", public(pib.PublicKey(k1)))
+
+	expect("[]", certs(pib.Certificates(k1)))
+	expect(ErrCertificateNotFound.Error(), cert(pib.DefaultCertificate(k1)))
+	ok(pib.AddCertificate(k1, newCert("/A/KEY/1/self/2", "old")))
+	ok(pib.AddCertificate(k1, newCert("/A/KEY/1/self/2", "self")))
+	ok(pib.AddCertificate(k1, newCert("/A/KEY/1/issuer/1", "issuer")))
+	ok(pib.AddCertificate(k2, newCert("/A/KEY/2/self/1", "2")))
+	expect("[/A/KEY/1/issuer/1:issuer /A/KEY/1/self/2:self]", certs(pib.Certificates(k1)))
+	expect("/A/KEY/1/self/2:self", cert(pib.DefaultCertificate(k1)))
+	expect("/A/KEY/1/issuer/1:issuer", cert(pib.Certificate(NewName("/A/KEY/1/issuer/1"))))
+
+	// defaults are changed
+	ok(pib.SetDefaultIdentity(a))
+	ok(pib.SetDefaultKey(k1))
+	ok(pib.SetDefaultCertificate(NewName("/A/KEY/1/issuer/1")))
+	expect("/A", name(pib.DefaultIdentity()))
+	expect("/A/KEY/1", name(pib.DefaultKey(a)))
+	expect("/A/KEY/1/issuer/1:issuer", cert(pib.DefaultCertificate(k1)))
+
+	// deleting the default leaves no default until another is added
+	ok(pib.DeleteCertificate(NewName("/A/KEY/1/issuer/1")))
+	expect(ErrCertificateNotFound.Error(), cert(pib.DefaultCertificate(k1)))
+	expect("[/A/KEY/1/self/2:self]", certs(pib.Certificates(k1)))
+	ok(pib.AddCertificate(k1, newCert("/A/KEY/1/issuer/2", "issuer")))
+	expect("/A/KEY/1/issuer/2:issuer", cert(pib.DefaultCertificate(k1)))
+
+	// deleting a key deletes its certificates
+	ok(pib.DeleteKey(k1))
+	expect("[/A/KEY/2]", names(pib.Keys(a)))
+	expect(ErrKeyNotFound.Error(), name(pib.DefaultKey(a)))
+	expect(ErrKeyNotFound.Error(), certs(pib.Certificates(k1)))
+	expect(ErrCertificateNotFound.Error(), cert(pib.Certificate(NewName("/A/KEY/1/self/2"))))
+	expectErr(ErrCertificateNotFound, pib.SetDefaultCertificate(NewName("/A/KEY/1/self/2")))
+
+	// deleting an identity deletes its keys and certificates
+	ok(pib.DeleteIdentity(a))
+	expect("[/B /C]", names(pib.Identities()))
+	expect(ErrIdentityNotFound.Error(), name(pib.DefaultIdentity()))
+	expect(ErrIdentityNotFound.Error(), names(pib.Keys(a)))
+	expect(ErrKeyNotFound.Error(), public(pib.PublicKey(k2)))
+	expect(ErrCertificateNotFound.Error(), cert(pib.Certificate(NewName("/A/KEY/2/self/1"))))
+	expect("[/C/KEY/3]", names(pib.Keys(c)))
+	ok(pib.AddIdentity(NewName("/D")))
+	expect("/D", name(pib.DefaultIdentity()))
+}
+
+func TestMemoryPIB(t *testing.T) {
+	testPIB(t, NewMemoryPIB())
+}
+
+func TestFilePIB(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ndn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	testPIB(t, NewFilePIB(dir))
+}
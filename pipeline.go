@@ -0,0 +1,164 @@
+package ndn
+
+import (
+	"math"
+	"time"
+)
+
+// Pipeline decides how many segments Fetch requests at the same time.
+//
+// A pipeline keeps state of one fetch, so it must not be shared between fetches.
+// Fetch does not call its methods concurrently.
+type Pipeline interface {
+	// Window returns the maximum number of pending interests.
+	Window() int
+	// Satisfied is called when an interest is satisfied.
+	// marked is set if the data carries an NDNLPv2 CongestionMark.
+	Satisfied(marked bool)
+	// Lost is called when an interest times out, or is nacked with NackReasonCongestion.
+	Lost()
+}
+
+// fixedPipeline is a pipeline with a fixed window.
+type fixedPipeline int
+
+func (p fixedPipeline) Window() int { return int(p) }
+
+func (fixedPipeline) Satisfied(bool) {}
+
+func (fixedPipeline) Lost() {}
+
+// congestionWindow is the state shared by adaptive pipelines.
+//
+// After the window is decreased, later congestion signals are ignored until
+// a window of interests is satisfied, which is about one round trip, because
+// they are likely caused by the same congestion event.
+type congestionWindow struct {
+	cwnd     float64
+	ssthresh float64
+	// recovery is the number of interests to satisfy before the next decrease.
+	recovery float64
+}
+
+const (
+	initialCongestionWindow = 2
+	minCongestionWindow     = 1
+)
+
+func newCongestionWindow() congestionWindow {
+	return congestionWindow{
+		cwnd:     initialCongestionWindow,
+		ssthresh: math.Inf(1),
+	}
+}
+
+func (w *congestionWindow) Window() int {
+	return int(math.Max(w.cwnd, minCongestionWindow))
+}
+
+// satisfied returns whether the window should grow in congestion avoidance.
+// It grows the window in slow start.
+func (w *congestionWindow) satisfied() bool {
+	if w.recovery > 0 {
+		w.recovery--
+	}
+	if w.cwnd < w.ssthresh {
+		w.cwnd++
+		return false
+	}
+	return true
+}
+
+// decrease sets the window to w.cwnd*beta, unless it is recovering.
+func (w *congestionWindow) decrease(beta float64) bool {
+	if w.recovery > 0 {
+		return false
+	}
+	w.ssthresh = math.Max(w.cwnd*beta, minCongestionWindow)
+	w.cwnd = w.ssthresh
+	w.recovery = w.cwnd
+	return true
+}
+
+// NewAIMDPipeline creates an additive-increase multiplicative-decrease pipeline,
+// like the default of ndncatchunks.
+//
+// The window starts from 2, and grows by 1 every satisfied interest in slow start,
+// or by 1 every window of satisfied interests in congestion avoidance.
+// It is halved on congestion.
+func NewAIMDPipeline() Pipeline {
+	return &aimdPipeline{congestionWindow: newCongestionWindow()}
+}
+
+type aimdPipeline struct {
+	congestionWindow
+}
+
+const aimdBeta = 0.5
+
+func (p *aimdPipeline) Satisfied(marked bool) {
+	if marked {
+		p.Lost()
+		return
+	}
+	if p.satisfied() {
+		p.cwnd += 1 / p.cwnd
+	}
+}
+
+func (p *aimdPipeline) Lost() {
+	p.decrease(aimdBeta)
+}
+
+// NewCUBICPipeline creates a pipeline with the CUBIC window growth function
+// of RFC 8312, which grows faster than AIMD on links with a large
+// bandwidth-delay product.
+func NewCUBICPipeline() Pipeline {
+	return &cubicPipeline{congestionWindow: newCongestionWindow()}
+}
+
+type cubicPipeline struct {
+	congestionWindow
+	// wmax is the window before the last decrease.
+	wmax float64
+	// decreased is when the window is last decreased.
+	decreased time.Time
+}
+
+const (
+	cubicBeta = 0.7
+	cubicC    = 0.4
+)
+
+func (p *cubicPipeline) Satisfied(marked bool) {
+	if marked {
+		p.Lost()
+		return
+	}
+	if !p.satisfied() {
+		return
+	}
+	t := time.Since(p.decreased).Seconds()
+	k := math.Cbrt(p.wmax * (1 - cubicBeta) / cubicC)
+	target := cubicC*math.Pow(t-k, 3) + p.wmax
+	if target > p.cwnd {
+		p.cwnd += (target - p.cwnd) / p.cwnd
+	} else {
+		// grow slowly near wmax
+		p.cwnd += 0.01 / p.cwnd
+	}
+}
+
+func (p *cubicPipeline) Lost() {
+	cwnd := p.cwnd
+	if !p.decrease(cubicBeta) {
+		return
+	}
+	// fast convergence releases bandwidth for new flows.
+	if cwnd < p.wmax {
+		p.wmax = cwnd * (1 + cubicBeta) / 2
+	} else {
+		p.wmax = cwnd
+	}
+	p.decreased = time.Now()
+}
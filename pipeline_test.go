@@ -0,0 +1,86 @@
+package ndn
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestAIMDPipeline(t *testing.T) {
+	p := NewAIMDPipeline()
+	if got := p.Window(); got != 2 {
+		t.Fatalf("expect %v, got %v", 2, got)
+	}
+	// slow start
+	for k := 0; k < 6; k++ {
+		p.Satisfied(false)
+	}
+	if got := p.Window(); got != 8 {
+		t.Fatalf("expect %v, got %v", 8, got)
+	}
+	p.Lost()
+	// losses in the same round trip are ignored
+	p.Lost()
+	p.Satisfied(true)
+	if got := p.Window(); got != 4 {
+		t.Fatalf("expect %v, got %v", 4, got)
+	}
+	// congestion avoidance grows by one every window
+	for k := 0; k < 4; k++ {
+		p.Satisfied(false)
+	}
+	p.Satisfied(true)
+	if got := p.Window(); got != 2 {
+		t.Fatalf("expect %v, got %v", 2, got)
+	}
+	for k := 0; k < 4; k++ {
+		p.Satisfied(false)
+	}
+	if got := p.Window(); got != 3 {
+		t.Fatalf("expect %v, got %v", 3, got)
+	}
+}
+
+func TestCUBICPipeline(t *testing.T) {
+	p := NewCUBICPipeline()
+	for k := 0; k < 98; k++ {
+		p.Satisfied(false)
+	}
+	if got := p.Window(); got != 100 {
+		t.Fatalf("expect %v, got %v", 100, got)
+	}
+	p.Lost()
+	if got := p.Window(); got != 70 {
+		t.Fatalf("expect %v, got %v", 70, got)
+	}
+	// the window grows back towards the window before the decrease
+	for k := 0; k < 1000; k++ {
+		p.Satisfied(false)
+	}
+	if got := p.Window(); got < 70 || got > 100 {
+		t.Fatalf("expect between %v and %v, got %v", 70, 100, got)
+	}
+}
+
+func TestFetchPipeline(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 100)
+	d := &Data{Name: NewName("/A"), Content: content}
+	d.MetaInfo.FreshnessPeriod = 1000
+	segments, err := Segment(d, SegmentOptions{Size: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []Pipeline{NewAIMDPipeline(), NewCUBICPipeline()} {
+		s := &segmentServer{segments: segments, loss: map[uint64]int{10: 1, 20: 2, 30: 1}}
+		got, err := Fetch(context.Background(), s, NewName("/A"), FetchOptions{
+			Pipeline: p,
+			LifeTime: 10,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, content) {
+			t.Fatalf("expect %q, got %q", content, got)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package ndn
+
+import (
+	"encoding/binary"
+	"hash/maphash"
+	"sync"
+
+	"github.com/go-ndn/lpm"
+)
+
+// pitShards is the number of pit shards.
+// Interests with names in different shards are added and satisfied without contention.
+const pitShards = 32
+
+// pit is the pending interest table of a face, which is sharded by name.
+//
+// Pending interests are indexed by the exact name, and data is matched
+// by looking up every prefix of its name.
+type pit struct {
+	seed   maphash.Seed
+	shards [pitShards]pitShard
+}
+
+type pitShard struct {
+	sync.Mutex
+	entries map[string]map[chan<- *Data]pitEntry
+}
+
+func newPIT() *pit {
+	p := &pit{seed: maphash.MakeSeed()}
+	for i := range p.shards {
+		p.shards[i].entries = make(map[string]map[chan<- *Data]pitEntry)
+	}
+	return p
+}
+
+// pitKeys returns the keys of every prefix of name from the shortest,
+// so the key of the first k components is at k.
+func pitKeys(name []lpm.Component) []string {
+	var size int
+	for _, c := range name {
+		size += binary.MaxVarintLen64 + len(c)
+	}
+	b := make([]byte, 0, size)
+	keys := make([]string, len(name)+1)
+	for k, c := range name {
+		b = binary.AppendUvarint(b, uint64(len(c)))
+		b = append(b, c...)
+		keys[k+1] = string(b)
+	}
+	return keys
+}
+
+// pitKey returns the key of name.
+func pitKey(name []lpm.Component) string {
+	var b []byte
+	for _, c := range name {
+		b = binary.AppendUvarint(b, uint64(len(c)))
+		b = append(b, c...)
+	}
+	return string(b)
+}
+
+// update calls f with pending interests of key while the shard of key is locked.
+// The map is nil if there is none, and key is removed if f returns an empty map.
+func (p *pit) update(key string, f func(map[chan<- *Data]pitEntry) map[chan<- *Data]pitEntry) {
+	s := &p.shards[maphash.String(p.seed, key)%pitShards]
+	s.Lock()
+	defer s.Unlock()
+	m := f(s.entries[key])
+	if len(m) == 0 {
+		delete(s.entries, key)
+	} else {
+		s.entries[key] = m
+	}
+}
+
+// visit calls f with pending interests of every key, one shard at a time.
+func (p *pit) visit(f func(map[chan<- *Data]pitEntry) map[chan<- *Data]pitEntry) {
+	for i := range p.shards {
+		s := &p.shards[i]
+		s.Lock()
+		for key, m := range s.entries {
+			m = f(m)
+			if len(m) == 0 {
+				delete(s.entries, key)
+			} else {
+				s.entries[key] = m
+			}
+		}
+		s.Unlock()
+	}
+}
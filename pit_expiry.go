@@ -0,0 +1,126 @@
+package ndn
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+// pitExpiry schedules the expiration of pending interests in a heap, which
+// is served by one goroutine of the face instead of a timer per interest.
+//
+// The lock of a pit shard may be held when it is locked, but not the other way around.
+type pitExpiry struct {
+	sync.Mutex
+	timers pitTimerHeap
+	// wake is signaled when the earliest deadline changes.
+	wake chan struct{}
+}
+
+type pitTimer struct {
+	deadline time.Time
+	// key is the pit key of the interest name.
+	key string
+	ch  chan<- *Data
+	// index is the position in the heap, or -1 if the timer is removed.
+	index int
+}
+
+func newPITExpiry() *pitExpiry {
+	return &pitExpiry{wake: make(chan struct{}, 1)}
+}
+
+// add schedules the pending interest of ch with key to expire at deadline.
+func (e *pitExpiry) add(key string, ch chan<- *Data, deadline time.Time) *pitTimer {
+	t := &pitTimer{
+		deadline: deadline,
+		key:      key,
+		ch:       ch,
+	}
+	e.Lock()
+	defer e.Unlock()
+	heap.Push(&e.timers, t)
+	if t.index == 0 {
+		select {
+		case e.wake <- struct{}{}:
+		default:
+		}
+	}
+	return t
+}
+
+// remove cancels t if it is not yet expired.
+func (e *pitExpiry) remove(t *pitTimer) {
+	e.Lock()
+	defer e.Unlock()
+	if t.index < 0 {
+		return
+	}
+	heap.Remove(&e.timers, t.index)
+}
+
+// expired removes and returns timers with deadlines not after now, and
+// the earliest deadline of the rest, or false if there is none.
+func (e *pitExpiry) expired(now time.Time) ([]*pitTimer, time.Time, bool) {
+	e.Lock()
+	defer e.Unlock()
+	var ts []*pitTimer
+	for len(e.timers) > 0 && !e.timers[0].deadline.After(now) {
+		ts = append(ts, heap.Pop(&e.timers).(*pitTimer))
+	}
+	if len(e.timers) == 0 {
+		return ts, time.Time{}, false
+	}
+	return ts, e.timers[0].deadline, true
+}
+
+type pitTimerHeap []*pitTimer
+
+func (h pitTimerHeap) Len() int { return len(h) }
+
+func (h pitTimerHeap) Less(i, j int) bool { return h[i].deadline.Before(h[j].deadline) }
+
+func (h pitTimerHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *pitTimerHeap) Push(x interface{}) {
+	t := x.(*pitTimer)
+	t.index = len(*h)
+	*h = append(*h, t)
+}
+
+func (h *pitTimerHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	t := old[n-1]
+	old[n-1] = nil
+	t.index = -1
+	*h = old[:n-1]
+	return t
+}
+
+// expirePendingInterests expires pending interests until the face starts closing.
+func (f *face) expirePendingInterests() {
+	timer := time.NewTimer(0)
+	for {
+		select {
+		case <-f.closing:
+			timer.Stop()
+			return
+		case <-f.expiry.wake:
+		case <-timer.C:
+		}
+		ts, next, ok := f.expiry.expired(time.Now())
+		for _, t := range ts {
+			f.expire(t.key, t.ch, true)
+		}
+
+		timer.Stop()
+		if ok {
+			timer.Reset(time.Until(next))
+		}
+	}
+}
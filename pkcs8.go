@@ -0,0 +1,220 @@
+package ndn
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"hash"
+)
+
+// ErrInvalidPassword is returned if an encrypted private key cannot be decrypted.
+var ErrInvalidPassword = errors.New("invalid password")
+
+var (
+	oidPBES2          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2         = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidAES128CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+)
+
+// pbkdf2Iterations follows the default of openssl.
+const pbkdf2Iterations = 2048
+
+type encryptedPrivateKeyInfo struct {
+	Algorithm     pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	KeyLength      int                      `asn1:"optional"`
+	PRF            pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+// privateKey returns the underlying private key of key.
+func privateKey(key Key) (crypto.PrivateKey, error) {
+	switch key := key.(type) {
+	case *RSAKey:
+		return key.PrivateKey, nil
+	case *ECDSAKey:
+		return key.PrivateKey, nil
+	case *Ed25519Key:
+		if key.PrivateKey == nil {
+			return nil, ErrNotSupported
+		}
+		return key.PrivateKey, nil
+	default:
+		return nil, ErrNotSupported
+	}
+}
+
+// encryptPKCS8 encodes key in PKCS#8 EncryptedPrivateKeyInfo
+// with PBES2 (PBKDF2-HMAC-SHA256 and AES-256-CBC).
+func encryptPKCS8(key Key, password []byte) ([]byte, error) {
+	pri, err := privateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(pri)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, 8)
+	_, err = rand.Read(salt)
+	if err != nil {
+		return nil, err
+	}
+	iv := make([]byte, aes.BlockSize)
+	_, err = rand.Read(iv)
+	if err != nil {
+		return nil, err
+	}
+	dk, err := pbkdf2.Key(sha256.New, string(password), salt, pbkdf2Iterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(dk)
+	if err != nil {
+		return nil, err
+	}
+	// PKCS#7 padding
+	padding := aes.BlockSize - len(der)%aes.BlockSize
+	for i := 0; i < padding; i++ {
+		der = append(der, byte(padding))
+	}
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(der, der)
+
+	kdfParams, err := asn1.Marshal(pbkdf2Params{
+		Salt:           salt,
+		IterationCount: pbkdf2Iterations,
+		PRF: pkix.AlgorithmIdentifier{
+			Algorithm:  oidHMACWithSHA256,
+			Parameters: asn1.NullRawValue,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	ivParams, err := asn1.Marshal(iv)
+	if err != nil {
+		return nil, err
+	}
+	params, err := asn1.Marshal(pbes2Params{
+		KeyDerivationFunc: pkix.AlgorithmIdentifier{
+			Algorithm:  oidPBKDF2,
+			Parameters: asn1.RawValue{FullBytes: kdfParams},
+		},
+		EncryptionScheme: pkix.AlgorithmIdentifier{
+			Algorithm:  oidAES256CBC,
+			Parameters: asn1.RawValue{FullBytes: ivParams},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(encryptedPrivateKeyInfo{
+		Algorithm: pkix.AlgorithmIdentifier{
+			Algorithm:  oidPBES2,
+			Parameters: asn1.RawValue{FullBytes: params},
+		},
+		EncryptedData: der,
+	})
+}
+
+// decryptPKCS8 decodes PKCS#8 EncryptedPrivateKeyInfo encrypted with PBES2.
+func decryptPKCS8(name Name, b, password []byte) (Key, error) {
+	var info encryptedPrivateKeyInfo
+	_, err := asn1.Unmarshal(b, &info)
+	if err != nil {
+		return nil, err
+	}
+	if !info.Algorithm.Algorithm.Equal(oidPBES2) {
+		return nil, ErrNotSupported
+	}
+	var params pbes2Params
+	_, err = asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &params)
+	if err != nil {
+		return nil, err
+	}
+	if !params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) {
+		return nil, ErrNotSupported
+	}
+	var kdfParams pbkdf2Params
+	_, err = asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdfParams)
+	if err != nil {
+		return nil, err
+	}
+	var prf func() hash.Hash
+	switch {
+	case kdfParams.PRF.Algorithm == nil, kdfParams.PRF.Algorithm.Equal(oidHMACWithSHA1):
+		prf = sha1.New
+	case kdfParams.PRF.Algorithm.Equal(oidHMACWithSHA256):
+		prf = sha256.New
+	default:
+		return nil, ErrNotSupported
+	}
+	var keyLength int
+	switch {
+	case params.EncryptionScheme.Algorithm.Equal(oidAES128CBC):
+		keyLength = 16
+	case params.EncryptionScheme.Algorithm.Equal(oidAES192CBC):
+		keyLength = 24
+	case params.EncryptionScheme.Algorithm.Equal(oidAES256CBC):
+		keyLength = 32
+	default:
+		return nil, ErrNotSupported
+	}
+	var iv []byte
+	_, err = asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv)
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != aes.BlockSize ||
+		len(info.EncryptedData) == 0 ||
+		len(info.EncryptedData)%aes.BlockSize != 0 {
+		return nil, ErrInvalidPEM
+	}
+
+	dk, err := pbkdf2.Key(prf, string(password), kdfParams.Salt, kdfParams.IterationCount, keyLength)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(dk)
+	if err != nil {
+		return nil, err
+	}
+	der := make([]byte, len(info.EncryptedData))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(der, info.EncryptedData)
+	padding := int(der[len(der)-1])
+	if padding == 0 || padding > aes.BlockSize {
+		return nil, ErrInvalidPassword
+	}
+	for _, b := range der[len(der)-padding:] {
+		if int(b) != padding {
+			return nil, ErrInvalidPassword
+		}
+	}
+	key, err := parsePrivateKey(name, der[:len(der)-padding])
+	if err != nil {
+		return nil, ErrInvalidPassword
+	}
+	return key, nil
+}
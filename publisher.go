@@ -0,0 +1,198 @@
+package ndn
+
+import (
+	"sync"
+	"time"
+
+	"github.com/go-ndn/lpm"
+	"github.com/go-ndn/tlv"
+)
+
+// metadataComponent is the keyword of RDR metadata names /<prefix>/metadata.
+//
+// See https://redmine.named-data.net/projects/ndn-tlv/wiki/RDR.
+const metadataComponent = "metadata"
+
+// PublisherOptions controls NewPublisher.
+type PublisherOptions struct {
+	// Size and Key are used to segment content; see SegmentOptions.
+	Size int
+	Key  Signer
+
+	// FreshnessPeriod is the freshness period of segments, so that interests
+	// with MustBeFresh discover the latest version.
+	// If it is 0, 1 second is used.
+	FreshnessPeriod time.Duration
+
+	// MaxVersions is the maximum number of versions kept.
+	// If it is 0, only the latest version is kept.
+	MaxVersions int
+
+	// MaxAge removes versions published earlier than MaxAge ago,
+	// except the latest one.
+	// If it is 0, versions are only removed by MaxVersions.
+	MaxAge time.Duration
+}
+
+// Publisher is an InterestHandler that serves versions of content under a prefix.
+//
+// An interest for the prefix is answered with the first segment of the latest
+// version, and an RDR metadata interest /<prefix>/metadata with the
+// versioned name of the latest version.
+// An interest for a version is answered from that version, if it is kept.
+type Publisher struct {
+	prefix Name
+	opts   PublisherOptions
+
+	mu       sync.Mutex
+	versions []publishedVersion // from the oldest
+}
+
+type publishedVersion struct {
+	version   uint64
+	published time.Time
+	segments  []*Data
+}
+
+// NewPublisher creates a publisher of content under prefix.
+func NewPublisher(prefix Name, opts PublisherOptions) *Publisher {
+	if opts.FreshnessPeriod <= 0 {
+		opts.FreshnessPeriod = time.Second
+	}
+	if opts.MaxVersions <= 0 {
+		opts.MaxVersions = 1
+	}
+	return &Publisher{
+		prefix: prefix,
+		opts:   opts,
+	}
+}
+
+// Publish segments content as a new version, which is the current Unix time
+// in milliseconds, or one more than the latest version if it is not later.
+// Old versions are removed by MaxVersions and MaxAge.
+func (p *Publisher) Publish(content []byte) (uint64, error) {
+	now := time.Now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	version := uint64(now.UnixMilli())
+	if n := len(p.versions); n > 0 && version <= p.versions[n-1].version {
+		version = p.versions[n-1].version + 1
+	}
+	d := &Data{
+		Name:    p.prefix,
+		Content: content,
+	}
+	d.MetaInfo.FreshnessPeriod = uint64(p.opts.FreshnessPeriod / time.Millisecond)
+	segments, err := Segment(d, SegmentOptions{
+		Size:    p.opts.Size,
+		Version: version,
+		Key:     p.opts.Key,
+	})
+	if err != nil {
+		return 0, err
+	}
+	p.versions = append(p.versions, publishedVersion{
+		version:   version,
+		published: now,
+		segments:  segments,
+	})
+
+	// remove old versions
+	var remove int
+	for remove < len(p.versions)-1 {
+		if len(p.versions)-remove <= p.opts.MaxVersions &&
+			(p.opts.MaxAge == 0 || now.Sub(p.versions[remove].published) <= p.opts.MaxAge) {
+			break
+		}
+		remove++
+	}
+	p.versions = append(p.versions[:0:0], p.versions[remove:]...)
+	return version, nil
+}
+
+// Versions returns versions that are kept, from the oldest.
+func (p *Publisher) Versions() []uint64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	versions := make([]uint64, len(p.versions))
+	for k, v := range p.versions {
+		versions[k] = v.version
+	}
+	return versions
+}
+
+func (p *Publisher) ServeInterest(w Sender, i *Interest) {
+	if !isPrefix(p.prefix, i.Name) {
+		return
+	}
+	rest := i.Name.Components[p.prefix.Len():]
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.versions) == 0 {
+		return
+	}
+	latest := p.versions[len(p.versions)-1]
+	if len(rest) == 1 && string(rest[0]) == metadataComponent {
+		d, err := p.metadata(latest.version)
+		if err != nil {
+			return
+		}
+		if i.Match(d) {
+			w.SendData(d)
+		}
+		return
+	}
+
+	segments := latest.segments
+	if len(rest) > 0 {
+		if version, ok := ParseVersion(rest[0]); ok {
+			segments = nil
+			for _, v := range p.versions {
+				if v.version == version {
+					segments = v.segments
+					break
+				}
+			}
+		}
+	}
+	// the latest version is the rightmost child of the prefix, and
+	// segments of a version are in order.
+	reverse := len(rest) > 0 && i.Selectors.ChildSelector == 1
+	for k := range segments {
+		seg := segments[k]
+		if reverse {
+			seg = segments[len(segments)-1-k]
+		}
+		if i.Match(seg) {
+			w.SendData(seg)
+			return
+		}
+	}
+}
+
+// metadata creates RDR metadata of version, which is named
+// /<prefix>/metadata/<version>/<segment 0>, and carries the versioned name.
+func (p *Publisher) metadata(version uint64) (*Data, error) {
+	versioned := Name{Components: append(p.prefix.Components[:p.prefix.Len():p.prefix.Len()], VersionComponent(version))}
+	content, err := tlv.Marshal(&versioned, 7)
+	if err != nil {
+		return nil, err
+	}
+	components := make([]lpm.Component, 0, p.prefix.Len()+3)
+	components = append(components, p.prefix.Components...)
+	components = append(components, lpm.Component(metadataComponent), VersionComponent(version), SegmentComponent(0))
+	d := &Data{
+		Name:    Name{Components: components},
+		Content: content,
+	}
+	// metadata must be fresh to discover the latest version.
+	d.MetaInfo.FreshnessPeriod = 10
+	d.MetaInfo.FinalBlockID.Component = SegmentComponent(0)
+	err = SignData(p.opts.Key, d)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
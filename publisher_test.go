@@ -0,0 +1,74 @@
+package ndn
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/go-ndn/tlv"
+)
+
+func TestPublisher(t *testing.T) {
+	pub := NewPublisher(NewName("/A"), PublisherOptions{Size: 10, MaxVersions: 2})
+	var versions []uint64
+	for k := 0; k < 3; k++ {
+		version, err := pub.Publish([]byte(fmt.Sprintf("content of version %d", k)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		versions = append(versions, version)
+	}
+	if fmt.Sprint(pub.Versions()) != fmt.Sprint(versions[1:]) {
+		t.Fatalf("expect %v, got %v", versions[1:], pub.Versions())
+	}
+
+	c1, c2 := net.Pipe()
+	producer := NewFace(c2, WithInterestHandler(pub))
+	defer producer.Close()
+	consumer := NewFace(c1)
+	defer consumer.Close()
+
+	// the latest version
+	got, err := Fetch(context.Background(), consumer, NewName("/A"), FetchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "content of version 2" {
+		t.Fatalf("expect %v, got %s", "content of version 2", got)
+	}
+
+	// RDR metadata
+	d, err := SendInterestContext(context.Background(), consumer, &Interest{
+		Name:      NewName("/A/metadata"),
+		Selectors: Selectors{MustBeFresh: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var versioned Name
+	err = tlv.Unmarshal(d.Content, &versioned, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version, ok := ParseVersion(versioned.Components[versioned.Len()-1]); !ok || version != versions[2] {
+		t.Fatalf("expect %v, got %v", versions[2], versioned)
+	}
+
+	for k, test := range []struct {
+		version uint64
+		err     error
+	}{
+		{versions[0], ErrTimeout},
+		{versions[1], nil},
+	} {
+		name := Name{Components: append(NewName("/A").Components, VersionComponent(test.version))}
+		got, err := Fetch(context.Background(), consumer, name, FetchOptions{LifeTime: 50, Retries: 1})
+		if err != test.err {
+			t.Fatalf("expect %v, got %v", test.err, err)
+		}
+		if err == nil && string(got) != fmt.Sprintf("content of version %d", k) {
+			t.Fatalf("expect version %d, got %s", k, got)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package ndn
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimitOptions controls RateLimitMiddleware.
+type RateLimitOptions struct {
+	// Rate is the number of interests allowed per second for each key.
+	// If it is 0, 100 is used.
+	Rate float64
+
+	// Burst is the number of interests allowed at once for each key.
+	// If it is 0, Rate is used.
+	Burst int
+
+	// Key groups interests that share a token bucket,
+	// such as RateLimitByPrefix and RateLimitBySigner.
+	// If it is nil, interests are grouped by name.
+	Key func(*Interest) string
+
+	// MaxKeys is the maximum number of token buckets.
+	// If it is 0, 4096 is used.
+	MaxKeys int
+}
+
+// RateLimitByPrefix groups interests by their first n name components.
+func RateLimitByPrefix(n int) func(*Interest) string {
+	return func(i *Interest) string {
+		if i.Name.Len() <= n {
+			return i.Name.String()
+		}
+		return Name{Components: i.Name.Components[:n]}.String()
+	}
+}
+
+// RateLimitBySigner groups signed interests by KeyLocator, which
+// identifies the client. Unsigned interests share one group.
+func RateLimitBySigner(i *Interest) string {
+	sigInfo, _, _, err := interestSignature(i)
+	if err != nil {
+		return ""
+	}
+	return sigInfo.KeyLocator.Name.String()
+}
+
+// RateLimitMiddleware limits interests with a token bucket for each key.
+//
+// Interests over the limit are rejected with NackReasonCongestion if
+// the Sender implements NackSender, and dropped otherwise.
+// It should be the outermost middleware, so that the face is the Sender.
+func RateLimitMiddleware(opts RateLimitOptions) Middleware {
+	if opts.Rate <= 0 {
+		opts.Rate = 100
+	}
+	if opts.Burst <= 0 {
+		opts.Burst = int(math.Ceil(opts.Rate))
+	}
+	if opts.Key == nil {
+		opts.Key = func(i *Interest) string {
+			return i.Name.String()
+		}
+	}
+	if opts.MaxKeys <= 0 {
+		opts.MaxKeys = 4096
+	}
+	l := &rateLimiter{
+		opts:    opts,
+		buckets: newExpiryCache(opts.MaxKeys),
+	}
+	return func(next InterestHandler) InterestHandler {
+		return InterestHandlerFunc(func(w Sender, i *Interest) {
+			if l.allow(opts.Key(i), time.Now()) {
+				next.ServeInterest(w, i)
+				return
+			}
+			if ns, ok := w.(NackSender); ok {
+				ns.SendNack(i, NackReasonCongestion)
+			}
+		})
+	}
+}
+
+// rateLimiter keeps token buckets in an expiry cache.
+// A bucket expires when it is full again, so that idle keys are forgotten.
+type rateLimiter struct {
+	opts    RateLimitOptions
+	buckets *expiryCache
+	sync.Mutex
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// allow takes a token from the bucket of key.
+func (l *rateLimiter) allow(key string, now time.Time) bool {
+	l.Lock()
+	defer l.Unlock()
+	burst := float64(l.opts.Burst)
+	b := &tokenBucket{tokens: burst, last: now}
+	if v, ok := l.buckets.Get(key); ok {
+		b = v.(*tokenBucket)
+		b.tokens = math.Min(burst, b.tokens+now.Sub(b.last).Seconds()*l.opts.Rate)
+		b.last = now
+	}
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	refill := time.Duration((burst - b.tokens) / l.opts.Rate * float64(time.Second))
+	l.buckets.Add(key, b, refill)
+	return true
+}
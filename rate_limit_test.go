@@ -0,0 +1,50 @@
+package ndn
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	h := ChainHandler(InterestHandlerFunc(func(w Sender, i *Interest) {
+		w.SendData(&Data{Name: i.Name})
+	}), RateLimitMiddleware(RateLimitOptions{
+		Rate:  10,
+		Burst: 2,
+		Key:   RateLimitByPrefix(1),
+	}))
+	c1, c2 := net.Pipe()
+	producer := NewFace(c2, WithInterestHandler(h))
+	defer producer.Close()
+	consumer := NewFace(c1)
+	defer consumer.Close()
+
+	for _, test := range []struct {
+		name  string
+		sleep time.Duration
+		err   error
+	}{
+		{name: "/A/1"},
+		{name: "/A/2"},
+		{name: "/A/3", err: &NackError{Reason: NackReasonCongestion}},
+		// another prefix has its own limit
+		{name: "/B/1"},
+		// a token is added every 100ms
+		{name: "/A/4", sleep: 150 * time.Millisecond},
+		{name: "/A/5", err: &NackError{Reason: NackReasonCongestion}},
+	} {
+		time.Sleep(test.sleep)
+		_, err := SendInterestContext(context.Background(), consumer, &Interest{
+			Name:     NewName(test.name),
+			LifeTime: 1000,
+		})
+		if (err == nil) != (test.err == nil) || (err != nil && err.Error() != test.err.Error()) {
+			t.Fatalf("%s: expect %v, got %v", test.name, test.err, err)
+		}
+	}
+	if got := consumer.(StatsFace).Stats().InNacks; got != 2 {
+		t.Fatalf("expect %v, got %v", 2, got)
+	}
+}
@@ -0,0 +1,198 @@
+package ndn
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-ndn/lpm"
+)
+
+// ErrInvalidRegex is returned if an NDN regex cannot be compiled.
+var ErrInvalidRegex = errors.New("invalid ndn regex")
+
+// Regex is an NDN regular expression that matches names.
+//
+// "<>" matches any component, and "<re>" matches a component that fully matches re.
+// A component or a group in parentheses can be followed by "*", "+", "?" or "{n,m}".
+// "[<a><b>]" matches either component.
+// "^" and "$" anchor the beginning and the end of a name.
+//
+// See https://named-data.net/doc/ndn-cxx/current/tutorials/utils-ndn-regex.html.
+type Regex struct {
+	*regexp.Regexp
+}
+
+// NewRegex compiles an NDN regex.
+func NewRegex(expr string) (*Regex, error) {
+	var buf bytes.Buffer
+	for i := 0; i < len(expr); i++ {
+		switch c := expr[i]; c {
+		case '<':
+			end := strings.IndexByte(expr[i:], '>')
+			if end < 0 {
+				return nil, ErrInvalidRegex
+			}
+			buf.WriteString(componentRegex(expr[i+1 : i+end]))
+			i += end
+		case '[':
+			end := strings.IndexByte(expr[i:], ']')
+			if end < 0 {
+				return nil, ErrInvalidRegex
+			}
+			set := expr[i+1 : i+end]
+			if strings.HasPrefix(set, "^") {
+				return nil, ErrNotSupported
+			}
+			var alt []string
+			for len(set) > 0 {
+				if set[0] != '<' {
+					return nil, ErrInvalidRegex
+				}
+				end := strings.IndexByte(set, '>')
+				if end < 0 {
+					return nil, ErrInvalidRegex
+				}
+				alt = append(alt, componentRegex(set[1:end]))
+				set = set[end+1:]
+			}
+			fmt.Fprintf(&buf, "(?:%s)", strings.Join(alt, "|"))
+			i += end
+		case '^', '$', '(', ')', '*', '+', '?', '|':
+			buf.WriteByte(c)
+		case '{':
+			end := strings.IndexByte(expr[i:], '}')
+			if end < 0 {
+				return nil, ErrInvalidRegex
+			}
+			buf.WriteString(expr[i : i+end+1])
+			i += end
+		case ' ', '\t':
+		default:
+			return nil, ErrInvalidRegex
+		}
+	}
+	re, err := regexp.Compile(buf.String())
+	if err != nil {
+		return nil, ErrInvalidRegex
+	}
+	return &Regex{Regexp: re}, nil
+}
+
+// componentRegex translates the regex of one component into a group,
+// so that a following quantifier applies to the whole component.
+func componentRegex(expr string) string {
+	if expr == "" {
+		return "(?:<[^<>]*>)"
+	}
+	var buf bytes.Buffer
+	for i := 0; i < len(expr); i++ {
+		switch c := expr[i]; c {
+		case '\\':
+			buf.WriteByte(c)
+			if i+1 < len(expr) {
+				i++
+				buf.WriteByte(expr[i])
+			}
+		case '.':
+			buf.WriteString("[^<>]")
+		case '[':
+			buf.WriteByte(c)
+			if i+1 < len(expr) && expr[i+1] == '^' {
+				buf.WriteString("^<>")
+				i++
+			}
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	return "(?:<(?:" + buf.String() + ")>)"
+}
+
+// escapeComponent escapes every byte except unreserved characters in RFC 3986.
+func escapeComponent(c lpm.Component) string {
+	var buf bytes.Buffer
+	for _, b := range c {
+		switch {
+		case 'a' <= b && b <= 'z', 'A' <= b && b <= 'Z', '0' <= b && b <= '9',
+			b == '-', b == '.', b == '_', b == '~':
+			buf.WriteByte(b)
+		default:
+			fmt.Fprintf(&buf, "%%%02X", b)
+		}
+	}
+	return buf.String()
+}
+
+func unescapeComponent(s string) lpm.Component {
+	c := make(lpm.Component, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] == '%' && i+2 < len(s) {
+			b, err := strconv.ParseUint(s[i+1:i+3], 16, 8)
+			if err == nil {
+				c = append(c, byte(b))
+				i += 2
+				continue
+			}
+		}
+		c = append(c, s[i])
+	}
+	return c
+}
+
+// regexString encodes a name as <c1><c2>...
+func regexString(name Name) string {
+	var buf bytes.Buffer
+	for _, c := range name.Components {
+		buf.WriteByte('<')
+		buf.WriteString(escapeComponent(c))
+		buf.WriteByte('>')
+	}
+	return buf.String()
+}
+
+func parseRegexString(s string) (name Name) {
+	for _, c := range strings.Split(s, ">") {
+		if !strings.HasPrefix(c, "<") {
+			continue
+		}
+		name.Components = append(name.Components, unescapeComponent(c[1:]))
+	}
+	return
+}
+
+// Match checks whether the name matches.
+func (re *Regex) Match(name Name) bool {
+	return re.MatchString(regexString(name))
+}
+
+// Expand matches the name, and replaces back references "\N" in template
+// with components captured by the N-th group.
+func (re *Regex) Expand(name Name, template string) (Name, bool) {
+	s := regexString(name)
+	match := re.FindStringSubmatch(s)
+	if match == nil {
+		return Name{}, false
+	}
+	var buf bytes.Buffer
+	for i := 0; i < len(template); i++ {
+		if template[i] != '\\' {
+			buf.WriteByte(template[i])
+			continue
+		}
+		for i+1 < len(template) && template[i+1] == '\\' {
+			i++
+		}
+		if i+1 < len(template) && '0' <= template[i+1] && template[i+1] <= '9' {
+			n := int(template[i+1] - '0')
+			if n < len(match) {
+				buf.WriteString(match[n])
+			}
+			i++
+		}
+	}
+	return parseRegexString(buf.String()), true
+}
@@ -0,0 +1,34 @@
+package ndn
+
+// RemoteSigner implements Signer with a signing service,
+// so that many producers can sign with one centrally held key.
+//
+// Send delivers the signed portion of a packet to the service over
+// any transport, such as HTTP, gRPC or NDN itself.
+// The service returns the signature value of Type; for example,
+// ECDSA signatures are ASN.1-encoded and computed over the SHA256 digest
+// of the signed portion.
+type RemoteSigner struct {
+	Name
+	Type uint64
+	Send func(msg []byte) ([]byte, error)
+}
+
+// Locator returns public key locator.
+func (s *RemoteSigner) Locator() Name {
+	return s.Name
+}
+
+// SignatureType returns signature type generated by the service.
+func (s *RemoteSigner) SignatureType() uint64 {
+	return s.Type
+}
+
+// Sign sends the signed portion of v to the service.
+func (s *RemoteSigner) Sign(v interface{}) ([]byte, error) {
+	msg, err := signedPortion(v)
+	if err != nil {
+		return nil, err
+	}
+	return s.Send(msg)
+}
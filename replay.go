@@ -0,0 +1,106 @@
+package ndn
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Errors introduced by CommandInterestValidator.
+var (
+	ErrReplayed         = errors.New("signed interest is replayed")
+	ErrTimestampTooOld  = errors.New("signed interest timestamp is outside grace period")
+	ErrInvalidTimestamp = errors.New("invalid signed interest timestamp")
+)
+
+// CommandInterestValidator rejects replayed signed interests
+// before they are validated by Validator.
+//
+// The name of a command interest ends with
+// <timestamp>/<nonce>/<SignatureInfo>/<SignatureValue>.
+// For each signer, the timestamp must increase. The first interest from
+// a signer must have a timestamp within GracePeriod of the local clock.
+// A (signer, nonce) pair is also rejected if it is seen within GracePeriod.
+//
+// See https://redmine.named-data.net/projects/ndn-cxx/wiki/CommandInterest.
+type CommandInterestValidator struct {
+	Validator
+	GracePeriod time.Duration
+	// RecordLifetime is how long the last timestamp of a signer is kept.
+	RecordLifetime time.Duration
+
+	timestamps *expiryCache
+	nonces     *expiryCache
+	sync.Mutex
+}
+
+// NewCommandInterestValidator creates a validator with
+// 2-minute grace period and 1-hour record lifetime.
+func NewCommandInterestValidator(v Validator) *CommandInterestValidator {
+	return &CommandInterestValidator{
+		Validator:      v,
+		GracePeriod:    2 * time.Minute,
+		RecordLifetime: time.Hour,
+		timestamps:     newExpiryCache(1000),
+		nonces:         newExpiryCache(4096),
+	}
+}
+
+// replayState extracts signer, timestamp in milliseconds and nonce from a command interest.
+func replayState(i *Interest) (signer string, timestamp uint64, nonce []byte, err error) {
+	l := i.Name.Len()
+	if l < 4 {
+		err = ErrUnsigned
+		return
+	}
+	sigInfo, _, _, err := interestSignature(i)
+	if err != nil {
+		return
+	}
+	signer = sigInfo.KeyLocator.Name.String()
+	timestamp, ok := parseNonNegativeInteger(i.Name.Components[l-4])
+	if !ok {
+		err = ErrInvalidTimestamp
+		return
+	}
+	nonce = i.Name.Components[l-3]
+	return
+}
+
+// ValidateInterest checks timestamp and nonce, and then invokes Validator.
+//
+// The replay state is only updated if the interest is valid.
+func (v *CommandInterestValidator) ValidateInterest(i *Interest) error {
+	signer, timestamp, nonce, err := replayState(i)
+	if err != nil {
+		return err
+	}
+	t := time.Unix(0, int64(timestamp)*int64(time.Millisecond))
+	nonceKey := fmt.Sprintf("%s/%x", signer, nonce)
+
+	v.Lock()
+	defer v.Unlock()
+	last, ok := v.timestamps.Get(signer)
+	if ok {
+		if !t.After(last.(time.Time)) {
+			return ErrReplayed
+		}
+	} else {
+		now := time.Now()
+		if t.Before(now.Add(-v.GracePeriod)) || t.After(now.Add(v.GracePeriod)) {
+			return ErrTimestampTooOld
+		}
+	}
+	if _, ok := v.nonces.Get(nonceKey); ok {
+		return ErrReplayed
+	}
+
+	err = v.Validator.ValidateInterest(i)
+	if err != nil {
+		return err
+	}
+	v.timestamps.Add(signer, t, v.RecordLifetime)
+	v.nonces.Add(nonceKey, true, v.GracePeriod)
+	return nil
+}
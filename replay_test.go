@@ -0,0 +1,66 @@
+package ndn
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCommandInterestValidator(t *testing.T) {
+	anchor, err := CertificateToData(ecdsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cv := NewValidatorConfig()
+	err = cv.Load([]byte(`
+rule
+{
+  for interest
+  filter
+  {
+    type name
+    regex ^<localhost><nfd><>*$
+  }
+  checker
+  {
+    type customized
+    sig-type ecdsa-sha256
+    key-locator
+    {
+      type name
+      regex ^<ndn><guest><alice><>*$
+    }
+  }
+}
+`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cv.AddTrustAnchor(anchor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := NewCommandInterestValidator(cv)
+
+	i1, err := newCommandInterest(commandScopeLocalhost, "rib", "register", &Parameters{Name: NewName("/hello")}, ecdsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = v.ValidateInterest(i1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = v.ValidateInterest(i1)
+	if err != ErrReplayed {
+		t.Fatalf("expect %v, got %v", ErrReplayed, err)
+	}
+
+	time.Sleep(2 * time.Millisecond)
+	i2, err := newCommandInterest(commandScopeLocalhost, "rib", "register", &Parameters{Name: NewName("/hello")}, ecdsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = v.ValidateInterest(i2)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
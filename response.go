@@ -0,0 +1,134 @@
+package ndn
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Nack reasons in NDNLPv2.
+const (
+	NackReasonNone       = 0
+	NackReasonCongestion = 50
+	NackReasonDuplicate  = 100
+	NackReasonNoRoute    = 150
+)
+
+// NackError is returned when an interest is rejected by a network nack.
+type NackError struct {
+	Reason uint64
+}
+
+func (e *NackError) Error() string {
+	switch e.Reason {
+	case NackReasonCongestion:
+		return "nack: congestion"
+	case NackReasonDuplicate:
+		return "nack: duplicate"
+	case NackReasonNoRoute:
+		return "nack: no route"
+	}
+	return fmt.Sprintf("nack: reason %d", e.Reason)
+}
+
+// Response is the outcome of an interest.
+type Response struct {
+	ch  <-chan *Data
+	ctx context.Context
+	// err is set before ch is closed.
+	err error
+	// congestionMark is set before data is sent to ch.
+	congestionMark uint64
+
+	once sync.Once
+	data *Data
+}
+
+func newResponse(ctx context.Context) (*Response, chan *Data) {
+	ch := make(chan *Data, 1)
+	return &Response{
+		ch:  ch,
+		ctx: ctx,
+	}, ch
+}
+
+func (r *Response) wait() {
+	r.once.Do(func() {
+		d, ok := <-r.ch
+		if ok {
+			r.data = d
+			return
+		}
+		if r.err != nil {
+			return
+		}
+		if err := r.ctx.Err(); err != nil {
+			r.err = err
+		} else {
+			r.err = ErrTimeout
+		}
+	})
+}
+
+// Data waits for the response, and returns nil if the interest is not satisfied.
+func (r *Response) Data() *Data {
+	r.wait()
+	return r.data
+}
+
+// Nack waits for the response, and returns the nack reason if the interest is rejected.
+func (r *Response) Nack() (uint64, bool) {
+	r.wait()
+	if err, ok := r.err.(*NackError); ok {
+		return err.Reason, true
+	}
+	return 0, false
+}
+
+// Err waits for the response, and returns nil if data is received.
+//
+// Otherwise, it returns *NackError for a nack, ErrTimeout if the interest expires,
+// ctx.Err() if ctx is done first, ErrFaceClosed if the face is closed first,
+// or the error of a validating face.
+func (r *Response) Err() error {
+	r.wait()
+	return r.err
+}
+
+// CongestionMark waits for the response, and returns the NDNLPv2 CongestionMark
+// of the data, which is 0 if the data is not marked or the interest is not satisfied.
+func (r *Response) CongestionMark() uint64 {
+	r.wait()
+	if r.data == nil {
+		return 0
+	}
+	return r.congestionMark
+}
+
+// ResponseSender is a Sender that tells why an interest is not satisfied.
+type ResponseSender interface {
+	ContextSender
+	ExpressInterest(ctx context.Context, i *Interest) *Response
+}
+
+// ExpressInterest sends i with s, and returns its response.
+//
+// If s does not implement ResponseSender, nacks are reported as timeouts.
+func ExpressInterest(ctx context.Context, s Sender, i *Interest) *Response {
+	if rs, ok := s.(ResponseSender); ok {
+		return rs.ExpressInterest(ctx, i)
+	}
+	r, ch := newResponse(ctx)
+	pending := sendInterest(ctx, s, i)
+	go func() {
+		select {
+		case d, ok := <-pending:
+			if ok {
+				ch <- d
+			}
+		case <-ctx.Done():
+		}
+		close(ch)
+	}()
+	return r
+}
@@ -11,9 +11,17 @@ import (
 )
 
 // RSAKey implements Key.
+//
+// If PSS is true, it signs with RSASSA-PSS instead of PKCS#1 v1.5.
 type RSAKey struct {
 	Name
 	*rsa.PrivateKey
+	PSS bool
+}
+
+// pssOptions follows RFC 4055, where salt length equals hash length.
+var pssOptions = &rsa.PSSOptions{
+	SaltLength: rsa.PSSSaltLengthEqualsHash,
 }
 
 // Locator returns public key locator.
@@ -33,6 +41,9 @@ func (key *RSAKey) Public() ([]byte, error) {
 
 // SignatureType returns signature type generated from the key.
 func (key *RSAKey) SignatureType() uint64 {
+	if key.PSS {
+		return SignatureTypeSHA256WithRSAPSS
+	}
 	return SignatureTypeSHA256WithRSA
 }
 
@@ -42,6 +53,9 @@ func (key *RSAKey) Sign(v interface{}) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	if key.PSS {
+		return rsa.SignPSS(rand.Reader, key.PrivateKey, crypto.SHA256, digest, pssOptions)
+	}
 	return rsa.SignPKCS1v15(rand.Reader, key.PrivateKey, crypto.SHA256, digest)
 }
 
@@ -51,7 +65,11 @@ func (key *RSAKey) Verify(v interface{}, signature []byte) error {
 	if err != nil {
 		return err
 	}
-	err = rsa.VerifyPKCS1v15(&key.PrivateKey.PublicKey, crypto.SHA256, digest, signature)
+	if key.PSS {
+		err = rsa.VerifyPSS(&key.PrivateKey.PublicKey, crypto.SHA256, digest, signature, pssOptions)
+	} else {
+		err = rsa.VerifyPKCS1v15(&key.PrivateKey.PublicKey, crypto.SHA256, digest, signature)
+	}
 	if err != nil {
 		return ErrInvalidSignature
 	}
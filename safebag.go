@@ -0,0 +1,99 @@
+package ndn
+
+import (
+	"encoding/base64"
+	"io"
+
+	"github.com/go-ndn/tlv"
+)
+
+// SafeBag contains a certificate and its private key encrypted with a password.
+//
+// It is compatible with "ndnsec export" and "ndnsec import".
+//
+// See https://named-data.net/doc/ndn-cxx/current/specs/safe-bag.html.
+type SafeBag struct {
+	Certificate     Data   `tlv:"6"`
+	EncryptedKeyBag []byte `tlv:"129"`
+}
+
+// WriteTo implements tlv.WriteTo.
+func (bag *SafeBag) WriteTo(w tlv.Writer) error {
+	return w.Write(bag, 128)
+}
+
+// ReadFrom implements tlv.ReadFrom.
+func (bag *SafeBag) ReadFrom(r tlv.Reader) error {
+	return r.Read(bag, 128)
+}
+
+// ExportSafeBag encodes a key and its certificate in base64-encoded SafeBag.
+//
+// The private key is encrypted in PKCS#8 with password.
+//
+// See ImportSafeBag.
+func ExportSafeBag(key Key, cert *Data, password []byte, w io.Writer) error {
+	b, err := encryptPKCS8(key, password)
+	if err != nil {
+		return err
+	}
+	bag := &SafeBag{
+		Certificate:     *cert,
+		EncryptedKeyBag: b,
+	}
+	enc := base64.NewEncoder(base64.StdEncoding, w)
+	err = bag.WriteTo(tlv.NewWriter(enc))
+	if err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// ImportSafeBag decodes a key and its certificate from base64-encoded SafeBag.
+//
+// The key is named by the key name in the certificate.
+//
+// See ExportSafeBag.
+func ImportSafeBag(r io.Reader, password []byte) (key Key, cert *Data, err error) {
+	bag := new(SafeBag)
+	err = bag.ReadFrom(tlv.NewReader(base64.NewDecoder(base64.StdEncoding, r)))
+	if err != nil {
+		return
+	}
+	c, err := ParseCertificate(&bag.Certificate)
+	if err != nil {
+		return
+	}
+	key, err = decryptPKCS8(c.Name, bag.EncryptedKeyBag, password)
+	if err != nil {
+		return
+	}
+	cert = &bag.Certificate
+	return
+}
+
+// ExportSafeBag exports the named key with its certificate.
+//
+// See ExportSafeBag.
+func (kc *KeyChain) ExportSafeBag(name Name, password []byte, w io.Writer) error {
+	key, err := kc.TPM.Key(name)
+	if err != nil {
+		return err
+	}
+	cert, err := kc.Certificate(name)
+	if err != nil {
+		return err
+	}
+	return ExportSafeBag(key, cert, password, w)
+}
+
+// ImportSafeBag imports a key with its certificate.
+//
+// See ImportSafeBag.
+func (kc *KeyChain) ImportSafeBag(r io.Reader, password []byte) error {
+	key, cert, err := ImportSafeBag(r, password)
+	if err != nil {
+		return err
+	}
+	return kc.addKey(key, cert)
+}
@@ -0,0 +1,45 @@
+package ndn
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSafeBag(t *testing.T) {
+	for _, key := range []Key{rsaKey, ecdsaKey} {
+		cert, err := CertificateToData(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf := new(bytes.Buffer)
+		err = ExportSafeBag(key, cert, []byte("password"), buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b := buf.Bytes()
+
+		_, _, err = ImportSafeBag(bytes.NewReader(b), []byte("wrong"))
+		if err != ErrInvalidPassword {
+			t.Fatalf("expect %v, got %v", ErrInvalidPassword, err)
+		}
+
+		key2, cert2, err := ImportSafeBag(bytes.NewReader(b), []byte("password"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		pri1, err := key.Private()
+		if err != nil {
+			t.Fatal(err)
+		}
+		pri2, err := key2.Private()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(pri1, pri2) {
+			t.Fatalf("expect %v, got %v", pri1, pri2)
+		}
+		if cert.Name.Compare(cert2.Name) != 0 {
+			t.Fatalf("expect %v, got %v", cert.Name, cert2.Name)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package ndn
+
+import (
+	"time"
+
+	"github.com/go-ndn/lpm"
+)
+
+// defaultSegmentSize leaves room for the name and signature in a packet of maxPacketSize.
+const defaultSegmentSize = 8000
+
+// SegmentOptions controls Segment and SegmentMiddleware.
+type SegmentOptions struct {
+	// Size is the maximum content size of a segment.
+	// If it is 0, 8000 bytes is used.
+	Size int
+
+	// Version is the version of segmented content.
+	// If it is 0, the current Unix time in milliseconds is used.
+	Version uint64
+
+	// Key signs every segment.
+	// If it is nil, segments are signed with DigestSha256.
+	Key Signer
+
+	// Cache stores segments for SegmentMiddleware, so that later segments
+	// are answered without calling the handler again.
+	// If it is nil, NewCache(1024) is used.
+	Cache Cache
+
+	// OnError is called by SegmentMiddleware with the data sent by the handler
+	// and the error, if the data cannot be segmented. The data is not sent.
+	OnError func(*Data, error)
+}
+
+// Segment splits the content of d into segments named
+// /<name of d>/<version>/<segment>, with the MetaInfo of d and
+// FinalBlockID of the last segment.
+//
+// Empty content has one empty segment.
+func Segment(d *Data, opts SegmentOptions) ([]*Data, error) {
+	size := opts.Size
+	if size <= 0 {
+		size = defaultSegmentSize
+	}
+	version := opts.Version
+	if version == 0 {
+		version = uint64(time.Now().UnixMilli())
+	}
+	n := (len(d.Content) + size - 1) / size
+	if n == 0 {
+		n = 1
+	}
+	final := SegmentComponent(uint64(n - 1))
+
+	segments := make([]*Data, n)
+	for k := range segments {
+		components := make([]lpm.Component, 0, d.Name.Len()+2)
+		components = append(components, d.Name.Components...)
+		components = append(components, VersionComponent(version), SegmentComponent(uint64(k)))
+
+		start := k * size
+		end := start + size
+		if end > len(d.Content) {
+			end = len(d.Content)
+		}
+		seg := &Data{
+			Name:     Name{Components: components},
+			MetaInfo: d.MetaInfo,
+			Content:  d.Content[start:end],
+		}
+		seg.MetaInfo.FinalBlockID.Component = final
+		err := SignData(opts.Key, seg)
+		if err != nil {
+			return nil, err
+		}
+		segments[k] = seg
+	}
+	return segments, nil
+}
+
+// splitVersion returns the prefix of name before a trailing version, or
+// a trailing version and segment.
+// The version is 0 if name has neither.
+func splitVersion(name Name) (prefix Name, version uint64) {
+	l := name.Len()
+	if l >= 1 {
+		if v, ok := ParseVersion(name.Components[l-1]); ok {
+			return Name{Components: name.Components[:l-1]}, v
+		}
+	}
+	if l >= 2 {
+		if _, ok := ParseSegment(name.Components[l-1]); ok {
+			if v, ok := ParseVersion(name.Components[l-2]); ok {
+				return Name{Components: name.Components[:l-2]}, v
+			}
+		}
+	}
+	return name, 0
+}
+
+// SegmentMiddleware lets handlers send content of any size.
+//
+// Interests are answered from opts.Cache first.
+// Otherwise, the handler is called with the interest name without a trailing
+// version and segment, and data sent by the handler is segmented with Segment.
+// If the interest has a version, it is used instead of opts.Version,
+// so that the requested segment is created.
+// Only segments that match the interest are sent; the others are cached.
+func SegmentMiddleware(opts SegmentOptions) Middleware {
+	if opts.Cache == nil {
+		opts.Cache = NewCache(1024)
+	}
+	return func(next InterestHandler) InterestHandler {
+		return InterestHandlerFunc(func(w Sender, i *Interest) {
+			if d := opts.Cache.Get(i); d != nil {
+				w.SendData(d)
+				return
+			}
+			prefix, version := splitVersion(i.Name)
+			segmentOpts := opts
+			if version != 0 {
+				segmentOpts.Version = version
+			}
+			inner := *i
+			inner.Name = prefix
+			next.ServeInterest(&segmentSender{
+				Sender:   w,
+				interest: i,
+				opts:     segmentOpts,
+			}, &inner)
+		})
+	}
+}
+
+// segmentSender segments data sent by a handler of SegmentMiddleware.
+type segmentSender struct {
+	Sender
+	interest *Interest
+	opts     SegmentOptions
+}
+
+func (s *segmentSender) SendData(d *Data) {
+	segments, err := Segment(d, s.opts)
+	if err != nil {
+		if s.opts.OnError != nil {
+			s.opts.OnError(d, err)
+		}
+		return
+	}
+	for _, seg := range segments {
+		s.opts.Cache.Add(seg)
+	}
+	for _, seg := range segments {
+		if s.interest.Match(seg) {
+			s.Sender.SendData(seg)
+			return
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package ndn
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+)
+
+// SegmentReader reads content that is named like Segment, and fetches
+// segments on demand, so it can be used like a file.
+//
+// Recently used segments are cached.
+// It is safe for concurrent use, although Read and Seek share one offset.
+type SegmentReader struct {
+	ctx context.Context
+	f   *fetcher
+	// segmentSize is the content size of every segment except the last one.
+	segmentSize int64
+
+	mu sync.Mutex
+	// cache holds at most opts.Window segments, and order is from the least
+	// recently used.
+	cache  map[uint64][]byte
+	order  []uint64
+	offset int64
+}
+
+var (
+	_ io.ReadSeeker = (*SegmentReader)(nil)
+	_ io.ReaderAt   = (*SegmentReader)(nil)
+)
+
+// NewSegmentReader creates a reader of content under name, which is
+// discovered like Fetch.
+//
+// opts.Window is the number of cached segments, and Read also fetches up to
+// opts.Window segments ahead at the same time.
+// Every segment except the last one must have the same size, and the first
+// segment must have FinalBlockID.
+// Segments are fetched with ctx.
+func NewSegmentReader(ctx context.Context, w Sender, name Name, opts FetchOptions) (*SegmentReader, error) {
+	f := newFetcher(w, opts)
+	d, err := f.discover(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if d == nil || !bytes.Equal(d.Name.Components[d.Name.Len()-1], SegmentComponent(0)) {
+		d, err = f.fetch(ctx, 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+	_, err = f.learn(d)
+	if err != nil {
+		return nil, err
+	}
+	if !f.hasFinal || (f.final > 0 && len(d.Content) == 0) {
+		return nil, ErrInvalidSegment
+	}
+	r := &SegmentReader{
+		ctx:         ctx,
+		f:           f,
+		segmentSize: int64(len(d.Content)),
+		cache:       make(map[uint64][]byte),
+	}
+	if r.segmentSize == 0 {
+		// empty content has one empty segment.
+		r.segmentSize = 1
+	}
+	r.put(0, d.Content)
+	return r, nil
+}
+
+// Size returns the size of content, which fetches the last segment.
+func (r *SegmentReader) Size() (int64, error) {
+	b, err := r.segments(r.f.final, 1)
+	if err != nil {
+		return 0, err
+	}
+	return int64(r.f.final)*r.segmentSize + int64(len(b[0])), nil
+}
+
+// ReadAt implements io.ReaderAt.
+func (r *SegmentReader) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, ErrInvalidOffset
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	first := off / r.segmentSize
+	last := (off + int64(len(p)) - 1) / r.segmentSize
+	if uint64(last) > r.f.final {
+		last = int64(r.f.final)
+	}
+	if uint64(first) > r.f.final {
+		return 0, io.EOF
+	}
+	segments, err := r.segments(uint64(first), int(last-first+1))
+	if err != nil {
+		return 0, err
+	}
+	var n int
+	skip := off - first*r.segmentSize
+	for _, b := range segments {
+		if skip < int64(len(b)) {
+			n += copy(p[n:], b[skip:])
+		}
+		skip = 0
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Read implements io.Reader.
+func (r *SegmentReader) Read(p []byte) (int, error) {
+	r.mu.Lock()
+	off := r.offset
+	r.mu.Unlock()
+	// read ahead
+	if segment := uint64(off / r.segmentSize); segment <= r.f.final {
+		count := uint64(r.f.opts.Window)
+		if segment+count > r.f.final+1 {
+			count = r.f.final + 1 - segment
+		}
+		_, err := r.segments(segment, int(count))
+		if err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.ReadAt(p, off)
+	if n > 0 {
+		err = nil
+	}
+	r.mu.Lock()
+	r.offset = off + int64(n)
+	r.mu.Unlock()
+	return n, err
+}
+
+// Seek implements io.Seeker.
+// Seeking relative to the end fetches the last segment.
+func (r *SegmentReader) Seek(offset int64, whence int) (int64, error) {
+	var base int64
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		r.mu.Lock()
+		base = r.offset
+		r.mu.Unlock()
+	case io.SeekEnd:
+		size, err := r.Size()
+		if err != nil {
+			return 0, err
+		}
+		base = size
+	default:
+		return 0, ErrInvalidOffset
+	}
+	if base+offset < 0 {
+		return 0, ErrInvalidOffset
+	}
+	r.mu.Lock()
+	r.offset = base + offset
+	r.mu.Unlock()
+	return base + offset, nil
+}
+
+// segments returns count segments from first, and fetches missing ones,
+// up to opts.Window at the same time.
+func (r *SegmentReader) segments(first uint64, count int) ([][]byte, error) {
+	b := make([][]byte, count)
+	r.mu.Lock()
+	for k := range b {
+		b[k] = r.get(first + uint64(k))
+	}
+	r.mu.Unlock()
+
+	errs := make([]error, count)
+	sem := make(chan struct{}, r.f.opts.Window)
+	var wg sync.WaitGroup
+	for k := range b {
+		if b[k] != nil {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(k int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			d, err := r.f.fetch(r.ctx, first+uint64(k))
+			if err != nil {
+				errs[k] = err
+				return
+			}
+			b[k] = d.Content
+		}(k)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	r.mu.Lock()
+	for k := range b {
+		r.put(first+uint64(k), b[k])
+	}
+	r.mu.Unlock()
+	return b, nil
+}
+
+// get returns a cached segment, or nil if it is not cached.
+func (r *SegmentReader) get(segment uint64) []byte {
+	b, ok := r.cache[segment]
+	if !ok {
+		return nil
+	}
+	r.touch(segment)
+	if b == nil {
+		// an empty segment is not nil
+		return []byte{}
+	}
+	return b
+}
+
+// put caches a segment, and evicts the least recently used one if the cache is full.
+func (r *SegmentReader) put(segment uint64, b []byte) {
+	if _, ok := r.cache[segment]; ok {
+		r.touch(segment)
+		return
+	}
+	r.cache[segment] = b
+	r.order = append(r.order, segment)
+	if len(r.order) > r.f.opts.Window {
+		delete(r.cache, r.order[0])
+		r.order = r.order[1:]
+	}
+}
+
+// touch marks segment as the most recently used.
+func (r *SegmentReader) touch(segment uint64) {
+	for k, s := range r.order {
+		if s == segment {
+			copy(r.order[k:], r.order[k+1:])
+			r.order[len(r.order)-1] = segment
+			return
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package ndn
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSegment(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 25)
+	d := &Data{Name: NewName("/A"), Content: content}
+	d.MetaInfo.FreshnessPeriod = 1000
+	segments, err := Segment(d, SegmentOptions{Size: 100, Version: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(segments) != 3 {
+		t.Fatalf("expect %v, got %v", 3, len(segments))
+	}
+	var got []byte
+	for k, seg := range segments {
+		want := Name{Components: append(NewName("/A").Components, VersionComponent(7), SegmentComponent(uint64(k)))}
+		if seg.Name.Compare(want) != 0 {
+			t.Fatalf("expect %v, got %v", want, seg.Name)
+		}
+		if !bytes.Equal(seg.MetaInfo.FinalBlockID.Component, SegmentComponent(2)) {
+			t.Fatalf("expect final block %v, got %v", SegmentComponent(2), seg.MetaInfo.FinalBlockID.Component)
+		}
+		if seg.MetaInfo.FreshnessPeriod != 1000 {
+			t.Fatalf("expect %v, got %v", 1000, seg.MetaInfo.FreshnessPeriod)
+		}
+		err := VerifyData(nil, seg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, seg.Content...)
+	}
+	if !bytes.Equal(got, content) {
+		t.Fatalf("expect %q, got %q", content, got)
+	}
+
+	segments, err = Segment(&Data{Name: NewName("/A")}, SegmentOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(segments) != 1 || len(segments[0].Content) != 0 {
+		t.Fatalf("expect one empty segment, got %v", segments)
+	}
+}
+
+func TestSegmentMiddleware(t *testing.T) {
+	content := bytes.Repeat([]byte("named-data networking "), 100)
+	var calls atomic.Int32
+	mux := NewMux()
+	mux.Use(SegmentMiddleware(SegmentOptions{Size: 500}))
+	mux.HandleFunc("/file", func(w Sender, i *Interest) {
+		calls.Add(1)
+		d := &Data{Name: i.Name, Content: content}
+		d.MetaInfo.FreshnessPeriod = 1000
+		w.SendData(d)
+	})
+
+	c1, c2 := net.Pipe()
+	producer := NewFace(c2, WithInterestHandler(mux))
+	defer producer.Close()
+	consumer := NewFace(c1)
+	defer consumer.Close()
+
+	got, err := FetchDataset(consumer, NewName("/file/readme"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Fatalf("expect %q, got %q", content, got)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expect %v, got %v", 1, calls.Load())
+	}
+}
+
+func TestSegmentMiddlewareError(t *testing.T) {
+	failed := make(chan error, 1)
+	h := SegmentMiddleware(SegmentOptions{
+		Key: failingKey{Key: rsaKey},
+		OnError: func(d *Data, err error) {
+			failed <- err
+		},
+	})(InterestHandlerFunc(func(w Sender, i *Interest) {
+		w.SendData(&Data{Name: i.Name, Content: []byte("content")})
+	}))
+
+	c1, c2 := net.Pipe()
+	producer := NewFace(c2, WithInterestHandler(h))
+	defer producer.Close()
+	consumer := NewFace(c1)
+	defer consumer.Close()
+
+	consumer.SendInterest(&Interest{Name: NewName("/file")})
+	if err := <-failed; err != errFailingKey {
+		t.Fatalf("expect %v, got %v", errFailingKey, err)
+	}
+	if n := producer.(StatsFace).Stats().OutData; n != 0 {
+		t.Fatalf("expect 0, got %v", n)
+	}
+}
+
+// segmentServer serves segments after a delay, and drops the first
+// interests of some segments.
+type segmentServer struct {
+	segments []*Data
+
+	sync.Mutex
+	loss        map[uint64]int
+	inflight    int
+	maxInflight int
+}
+
+func (s *segmentServer) SendInterest(i *Interest) <-chan *Data {
+	ch := make(chan *Data, 1)
+	s.Lock()
+	s.inflight++
+	if s.inflight > s.maxInflight {
+		s.maxInflight = s.inflight
+	}
+	var match *Data
+	for n, seg := range s.segments {
+		if i.Match(seg) {
+			if s.loss[uint64(n)] > 0 {
+				s.loss[uint64(n)]--
+			} else {
+				match = seg
+			}
+			break
+		}
+	}
+	s.Unlock()
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		s.Lock()
+		s.inflight--
+		s.Unlock()
+		if match != nil {
+			ch <- match
+		}
+		close(ch)
+	}()
+	return ch
+}
+
+func (s *segmentServer) SendData(*Data) {}
+
+func TestFetch(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 100)
+	d := &Data{Name: NewName("/A"), Content: content}
+	d.MetaInfo.FreshnessPeriod = 1000
+	segments, err := Segment(d, SegmentOptions{Size: 10, Version: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name string
+		loss map[uint64]int
+		err  error
+	}{
+		{name: "/A", loss: map[uint64]int{0: 1, 7: 2, 99: 3}},
+		{name: "/A", loss: map[uint64]int{0: 4}, err: ErrTimeout},
+		{name: "/A", loss: map[uint64]int{50: 4}, err: ErrTimeout},
+	} {
+		s := &segmentServer{segments: segments, loss: test.loss}
+		got, err := Fetch(context.Background(), s, NewName(test.name), FetchOptions{
+			Window:   8,
+			LifeTime: 10,
+		})
+		if err != test.err {
+			t.Fatalf("expect %v, got %v", test.err, err)
+		}
+		if err != nil {
+			continue
+		}
+		if !bytes.Equal(got, content) {
+			t.Fatalf("expect %q, got %q", content, got)
+		}
+		if s.maxInflight > 8 {
+			t.Fatalf("expect at most %v interests, got %v", 8, s.maxInflight)
+		}
+	}
+
+	// a versioned name is not discovered with a fresh interest
+	stale := &Data{Name: NewName("/A"), Content: content}
+	segments, err = Segment(stale, SegmentOptions{Size: 100, Version: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &segmentServer{segments: segments}
+	got, err := Fetch(context.Background(), s, Name{Components: segments[0].Name.Components[:2]}, FetchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Fatalf("expect %q, got %q", content, got)
+	}
+	if _, err := Fetch(context.Background(), s, NewName("/A"), FetchOptions{LifeTime: 10}); err != ErrTimeout {
+		t.Fatalf("expect %v, got %v", ErrTimeout, err)
+	}
+}
+
+func TestSegmentReader(t *testing.T) {
+	content := make([]byte, 1000)
+	for k := range content {
+		content[k] = byte(k)
+	}
+	d := &Data{Name: NewName("/A"), Content: content}
+	d.MetaInfo.FreshnessPeriod = 1000
+	segments, err := Segment(d, SegmentOptions{Size: 64})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &segmentServer{segments: segments}
+	r, err := NewSegmentReader(context.Background(), s, NewName("/A"), FetchOptions{Window: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Fatalf("expect %v, got %v", content, got)
+	}
+	if s.maxInflight > 4 {
+		t.Fatalf("expect at most %v interests, got %v", 4, s.maxInflight)
+	}
+
+	size, err := r.Size()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != int64(len(content)) {
+		t.Fatalf("expect %v, got %v", len(content), size)
+	}
+	for _, test := range []struct {
+		off, n int64
+	}{
+		{0, 10},
+		{60, 10},
+		{100, 500},
+		{990, 10},
+	} {
+		b := make([]byte, test.n)
+		n, err := r.ReadAt(b, test.off)
+		if err != nil || int64(n) != test.n {
+			t.Fatalf("ReadAt(%d) == %d, got %d, %v", test.off, test.n, n, err)
+		}
+		if !bytes.Equal(b, content[test.off:test.off+test.n]) {
+			t.Fatalf("ReadAt(%d): expect %v, got %v", test.off, content[test.off:test.off+test.n], b)
+		}
+	}
+	b := make([]byte, 20)
+	n, err := r.ReadAt(b, 990)
+	if n != 10 || err != io.EOF {
+		t.Fatalf("expect %v, got %v, %v", io.EOF, n, err)
+	}
+
+	off, err := r.Seek(-10, io.SeekEnd)
+	if err != nil || off != 990 {
+		t.Fatalf("expect %v, got %v, %v", 990, off, err)
+	}
+	got, err = io.ReadAll(r)
+	if err != nil || !bytes.Equal(got, content[990:]) {
+		t.Fatalf("expect %v, got %v, %v", content[990:], got, err)
+	}
+	if _, err := r.Seek(-1, io.SeekStart); err != ErrInvalidOffset {
+		t.Fatalf("expect %v, got %v", ErrInvalidOffset, err)
+	}
+}
@@ -0,0 +1,215 @@
+package ndn
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-ndn/lpm"
+)
+
+// SequenceOptions controls FollowSequence.
+type SequenceOptions struct {
+	// Start is the first sequence number.
+	// If it is 0, the latest sequence number is discovered, and following
+	// starts after it; if there is none, it starts from 0.
+	Start uint64
+
+	// Window is the number of upcoming sequence numbers requested at the same time.
+	// If it is 0, 4 is used.
+	Window int
+
+	// Retries is the number of times a missing sequence number is requested
+	// again after a later one is received, before it is skipped as a gap.
+	// If it is 0, 3 is used.
+	Retries int
+
+	// LifeTime is the lifetime of interests in milliseconds.
+	// If it is 0, 4 seconds is used.
+	LifeTime uint64
+}
+
+// FollowSequence follows data named /<prefix>/<sequence number> with
+// SequenceComponent, such as a telemetry or chat feed, and calls fn with every
+// data in the order it is received, until ctx is done.
+//
+// Interests for upcoming sequence numbers are sent again until they are produced.
+// Sequence numbers that are still missing after later ones are received are skipped.
+// If every pending interest keeps timing out, the latest sequence number is
+// discovered again, so following continues after the producer restarts.
+//
+// It returns ctx.Err().
+func FollowSequence(ctx context.Context, w Sender, prefix Name, opts SequenceOptions, fn func(seq uint64, d *Data)) error {
+	if opts.Window <= 0 {
+		opts.Window = 4
+	}
+	if opts.Retries <= 0 {
+		opts.Retries = 3
+	}
+	if opts.LifeTime == 0 {
+		opts.LifeTime = 4000
+	}
+	s := &sequenceFollower{
+		w:       w,
+		prefix:  prefix,
+		opts:    opts,
+		pending: make(map[uint64]*pendingSequence),
+		results: make(chan sequenceResult),
+	}
+	s.next = opts.Start
+	if s.next == 0 {
+		if latest, ok := s.discover(ctx); ok {
+			s.next = latest + 1
+		}
+	}
+
+	// idle counts timeouts since the last data.
+	var idle int
+	for {
+		for len(s.pending) < opts.Window {
+			s.request(ctx, s.next, 0)
+			s.next++
+		}
+		var r sequenceResult
+		select {
+		case r = <-s.results:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		p, ok := s.pending[r.seq]
+		if !ok || p.id != r.id {
+			// canceled by discovery
+			continue
+		}
+		delete(s.pending, r.seq)
+		if r.d != nil {
+			idle = 0
+			if !s.received || r.seq > s.highest {
+				s.highest = r.seq
+				s.received = true
+			}
+			fn(r.seq, r.d)
+			continue
+		}
+
+		if s.received && r.seq < s.highest {
+			if p.retries >= opts.Retries {
+				// gap
+				continue
+			}
+			s.request(ctx, r.seq, p.retries+1)
+			continue
+		}
+		// not yet produced
+		s.request(ctx, r.seq, 0)
+		idle++
+		if idle < 3*opts.Window {
+			continue
+		}
+		idle = 0
+		latest, ok := s.discover(ctx)
+		if !ok || (latest+1 >= s.lowest() && latest < s.next) {
+			continue
+		}
+		// the producer restarts, or following falls behind.
+		for _, p := range s.pending {
+			p.cancel()
+		}
+		s.pending = make(map[uint64]*pendingSequence)
+		s.next = latest + 1
+		s.received = false
+	}
+}
+
+// sequenceFollower is the state of FollowSequence.
+type sequenceFollower struct {
+	w       Sender
+	prefix  Name
+	opts    SequenceOptions
+	pending map[uint64]*pendingSequence
+	results chan sequenceResult
+	// next is the next sequence number to request.
+	next uint64
+	// highest is the highest received sequence number since the last discovery.
+	highest  uint64
+	received bool
+	// id identifies requests, so that results of canceled ones are ignored.
+	id uint64
+}
+
+type pendingSequence struct {
+	id      uint64
+	retries int
+	cancel  context.CancelFunc
+}
+
+type sequenceResult struct {
+	seq uint64
+	id  uint64
+	d   *Data
+}
+
+func (s *sequenceFollower) name(seq uint64) Name {
+	components := make([]lpm.Component, 0, s.prefix.Len()+1)
+	components = append(components, s.prefix.Components...)
+	return Name{Components: append(components, SequenceComponent(seq))}
+}
+
+// request sends an interest for seq in the background.
+func (s *sequenceFollower) request(ctx context.Context, seq uint64, retries int) {
+	s.id++
+	ctx, cancel := context.WithCancel(ctx)
+	p := &pendingSequence{
+		id:      s.id,
+		retries: retries,
+		cancel:  cancel,
+	}
+	s.pending[seq] = p
+	name := s.name(seq)
+	go func() {
+		defer cancel()
+		r := ExpressInterest(ctx, s.w, &Interest{
+			Name:     name,
+			LifeTime: s.opts.LifeTime,
+		})
+		d := r.Data()
+		if _, nacked := r.Nack(); nacked {
+			// wait before the interest is sent again.
+			select {
+			case <-time.After(time.Duration(s.opts.LifeTime) * time.Millisecond):
+			case <-ctx.Done():
+			}
+		}
+		select {
+		case s.results <- sequenceResult{seq: seq, id: p.id, d: d}:
+		case <-ctx.Done():
+		}
+	}()
+}
+
+// lowest returns the lowest pending sequence number.
+func (s *sequenceFollower) lowest() uint64 {
+	lowest := s.next
+	for seq := range s.pending {
+		if seq < lowest {
+			lowest = seq
+		}
+	}
+	return lowest
+}
+
+// discover returns the latest sequence number with a fresh interest for
+// the rightmost child of prefix.
+func (s *sequenceFollower) discover(ctx context.Context) (uint64, bool) {
+	d, err := SendInterestContext(ctx, s.w, &Interest{
+		Name: s.prefix,
+		Selectors: Selectors{
+			ChildSelector: 1,
+			MustBeFresh:   true,
+		},
+		LifeTime: s.opts.LifeTime,
+	})
+	if err != nil || d.Name.Len() != s.prefix.Len()+1 {
+		return 0, false
+	}
+	return ParseSequence(d.Name.Components[s.prefix.Len()])
+}
@@ -0,0 +1,136 @@
+package ndn
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// feedServer serves data named with SequenceComponent as it is published.
+type feedServer struct {
+	prefix Name
+
+	sync.Mutex
+	published map[uint64]*Data
+	latest    *Data
+	update    chan struct{}
+}
+
+func newFeedServer(prefix Name) *feedServer {
+	return &feedServer{
+		prefix:    prefix,
+		published: make(map[uint64]*Data),
+		update:    make(chan struct{}),
+	}
+}
+
+func (s *feedServer) publish(seq uint64) {
+	d := &Data{Name: Name{Components: append(s.prefix.Components[:s.prefix.Len():s.prefix.Len()], SequenceComponent(seq))}}
+	s.Lock()
+	s.published[seq] = d
+	s.latest = d
+	close(s.update)
+	s.update = make(chan struct{})
+	s.Unlock()
+}
+
+// restart forgets all published data.
+func (s *feedServer) restart() {
+	s.Lock()
+	s.published = make(map[uint64]*Data)
+	s.latest = nil
+	s.Unlock()
+}
+
+func (s *feedServer) SendInterest(i *Interest) <-chan *Data {
+	ch := make(chan *Data, 1)
+	go func() {
+		defer close(ch)
+		timeout := time.After(time.Duration(i.LifeTime) * time.Millisecond)
+		for {
+			s.Lock()
+			var match *Data
+			if i.Selectors.ChildSelector == 1 {
+				match = s.latest
+			} else {
+				for _, d := range s.published {
+					if i.Match(d) {
+						match = d
+					}
+				}
+			}
+			update := s.update
+			s.Unlock()
+			if match != nil {
+				ch <- match
+				return
+			}
+			select {
+			case <-update:
+			case <-timeout:
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+func (s *feedServer) SendData(*Data) {}
+
+func TestFollowSequence(t *testing.T) {
+	prefix := NewName("/feed")
+	s := newFeedServer(prefix)
+	for seq := uint64(0); seq < 3; seq++ {
+		s.publish(seq)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	received := make(chan uint64, 16)
+	done := make(chan error, 1)
+	go func() {
+		done <- FollowSequence(ctx, s, prefix, SequenceOptions{LifeTime: 20}, func(seq uint64, d *Data) {
+			if got, ok := ParseSequence(d.Name.Components[1]); !ok || got != seq {
+				t.Errorf("expect %v, got %v", seq, got)
+			}
+			received <- seq
+		})
+	}()
+	expect := func(want uint64) {
+		select {
+		case got := <-received:
+			if got != want {
+				t.Fatalf("expect %v, got %v", want, got)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expect %v, got timeout", want)
+		}
+	}
+
+	// following starts after the latest sequence number
+	time.Sleep(50 * time.Millisecond)
+	s.publish(3)
+	expect(3)
+	s.publish(4)
+	expect(4)
+	// 5 is skipped
+	s.publish(6)
+	expect(6)
+	time.Sleep(200 * time.Millisecond)
+	s.publish(7)
+	expect(7)
+
+	// the producer restarts from 0
+	s.restart()
+	s.publish(0)
+	s.publish(1)
+	time.Sleep(time.Second)
+	s.publish(2)
+	expect(2)
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("expect %v, got %v", context.Canceled, err)
+	}
+}
@@ -0,0 +1,51 @@
+package ndn
+
+import (
+	"crypto/rand"
+	"sync"
+	"time"
+)
+
+// SignatureOptions populates optional SignatureInfo fields before signing.
+//
+// Time is the current time in milliseconds, and it strictly increases
+// for the same SignatureOptions. SeqNum starts from 1 and increases by 1
+// for every populated SignatureInfo, because zero is not encoded.
+// Nonce is 8 random bytes.
+type SignatureOptions struct {
+	Nonce  bool
+	Time   bool
+	SeqNum bool
+
+	lastTime uint64
+	seqNum   uint64
+	sync.Mutex
+}
+
+// Populate sets selected fields in info.
+//
+// It should be invoked before SignData.
+func (opts *SignatureOptions) Populate(info *SignatureInfo) error {
+	if opts.Nonce {
+		info.Nonce = make([]byte, 8)
+		_, err := rand.Read(info.Nonce)
+		if err != nil {
+			return err
+		}
+	}
+	opts.Lock()
+	defer opts.Unlock()
+	if opts.Time {
+		now := uint64(time.Now().UnixNano() / int64(time.Millisecond))
+		if now <= opts.lastTime {
+			now = opts.lastTime + 1
+		}
+		opts.lastTime = now
+		info.Time = now
+	}
+	if opts.SeqNum {
+		opts.seqNum++
+		info.SeqNum = opts.seqNum
+	}
+	return nil
+}
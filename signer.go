@@ -0,0 +1,109 @@
+package ndn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+
+	"github.com/go-ndn/tlv"
+)
+
+// SignerKey implements Key with crypto.Signer.
+//
+// The private key never leaves the signer, so it can be held by
+// HSM, PKCS#11 token, TPM2 device or cloud KMS.
+// Only RSA, ECDSA and Ed25519 signers are supported.
+type SignerKey struct {
+	Name
+	Signer crypto.Signer
+}
+
+// Locator returns public key locator.
+func (key *SignerKey) Locator() Name {
+	return key.Name
+}
+
+// Private returns ErrNotSupported because the private key cannot be exported.
+func (key *SignerKey) Private() ([]byte, error) {
+	return nil, ErrNotSupported
+}
+
+// Public encodes public key.
+func (key *SignerKey) Public() ([]byte, error) {
+	return x509.MarshalPKIXPublicKey(key.Signer.Public())
+}
+
+// SignatureType returns signature type generated from the key.
+func (key *SignerKey) SignatureType() uint64 {
+	switch key.Signer.Public().(type) {
+	case *rsa.PublicKey:
+		return SignatureTypeSHA256WithRSA
+	case *ecdsa.PublicKey:
+		return SignatureTypeSHA256WithECDSA
+	case ed25519.PublicKey:
+		return SignatureTypeEd25519
+	default:
+		return SignatureTypeDigestSHA256
+	}
+}
+
+// Sign creates signature.
+func (key *SignerKey) Sign(v interface{}) ([]byte, error) {
+	switch key.Signer.Public().(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey:
+	case ed25519.PublicKey:
+		msg, err := signedPortion(v)
+		if err != nil {
+			return nil, err
+		}
+		return key.Signer.Sign(rand.Reader, msg, crypto.Hash(0))
+	default:
+		return nil, ErrNotSupported
+	}
+	digest, err := tlv.Hash(sha256.New, v)
+	if err != nil {
+		return nil, err
+	}
+	// ecdsa signature from crypto.Signer is already asn.1-encoded.
+	return key.Signer.Sign(rand.Reader, digest, crypto.SHA256)
+}
+
+// Verify checks signature.
+func (key *SignerKey) Verify(v interface{}, signature []byte) error {
+	pub, err := publicKey(key.Name, key.Signer.Public())
+	if err != nil {
+		return err
+	}
+	return pub.Verify(v, signature)
+}
+
+// publicKey creates a verification-only key from a public key.
+func publicKey(name Name, pub crypto.PublicKey) (Key, error) {
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		return &RSAKey{
+			Name: name,
+			PrivateKey: &rsa.PrivateKey{
+				PublicKey: *pub,
+			},
+		}, nil
+	case *ecdsa.PublicKey:
+		return &ECDSAKey{
+			Name: name,
+			PrivateKey: &ecdsa.PrivateKey{
+				PublicKey: *pub,
+			},
+		}, nil
+	case ed25519.PublicKey:
+		return &Ed25519Key{
+			Name:      name,
+			PublicKey: pub,
+		}, nil
+	default:
+		return nil, ErrNotSupported
+	}
+}
@@ -0,0 +1,128 @@
+PRAGMA foreign_keys=OFF;
+BEGIN TRANSACTION;
+CREATE TABLE tpmInfo(
+    tpm_locator           BLOB
+  );
+INSERT INTO tpmInfo VALUES('tpm-file:');
+CREATE TABLE identities(
+    id                    INTEGER PRIMARY KEY,
+    identity              BLOB NOT NULL,
+    is_default            INTEGER DEFAULT 0
+  );
+INSERT INTO identities VALUES(1,X'071308036e646e080567756573740805616c696365',1);
+CREATE TABLE keys(
+    id                    INTEGER PRIMARY KEY,
+    identity_id           INTEGER NOT NULL,
+    key_name              BLOB NOT NULL,
+    key_bits              BLOB NOT NULL,
+    is_default            INTEGER DEFAULT 0,
+    FOREIGN KEY(identity_id)
+      REFERENCES identities(id)
+      ON DELETE CASCADE
+      ON UPDATE CASCADE
+  );
+INSERT INTO keys VALUES(1,1,X'072708036e646e080567756573740805616c69636508034b4559080d31343334353038393432303737',X'30820122300d06092a864886f70d01010105000382010f003082010a0282010100bc5bb5ec22a9fd1671cae5cc859ffe19a5f8fb6c2c670bc5485e578fede748a129db6894973602db39ae9cdc4a2352ee2b5b77c32fbe90f6eac637eb91f193e25c35ccbb2565fe0f9a082e6e09eaebd327b149bcb4b94687d21bdcc1337a1ade01dc6b36ab4171fa33a272d1a5195b4e96ceb5e038f0468513aa6b7187139f26d96b359e9af327c8ddd23e3ce725a6198f976ae7cb78b651c3ff6297cf6dff9f155c708022c85320d768779418b7b23fa906905b8ee795919889788a0abb8f99fbffe4d0decbfd013ff87c690e4970ce32dba43052156cd0140f8563c7615cf9ab7f2f80a15c02e476e92fe698ecae5c5c22b1f175bf1aaa4ee6c51be1607c930203010001',1);
+CREATE TABLE certificates(
+    id                    INTEGER PRIMARY KEY,
+    key_id                INTEGER NOT NULL,
+    certificate_name      BLOB NOT NULL,
+    certificate_data      BLOB NOT NULL,
+    is_default            INTEGER DEFAULT 0,
+    FOREIGN KEY(key_id)
+      REFERENCES keys(id)
+      ON DELETE CASCADE
+      ON UPDATE CASCADE
+  );
+INSERT INTO certificates VALUES(1,1,X'072b08036e646e080567756573740805616c696365080d3134333435303839343230373708034b455908020000',X'06fd029a072b08036e646e080567756573740805616c696365080d3134333435303839343230373708034b455908020000140918010219040036ee8015fd012630820122300d06092a864886f70d01010105000382010f003082010a0282010100bc5bb5ec22a9fd1671cae5cc859ffe19a5f8fb6c2c670bc5485e578fede748a129db6894973602db39ae9cdc4a2352ee2b5b77c32fbe90f6eac637eb91f193e25c35ccbb2565fe0f9a082e6e09eaebd327b149bcb4b94687d21bdcc1337a1ade01dc6b36ab4171fa33a272d1a5195b4e96ceb5e038f0468513aa6b7187139f26d96b359e9af327c8ddd23e3ce725a6198f976ae7cb78b651c3ff6297cf6dff9f155c708022c85320d768779418b7b23fa906905b8ee795919889788a0abb8f99fbffe4d0decbfd013ff87c690e4970ce32dba43052156cd0140f8563c7615cf9ab7f2f80a15c02e476e92fe698ecae5c5c22b1f175bf1aaa4ee6c51be1607c93020301000116321b01011c2d072b08036e646e080567756573740805616c696365080d3134333435303839343230373708034b45590802000017fd010002037891a3764ef95ef83eafeac7fcc8669bed4f9787017247cd9176da3aae4dc9cb129f71b5959444db362c46d2dac67512148607eeaea4b4a02c6616e1fa097017cd340f7b29c0a7cf90f9c6c91d90da01af74a9e53a5870d8cf2871e8441fe91906eb9b3feec0cabd84c9d57805a1d45a30f4984c8ad2514bd67b53410fd721e3c51d90354e12ed2228bde91a1e5a25c9ae8dbef2e81c4d997e54bf70d8ba15e280a5ab51b5fa1a77470cc24d538bd239f31b8f8394e532a41883dda0fb02e4a115ae58704ce5ef571a9d06040f02928dfe33118b77ad710ba7c6ddb4b13f588e24eb789dbcd49ba3935383ec74a24a10ff5a4a5603bb888881559ba64d8b',1);
+CREATE TRIGGER identity_default_before_insert_trigger
+  BEFORE INSERT ON identities
+  FOR EACH ROW
+  WHEN NEW.is_default=1
+  BEGIN
+    UPDATE identities SET is_default=0;
+  END;
+CREATE TRIGGER identity_default_after_insert_trigger
+  AFTER INSERT ON identities
+  FOR EACH ROW
+  WHEN NOT EXISTS
+    (SELECT id
+       FROM identities
+       WHERE is_default=1)
+  BEGIN
+    UPDATE identities
+      SET is_default=1
+      WHERE identity=NEW.identity;
+  END;
+CREATE TRIGGER identity_default_update_trigger
+  BEFORE UPDATE ON identities
+  FOR EACH ROW
+  WHEN NEW.is_default=1 AND OLD.is_default=0
+  BEGIN
+    UPDATE identities SET is_default=0;
+  END;
+CREATE TRIGGER key_default_before_insert_trigger
+  BEFORE INSERT ON keys
+  FOR EACH ROW
+  WHEN NEW.is_default=1
+  BEGIN
+    UPDATE keys
+      SET is_default=0
+      WHERE identity_id=NEW.identity_id;
+  END;
+CREATE TRIGGER key_default_after_insert_trigger
+  AFTER INSERT ON keys
+  FOR EACH ROW
+  WHEN NOT EXISTS
+    (SELECT id
+       FROM keys
+       WHERE is_default=1
+         AND identity_id=NEW.identity_id)
+  BEGIN
+    UPDATE keys
+      SET is_default=1
+      WHERE key_name=NEW.key_name;
+  END;
+CREATE TRIGGER key_default_update_trigger
+  BEFORE UPDATE ON keys
+  FOR EACH ROW
+  WHEN NEW.is_default=1 AND OLD.is_default=0
+  BEGIN
+    UPDATE keys
+      SET is_default=0
+      WHERE identity_id=NEW.identity_id;
+  END;
+CREATE TRIGGER cert_default_before_insert_trigger
+  BEFORE INSERT ON certificates
+  FOR EACH ROW
+  WHEN NEW.is_default=1
+  BEGIN
+    UPDATE certificates
+      SET is_default=0
+      WHERE key_id=NEW.key_id;
+  END;
+CREATE TRIGGER cert_default_after_insert_trigger
+  AFTER INSERT ON certificates
+  FOR EACH ROW
+  WHEN NOT EXISTS
+    (SELECT id
+       FROM certificates
+       WHERE is_default=1
+         AND key_id=NEW.key_id)
+  BEGIN
+    UPDATE certificates
+      SET is_default=1
+      WHERE certificate_name=NEW.certificate_name;
+  END;
+CREATE TRIGGER cert_default_update_trigger
+  BEFORE UPDATE ON certificates
+  FOR EACH ROW
+  WHEN NEW.is_default=1 AND OLD.is_default=0
+  BEGIN
+    UPDATE certificates
+      SET is_default=0
+      WHERE key_id=NEW.key_id;
+  END;
+CREATE UNIQUE INDEX identityIndex ON identities(identity);
+CREATE UNIQUE INDEX keyIndex ON keys(key_name);
+CREATE UNIQUE INDEX certIndex ON certificates(certificate_name);
+COMMIT;
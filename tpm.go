@@ -0,0 +1,50 @@
+package ndn
+
+import "sync"
+
+// TPM stores private keys.
+type TPM interface {
+	AddKey(key Key) error
+	Key(name Name) (Key, error)
+	DeleteKey(name Name) error
+}
+
+// NewMemoryTPM creates a new thread-safe in-memory TPM.
+func NewMemoryTPM() TPM {
+	return &memoryTPM{
+		key: make(map[string]Key),
+	}
+}
+
+type memoryTPM struct {
+	key map[string]Key
+	sync.Mutex
+}
+
+func (tpm *memoryTPM) AddKey(key Key) error {
+	tpm.Lock()
+	defer tpm.Unlock()
+	tpm.key[key.Locator().String()] = key
+	return nil
+}
+
+func (tpm *memoryTPM) Key(name Name) (Key, error) {
+	tpm.Lock()
+	defer tpm.Unlock()
+	key, ok := tpm.key[name.String()]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return key, nil
+}
+
+func (tpm *memoryTPM) DeleteKey(name Name) error {
+	tpm.Lock()
+	defer tpm.Unlock()
+	n := name.String()
+	if _, ok := tpm.key[n]; !ok {
+		return ErrKeyNotFound
+	}
+	delete(tpm.key, n)
+	return nil
+}
@@ -0,0 +1,169 @@
+package ndn
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// NewFileTPM creates a TPM that stores private keys in dir.
+//
+// The layout follows ndnsec file TPM (~/.ndn/ndnsec-key-file):
+// each key is stored in a file named by the hex-encoded SHA256 of its wire-encoded name,
+// with extension ".privkey". RSA and ECDSA keys are saved as base64-encoded DER,
+// so they can be shared with ndnsec. Other keys, including RSA-PSS keys and
+// ECDSA keys with SHA-384 or SHA-512, are saved in PEM encoding, which also
+// records the signature type.
+func NewFileTPM(dir string) TPM {
+	return &fileTPM{dir: dir}
+}
+
+type fileTPM struct {
+	dir string
+	sync.Mutex
+}
+
+// nameHash returns the hex-encoded SHA256 of a wire-encoded name.
+func nameHash(name Name) (string, error) {
+	b, err := marshalName(name)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (tpm *fileTPM) path(name Name) (string, error) {
+	h, err := nameHash(name)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(tpm.dir, h+".privkey"), nil
+}
+
+func (tpm *fileTPM) AddKey(key Key) error {
+	file, err := tpm.path(key.Locator())
+	if err != nil {
+		return err
+	}
+	buf := new(bytes.Buffer)
+	switch key.SignatureType() {
+	case SignatureTypeSHA256WithRSA, SignatureTypeSHA256WithECDSA:
+		der, err := key.Private()
+		if err != nil {
+			return err
+		}
+		enc := base64.StdEncoding.EncodeToString(der)
+		for len(enc) > 64 {
+			buf.WriteString(enc[:64])
+			buf.WriteByte('\n')
+			enc = enc[64:]
+		}
+		buf.WriteString(enc)
+		buf.WriteByte('\n')
+	default:
+		err = EncodePrivateKey(key, buf)
+		if err != nil {
+			return err
+		}
+	}
+
+	tpm.Lock()
+	defer tpm.Unlock()
+	err = os.MkdirAll(tpm.dir, 0700)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, buf.Bytes(), 0600)
+}
+
+func (tpm *fileTPM) Key(name Name) (Key, error) {
+	file, err := tpm.path(name)
+	if err != nil {
+		return nil, err
+	}
+	tpm.Lock()
+	b, err := ioutil.ReadFile(file)
+	tpm.Unlock()
+	if os.IsNotExist(err) {
+		return nil, ErrKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(b, []byte("-----BEGIN")) {
+		key, err := DecodePrivateKey(bytes.NewReader(b))
+		if err != nil {
+			return nil, err
+		}
+		return key, nil
+	}
+	der, err := base64.StdEncoding.DecodeString(string(bytes.Join(bytes.Fields(b), nil)))
+	if err != nil {
+		return nil, err
+	}
+	return parsePrivateKey(name, der)
+}
+
+// parsePrivateKey parses a DER-encoded private key in PKCS#1, SEC1 or PKCS#8.
+func parsePrivateKey(name Name, der []byte) (Key, error) {
+	if pri, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return &RSAKey{
+			Name:       name,
+			PrivateKey: pri,
+		}, nil
+	}
+	if pri, err := x509.ParseECPrivateKey(der); err == nil {
+		return &ECDSAKey{
+			Name:       name,
+			PrivateKey: pri,
+		}, nil
+	}
+	pri, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+	switch pri := pri.(type) {
+	case *rsa.PrivateKey:
+		return &RSAKey{
+			Name:       name,
+			PrivateKey: pri,
+		}, nil
+	case *ecdsa.PrivateKey:
+		return &ECDSAKey{
+			Name:       name,
+			PrivateKey: pri,
+		}, nil
+	case ed25519.PrivateKey:
+		return &Ed25519Key{
+			Name:       name,
+			PrivateKey: pri,
+			PublicKey:  pri.Public().(ed25519.PublicKey),
+		}, nil
+	default:
+		return nil, ErrNotSupported
+	}
+}
+
+func (tpm *fileTPM) DeleteKey(name Name) error {
+	file, err := tpm.path(name)
+	if err != nil {
+		return err
+	}
+	tpm.Lock()
+	defer tpm.Unlock()
+	err = os.Remove(file)
+	if os.IsNotExist(err) {
+		return ErrKeyNotFound
+	}
+	return err
+}
@@ -0,0 +1,121 @@
+package ndn
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-ndn/lpm"
+	"github.com/go-ndn/tlv"
+)
+
+// Errors introduced by Validator.
+var (
+	ErrNoRule        = errors.New("no matching validation rule")
+	ErrRuleViolation = errors.New("signature violates validation rule")
+	ErrUntrusted     = errors.New("signer is not trusted")
+	ErrUnsigned      = errors.New("packet is not signed")
+	ErrChainTooLong  = errors.New("certificate chain exceeds max depth")
+	ErrChainLoop     = errors.New("certificate chain refers to itself")
+)
+
+// Validator checks whether packets are signed by trusted keys.
+type Validator interface {
+	ValidateData(*Data) error
+	ValidateInterest(*Interest) error
+}
+
+// signedName is the signed portion of a signed interest,
+// which contains all name components except SignatureValue.
+type signedName struct {
+	Components []lpm.Component `tlv:"8"`
+}
+
+// interestSignature decodes SignatureInfo and SignatureValue
+// from the last two name components of a signed interest.
+func interestSignature(i *Interest) (sigInfo SignatureInfo, sigValue []byte, signed *signedName, err error) {
+	l := i.Name.Len()
+	if l < 2 {
+		err = ErrUnsigned
+		return
+	}
+	err = tlv.Unmarshal(i.Name.Components[l-2], &sigInfo, 22)
+	if err != nil {
+		return
+	}
+	err = tlv.Unmarshal(i.Name.Components[l-1], &sigValue, 23)
+	if err != nil {
+		return
+	}
+	signed = &signedName{Components: i.Name.Components[:l-1]}
+	return
+}
+
+type validatingFace struct {
+	Face
+	Validator
+}
+
+// NewValidatingFace creates a face that only delivers data packets accepted by v.
+//
+// Validation happens outside the read loop of f, so v can send interests with f
+// to retrieve certificates.
+func NewValidatingFace(f Face, v Validator) Face {
+	return &validatingFace{
+		Face:      f,
+		Validator: v,
+	}
+}
+
+func (f *validatingFace) SendInterest(i *Interest) <-chan *Data {
+	return f.SendInterestContext(context.Background(), i)
+}
+
+func (f *validatingFace) SendInterestContext(ctx context.Context, i *Interest) <-chan *Data {
+	return f.ExpressInterest(ctx, i).ch
+}
+
+// ExpressInterest reports the validation error in Response.Err.
+func (f *validatingFace) ExpressInterest(ctx context.Context, i *Interest) *Response {
+	resp, ch := newResponse(ctx)
+	pending := ExpressInterest(ctx, f.Face, i)
+	go func() {
+		d := pending.Data()
+		if d == nil {
+			resp.err = pending.Err()
+		} else if err := f.ValidateData(d); err != nil {
+			resp.err = err
+		} else {
+			ch <- d
+		}
+		close(ch)
+	}()
+	return resp
+}
+
+// FetchCertificate creates a function that retrieves certificates with s.
+//
+// It can be used as ValidatorConfig.Fetch to walk the certificate chain
+// from KeyLocator up to a trust anchor.
+// If c is not nil, retrieved certificates are cached in c, and
+// later lookups are answered from c first.
+func FetchCertificate(s Sender, c Cache) func(Name) (*Data, error) {
+	return func(name Name) (*Data, error) {
+		i := &Interest{Name: name}
+		if c != nil {
+			if d := c.Get(i); d != nil {
+				return d, nil
+			}
+		}
+		d, ok := <-s.SendInterest(i)
+		if !ok {
+			return nil, ErrTimeout
+		}
+		if d.MetaInfo.ContentType != ContentTypeKey {
+			return nil, ErrCertificateNotFound
+		}
+		if c != nil {
+			c.Add(d)
+		}
+		return d, nil
+	}
+}
@@ -0,0 +1,668 @@
+package ndn
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ValidatorConfig is a validator driven by ndn-cxx validator configuration.
+//
+//	rule
+//	{
+//	  id "example"
+//	  for data
+//	  filter
+//	  {
+//	    type name
+//	    regex ^<example><>*$
+//	  }
+//	  checker
+//	  {
+//	    type customized
+//	    sig-type ecdsa-sha256
+//	    key-locator
+//	    {
+//	      type name
+//	      hyper-relation
+//	      {
+//	        k-regex ^(<>*)<KEY><>$
+//	        k-expand \\1
+//	        h-relation is-prefix-of
+//	        p-regex ^(<>*)$
+//	        p-expand \\1
+//	      }
+//	    }
+//	  }
+//	}
+//	trust-anchor
+//	{
+//	  type file
+//	  file-name "root.ndncert"
+//	}
+//
+// Supported filter relations are equal, is-prefix-of and is-strict-prefix-of.
+// Supported checker types are customized and hierarchical.
+// Supported trust anchor types are file, base64, dir and any.
+// If a dir trust anchor has "refresh" (e.g. 1h, 30m or 10s), its certificates
+// are reloaded when they are older than the refresh period.
+//
+// See https://named-data.net/doc/ndn-cxx/current/tutorials/security-validator-config.html.
+type ValidatorConfig struct {
+	// Fetch retrieves the certificate named by KeyLocator.
+	// If it is nil, packets must be signed by trust anchors.
+	Fetch func(Name) (*Data, error)
+
+	// CacheTTL is how long successful validation results and
+	// validated certificates are remembered.
+	// If it is 0, every packet is validated up to a trust anchor.
+	CacheTTL time.Duration
+
+	// MaxDepth is the maximum number of certificates that are fetched
+	// to validate one packet.
+	MaxDepth int
+
+	// ClockSkew is how far the current time may be outside ValidityPeriod
+	// of packets and certificates in the chain.
+	// Packets outside ValidityPeriod are rejected with ErrCertificateExpired.
+	ClockSkew time.Duration
+
+	// Schema is a trust schema that is used instead of rules.
+	// The packet name and KeyLocator are checked with Schema.Check.
+	Schema *LVSSchema
+
+	results    *expiryCache
+	rules      []*validationRule
+	anchors    map[string]Key
+	anchorDirs []*trustAnchorDir
+	anyAnchor  bool
+	anchorMu   sync.Mutex
+}
+
+// trustAnchorDir contains dynamic trust anchors loaded from a directory.
+type trustAnchorDir struct {
+	dir     string
+	refresh time.Duration
+	loaded  time.Time
+	anchors map[string]Key
+}
+
+type validationRule struct {
+	id       string
+	interest bool
+	filters  []*nameFilter
+	checkers []*signatureChecker
+}
+
+type nameFilter struct {
+	name     Name
+	relation string
+	regex    *Regex
+}
+
+type hyperRelation struct {
+	kRegex   *Regex
+	kExpand  string
+	relation string
+	pRegex   *Regex
+	pExpand  string
+}
+
+type signatureChecker struct {
+	sigType      string
+	hierarchical bool
+	keyLocator   *nameFilter
+	hyper        *hyperRelation
+}
+
+var sigTypes = map[string]uint64{
+	"sha256":         SignatureTypeDigestSHA256,
+	"rsa-sha256":     SignatureTypeSHA256WithRSA,
+	"rsa-pss-sha256": SignatureTypeSHA256WithRSAPSS,
+	"ecdsa-sha256":   SignatureTypeSHA256WithECDSA,
+	"ecdsa-sha384":   SignatureTypeSHA384WithECDSA,
+	"ecdsa-sha512":   SignatureTypeSHA512WithECDSA,
+	"hmac-sha256":    SignatureTypeSHA256WithHMAC,
+	"ed25519":        SignatureTypeEd25519,
+}
+
+// isPrefix checks whether a is a prefix of b.
+func isPrefix(a, b Name) bool {
+	if a.Len() > b.Len() {
+		return false
+	}
+	for i, c := range a.Components {
+		if !bytes.Equal(c, b.Components[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// nameRelation checks whether a and b satisfy relation.
+func nameRelation(relation string, a, b Name) bool {
+	switch relation {
+	case "equal":
+		return a.Compare(b) == 0
+	case "is-prefix-of":
+		return isPrefix(a, b)
+	case "is-strict-prefix-of":
+		return a.Len() < b.Len() && isPrefix(a, b)
+	default:
+		return false
+	}
+}
+
+// NewValidatorConfig creates an empty validator that rejects all packets.
+func NewValidatorConfig() *ValidatorConfig {
+	return &ValidatorConfig{
+		anchors:  make(map[string]Key),
+		results:  newExpiryCache(4096),
+		MaxDepth: 25,
+	}
+}
+
+// LoadValidatorConfig creates a validator from a configuration file.
+//
+// Relative paths in the file are resolved from its directory.
+func LoadValidatorConfig(file string) (*ValidatorConfig, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	v := NewValidatorConfig()
+	err = v.Load(b, filepath.Dir(file))
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Load adds rules and trust anchors from configuration.
+//
+// Relative paths are resolved from dir.
+func (v *ValidatorConfig) Load(config []byte, dir string) error {
+	root, err := parseConf(bytes.NewReader(config))
+	if err != nil {
+		return err
+	}
+	for _, node := range root.Children {
+		switch node.Key {
+		case "rule":
+			rule, err := parseValidationRule(node)
+			if err != nil {
+				return err
+			}
+			v.rules = append(v.rules, rule)
+		case "trust-anchor":
+			err = v.loadTrustAnchor(node, dir)
+			if err != nil {
+				return err
+			}
+		default:
+			return ErrInvalidConfig
+		}
+	}
+	return nil
+}
+
+func parseNameFilter(node *confNode) (*nameFilter, error) {
+	if node.Get("type") != "name" {
+		return nil, ErrNotSupported
+	}
+	if expr := node.Get("regex"); expr != "" {
+		re, err := NewRegex(expr)
+		if err != nil {
+			return nil, err
+		}
+		return &nameFilter{regex: re}, nil
+	}
+	f := &nameFilter{
+		name:     NewName(node.Get("name")),
+		relation: node.Get("relation"),
+	}
+	switch f.relation {
+	case "equal", "is-prefix-of", "is-strict-prefix-of":
+	default:
+		return nil, ErrInvalidConfig
+	}
+	return f, nil
+}
+
+// match checks whether the filter accepts the name.
+func (f *nameFilter) match(name Name) bool {
+	if f.regex != nil {
+		return f.regex.Match(name)
+	}
+	return nameRelation(f.relation, f.name, name)
+}
+
+func parseHyperRelation(node *confNode) (*hyperRelation, error) {
+	kRegex, err := NewRegex(node.Get("k-regex"))
+	if err != nil {
+		return nil, err
+	}
+	pRegex, err := NewRegex(node.Get("p-regex"))
+	if err != nil {
+		return nil, err
+	}
+	return &hyperRelation{
+		kRegex:   kRegex,
+		kExpand:  node.Get("k-expand"),
+		relation: node.Get("h-relation"),
+		pRegex:   pRegex,
+		pExpand:  node.Get("p-expand"),
+	}, nil
+}
+
+func parseSignatureChecker(node *confNode) (*signatureChecker, error) {
+	c := &signatureChecker{
+		sigType: node.Get("sig-type"),
+	}
+	if _, ok := sigTypes[c.sigType]; c.sigType != "" && !ok {
+		return nil, ErrNotSupported
+	}
+	switch node.Get("type") {
+	case "hierarchical":
+		c.hierarchical = true
+	case "customized":
+		kl := node.Child("key-locator")
+		if kl == nil {
+			return nil, ErrInvalidConfig
+		}
+		if hr := kl.Child("hyper-relation"); hr != nil {
+			hyper, err := parseHyperRelation(hr)
+			if err != nil {
+				return nil, err
+			}
+			c.hyper = hyper
+		} else {
+			f, err := parseNameFilter(kl)
+			if err != nil {
+				return nil, err
+			}
+			c.keyLocator = f
+		}
+	default:
+		return nil, ErrNotSupported
+	}
+	return c, nil
+}
+
+func parseValidationRule(node *confNode) (*validationRule, error) {
+	rule := &validationRule{
+		id: node.Get("id"),
+	}
+	switch node.Get("for") {
+	case "data":
+	case "interest":
+		rule.interest = true
+	default:
+		return nil, ErrInvalidConfig
+	}
+	for _, child := range node.All("filter") {
+		f, err := parseNameFilter(child)
+		if err != nil {
+			return nil, err
+		}
+		rule.filters = append(rule.filters, f)
+	}
+	for _, child := range node.All("checker") {
+		c, err := parseSignatureChecker(child)
+		if err != nil {
+			return nil, err
+		}
+		rule.checkers = append(rule.checkers, c)
+	}
+	if len(rule.checkers) == 0 {
+		return nil, ErrInvalidConfig
+	}
+	return rule, nil
+}
+
+// match checks whether any filter accepts the name.
+//
+// A rule without filters matches all names.
+func (rule *validationRule) match(interest bool, name Name) bool {
+	if rule.interest != interest {
+		return false
+	}
+	if len(rule.filters) == 0 {
+		return true
+	}
+	for _, f := range rule.filters {
+		if f.match(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// check checks whether any checker accepts the signature.
+func (rule *validationRule) check(name Name, sigInfo *SignatureInfo) error {
+	for _, c := range rule.checkers {
+		if c.check(name, sigInfo) {
+			return nil
+		}
+	}
+	return ErrRuleViolation
+}
+
+func (c *signatureChecker) check(name Name, sigInfo *SignatureInfo) bool {
+	if c.sigType != "" && sigTypes[c.sigType] != sigInfo.SignatureType {
+		return false
+	}
+	keyName := sigInfo.KeyLocator.Name
+	switch {
+	case c.hierarchical:
+		return isPrefix(keyIdentity(keyName), name)
+	case c.hyper != nil:
+		k, ok := c.hyper.kRegex.Expand(keyName, c.hyper.kExpand)
+		if !ok {
+			return false
+		}
+		p, ok := c.hyper.pRegex.Expand(name, c.hyper.pExpand)
+		if !ok {
+			return false
+		}
+		return nameRelation(c.hyper.relation, k, p)
+	case c.keyLocator != nil:
+		return c.keyLocator.match(keyName)
+	}
+	return false
+}
+
+func (v *ValidatorConfig) loadTrustAnchor(node *confNode, dir string) error {
+	switch node.Get("type") {
+	case "any":
+		v.anyAnchor = true
+		return nil
+	case "base64":
+		cert, err := decodeCertificateData(strings.NewReader(node.Get("base64-string")))
+		if err != nil {
+			return err
+		}
+		return v.AddTrustAnchor(cert)
+	case "file":
+		return v.addTrustAnchorFile(resolvePath(dir, node.Get("file-name")))
+	case "dir":
+		var refresh time.Duration
+		if node.Get("refresh") != "" {
+			var err error
+			refresh, err = parseConfDuration(node.Get("refresh"))
+			if err != nil {
+				return err
+			}
+		}
+		return v.AddTrustAnchorDir(resolvePath(dir, node.Get("dir")), refresh)
+	default:
+		return ErrNotSupported
+	}
+}
+
+func resolvePath(dir, file string) string {
+	if filepath.IsAbs(file) {
+		return file
+	}
+	return filepath.Join(dir, file)
+}
+
+func (v *ValidatorConfig) addTrustAnchorFile(file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	cert, err := decodeCertificateData(f)
+	if err != nil {
+		return err
+	}
+	return v.AddTrustAnchor(cert)
+}
+
+// loadTrustAnchors reads all certificates in dir.
+//
+// Files that are not certificates are ignored.
+func loadTrustAnchors(dir string) (map[string]Key, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	anchors := make(map[string]Key)
+	for _, fi := range files {
+		if fi.IsDir() {
+			continue
+		}
+		b, err := ioutil.ReadFile(filepath.Join(dir, fi.Name()))
+		if err != nil {
+			continue
+		}
+		cert, err := decodeCertificateData(bytes.NewReader(b))
+		if err != nil {
+			continue
+		}
+		key, err := CertificateFromData(cert)
+		if err != nil {
+			continue
+		}
+		anchors[cert.Name.String()] = key
+		anchors[key.Locator().String()] = key
+	}
+	return anchors, nil
+}
+
+// AddTrustAnchor trusts a certificate.
+//
+// Its signature is not verified.
+func (v *ValidatorConfig) AddTrustAnchor(cert *Data) error {
+	key, err := CertificateFromData(cert)
+	if err != nil {
+		return err
+	}
+	v.anchorMu.Lock()
+	v.anchors[cert.Name.String()] = key
+	v.anchors[key.Locator().String()] = key
+	v.anchorMu.Unlock()
+	return nil
+}
+
+// AddTrustAnchorDir trusts all certificates in dir.
+//
+// If refresh is 0, the certificates are loaded only once.
+// Otherwise, they are reloaded when they are older than refresh,
+// so anchors can be rotated without restarting.
+func (v *ValidatorConfig) AddTrustAnchorDir(dir string, refresh time.Duration) error {
+	anchors, err := loadTrustAnchors(dir)
+	if err != nil {
+		return err
+	}
+	v.anchorMu.Lock()
+	defer v.anchorMu.Unlock()
+	if refresh == 0 {
+		for name, key := range anchors {
+			v.anchors[name] = key
+		}
+		return nil
+	}
+	v.anchorDirs = append(v.anchorDirs, &trustAnchorDir{
+		dir:     dir,
+		refresh: refresh,
+		loaded:  time.Now(),
+		anchors: anchors,
+	})
+	return nil
+}
+
+// Refresh reloads all dynamic trust anchors immediately.
+func (v *ValidatorConfig) Refresh() {
+	v.anchorMu.Lock()
+	defer v.anchorMu.Unlock()
+	for _, ad := range v.anchorDirs {
+		ad.reload()
+	}
+}
+
+// reload keeps old anchors if dir cannot be read.
+func (ad *trustAnchorDir) reload() {
+	anchors, err := loadTrustAnchors(ad.dir)
+	if err != nil {
+		return
+	}
+	ad.anchors = anchors
+	ad.loaded = time.Now()
+}
+
+// anchor finds a trust anchor by certificate name or key name.
+func (v *ValidatorConfig) anchor(name Name) (Key, bool) {
+	s := name.String()
+	v.anchorMu.Lock()
+	defer v.anchorMu.Unlock()
+	if key, ok := v.anchors[s]; ok {
+		return key, true
+	}
+	for _, ad := range v.anchorDirs {
+		if time.Since(ad.loaded) > ad.refresh {
+			ad.reload()
+		}
+		if key, ok := ad.anchors[s]; ok {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+// rule returns the first rule that matches the name.
+func (v *ValidatorConfig) rule(interest bool, name Name) *validationRule {
+	for _, rule := range v.rules {
+		if rule.match(interest, name) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// signerKey finds the key named by KeyLocator.
+//
+// If the key is not a trust anchor, its certificate is fetched and validated.
+// chain contains certificates that are already fetched for this packet.
+func (v *ValidatorConfig) signerKey(name Name, chain []Name) (Key, error) {
+	if key, ok := v.anchor(name); ok {
+		return key, nil
+	}
+	if v.CacheTTL > 0 {
+		if key, ok := v.results.Get("cert:" + name.String()); ok {
+			return key.(Key), nil
+		}
+	}
+	if v.Fetch == nil {
+		return nil, ErrUntrusted
+	}
+	for _, c := range chain {
+		if isPrefix(name, c) {
+			return nil, ErrChainLoop
+		}
+	}
+	if len(chain) >= v.MaxDepth {
+		return nil, ErrChainTooLong
+	}
+	cert, err := v.Fetch(name)
+	if err != nil {
+		return nil, err
+	}
+	err = v.validateData(cert, append(chain, cert.Name))
+	if err != nil {
+		return nil, err
+	}
+	key, err := CertificateFromData(cert)
+	if err != nil {
+		return nil, err
+	}
+	if v.CacheTTL > 0 {
+		ttl := v.CacheTTL
+		// a cached certificate must not outlive its validity period
+		if notAfter := cert.SignatureInfo.ValidityPeriod.NotAfter; notAfter != "" {
+			t, err := time.Parse(ISO8601, notAfter)
+			if err == nil && time.Until(t.Add(v.ClockSkew)) < ttl {
+				ttl = time.Until(t.Add(v.ClockSkew))
+			}
+		}
+		v.results.Add("cert:"+name.String(), key, ttl)
+		v.results.Add("cert:"+cert.Name.String(), key, ttl)
+	}
+	return key, nil
+}
+
+func (v *ValidatorConfig) validate(interest bool, name Name, sigInfo *SignatureInfo, verify func(Key) error, chain []Name) error {
+	if v.anyAnchor {
+		return nil
+	}
+	var err error
+	if v.Schema != nil {
+		err = v.Schema.Check(name, sigInfo.KeyLocator.Name)
+	} else {
+		rule := v.rule(interest, name)
+		if rule == nil {
+			return ErrNoRule
+		}
+		err = rule.check(name, sigInfo)
+	}
+	if err != nil {
+		return err
+	}
+	key, err := v.signerKey(sigInfo.KeyLocator.Name, chain)
+	if err != nil {
+		return err
+	}
+	return verify(key)
+}
+
+// ValidateData implements Validator.
+//
+// If CacheTTL is not 0, the result is remembered by the implicit digest of d
+// and its KeyLocator.
+func (v *ValidatorConfig) ValidateData(d *Data) error {
+	return v.validateData(d, nil)
+}
+
+func (v *ValidatorConfig) validateData(d *Data, chain []Name) error {
+	var cacheKey string
+	if v.CacheTTL > 0 {
+		digest, err := dataDigest(d)
+		if err != nil {
+			return err
+		}
+		cacheKey = fmt.Sprintf("data:%x%s", digest, d.SignatureInfo.KeyLocator.Name)
+		if _, ok := v.results.Get(cacheKey); ok {
+			return nil
+		}
+	}
+	err := v.validate(false, d.Name, &d.SignatureInfo, func(key Key) error {
+		return verifyData(key, d, v.ClockSkew)
+	}, chain)
+	if err != nil {
+		return err
+	}
+	if v.CacheTTL > 0 {
+		v.results.Add(cacheKey, true, v.CacheTTL)
+	}
+	return nil
+}
+
+// ValidateInterest implements Validator.
+//
+// Rules are matched against the interest name without signature components.
+func (v *ValidatorConfig) ValidateInterest(i *Interest) error {
+	sigInfo, sigValue, signed, err := interestSignature(i)
+	if err != nil {
+		return err
+	}
+	name := Name{Components: signed.Components[:len(signed.Components)-1]}
+	return v.validate(true, name, &sigInfo, func(key Key) error {
+		return verificationKey(key, sigInfo.SignatureType).Verify(signed, sigValue)
+	}, nil)
+}
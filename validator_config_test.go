@@ -0,0 +1,472 @@
+package ndn
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRegex(t *testing.T) {
+	for _, test := range []struct {
+		regex string
+		in    string
+		want  bool
+	}{
+		{"^<a><>*$", "/a/b/c", true},
+		{"^<a><>*$", "/b/c", false},
+		{"^<a><b>$", "/a/b/c", false},
+		{"<b>", "/a/b/c", true},
+		{"^<>*<KEY><>$", "/a/KEY/1", true},
+		{"^<a.*>+$", "/abc/ab", true},
+		{"^[<a><b>]<c>$", "/b/c", true},
+		{"^<a>?<c>$", "/c", true},
+		{"^(<a><b>)+$", "/a/b/a/b", true},
+		{"^(<a><b>)+$", "/a/b/a", false},
+		{"^<a>(<b><c>)*<d>$", "/a/d", true},
+		{"^<a>(<b><c>)*<d>$", "/a/b/c/b/c/d", true},
+		{"^<a>(<b><c>)*<d>$", "/a/b/d", false},
+		{"^(<>*)<KEY><>$", "/KEY/1", true},
+	} {
+		re, err := NewRegex(test.regex)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := re.Match(NewName(test.in))
+		if got != test.want {
+			t.Fatalf("Match(%v, %v) == %v, got %v", test.regex, test.in, test.want, got)
+		}
+	}
+
+	for _, test := range []struct {
+		regex    string
+		in       string
+		template string
+		want     string
+	}{
+		{"^(<>*)<KEY><>$", "/a/b/KEY/1", "\\1", "/a/b"},
+		{"^(<>*)<KEY><>$", "/a/b/c/KEY/1", "\\1", "/a/b/c"},
+		{"^(<>*)<KEY><>$", "/KEY/1", "\\1", "/"},
+		{"^(<a><b>)+(<>)$", "/a/b/a/b/c", "\\2\\1", "/c/a/b"},
+		{"^<a>(<>)(<>*)$", "/a/b/c/d", "\\2<x>\\1", "/c/d/x/b"},
+	} {
+		re, err := NewRegex(test.regex)
+		if err != nil {
+			t.Fatal(err)
+		}
+		name, ok := re.Expand(NewName(test.in), test.template)
+		if !ok {
+			t.Fatalf("Expand(%v, %v): expect match", test.regex, test.in)
+		}
+		if want := NewName(test.want); want.Compare(name) != 0 {
+			t.Fatalf("Expand(%v, %v, %v) == %v, got %v", test.regex, test.in, test.template, want, name)
+		}
+	}
+}
+
+func TestValidatorConfig(t *testing.T) {
+	cert, err := CertificateToData(ecdsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := new(bytes.Buffer)
+	err = encodeCertificateData(cert, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := fmt.Sprintf(`
+rule
+{
+  id "hierarchical"
+  for data
+  filter
+  {
+    type name
+    name /ndn/guest/alice
+    relation is-prefix-of
+  }
+  checker
+  {
+    type customized
+    sig-type ecdsa-sha256
+    key-locator
+    {
+      type name
+      hyper-relation
+      {
+        k-regex ^(<>*)<KEY><>$
+        k-expand \\1
+        h-relation is-prefix-of
+        p-regex ^(<>*)$
+        p-expand \\1
+      }
+    }
+  }
+}
+trust-anchor
+{
+  type base64
+  base64-string "%s"
+}
+`, buf.String())
+
+	v := NewValidatorConfig()
+	err = v.Load([]byte(config), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name string
+		key  Key
+		want error
+	}{
+		{"/ndn/guest/alice/1434508996774/hello", ecdsaKey, nil},
+		{"/ndn/guest/alice/other", ecdsaKey, ErrRuleViolation},
+		{"/ndn/guest/bob", ecdsaKey, ErrNoRule},
+		{"/ndn/guest/alice/1434508996774/hello", rsaKey, ErrRuleViolation},
+	} {
+		d := &Data{Name: NewName(test.name)}
+		err := SignData(test.key, d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := v.ValidateData(d)
+		if got != test.want {
+			t.Fatalf("ValidateData(%v) == %v, got %v", test.name, test.want, got)
+		}
+	}
+}
+
+func TestTrustAnchorDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ndn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	v := NewValidatorConfig()
+	err = v.Load([]byte(`
+rule
+{
+  for data
+  checker
+  {
+    type hierarchical
+  }
+}
+trust-anchor
+{
+  type dir
+  dir anchors
+  refresh 1h
+}
+`), dir)
+	if err == nil {
+		t.Fatal("expect error for missing dir")
+	}
+
+	err = os.Mkdir(filepath.Join(dir, "anchors"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v = NewValidatorConfig()
+	err = v.Load([]byte(`
+rule
+{
+  for data
+  checker
+  {
+    type hierarchical
+  }
+}
+`), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = v.AddTrustAnchorDir(filepath.Join(dir, "anchors"), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := &Data{Name: NewName("/ndn/guest/alice/1434508996774/hello")}
+	err = SignData(ecdsaKey, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = v.ValidateData(d)
+	if err != ErrUntrusted {
+		t.Fatalf("expect %v, got %v", ErrUntrusted, err)
+	}
+
+	// rotate anchor
+	f, err := os.Create(filepath.Join(dir, "anchors", "alice.ndncert"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = EncodeCertificate(ecdsaKey, f)
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.Refresh()
+	err = v.ValidateData(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFetchCertificate(t *testing.T) {
+	now := time.Now()
+	public, err := ecdsaKey.Public()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := IssueCertificate(&Certificate{
+		Name:      ecdsaKey.Locator(),
+		IssuerID:  []byte("alice"),
+		Version:   1,
+		PublicKey: public,
+		NotBefore: now.Add(-time.Hour),
+		NotAfter:  now.Add(time.Hour),
+	}, rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	consumer, producer := newPipe(func(i *Interest) *Data {
+		if isPrefix(i.Name, cert.Name) {
+			return cert
+		}
+		return nil
+	})
+	defer producer.Close()
+	defer consumer.Close()
+
+	anchor, err := CertificateToData(rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := NewValidatorConfig()
+	err = v.Load([]byte(`
+rule
+{
+  for data
+  checker
+  {
+    type customized
+    key-locator
+    {
+      type name
+      regex ^<ndn><guest><alice><>*$
+    }
+  }
+}
+`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = v.AddTrustAnchor(anchor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewCache(16)
+	v.Fetch = FetchCertificate(consumer, c)
+
+	d := &Data{Name: NewName("/hello")}
+	err = SignData(ecdsaKey, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = v.ValidateData(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Get(&Interest{Name: ecdsaKey.Locator()}) == nil {
+		t.Fatal("expect cached certificate")
+	}
+}
+
+func TestValidatorCache(t *testing.T) {
+	anchor, err := CertificateToData(rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	public, err := ecdsaKey.Public()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	cert, err := IssueCertificate(&Certificate{
+		Name:      ecdsaKey.Locator(),
+		IssuerID:  []byte("alice"),
+		Version:   1,
+		PublicKey: public,
+		NotBefore: now.Add(-time.Hour),
+		NotAfter:  now.Add(time.Hour),
+	}, rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v := NewValidatorConfig()
+	err = v.Load([]byte(`
+rule
+{
+  for data
+  checker
+  {
+    type customized
+    key-locator
+    {
+      type name
+      regex ^<ndn><guest><alice><>*$
+    }
+  }
+}
+`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = v.AddTrustAnchor(anchor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fetched int
+	v.Fetch = func(Name) (*Data, error) {
+		fetched++
+		return cert, nil
+	}
+	v.CacheTTL = time.Minute
+
+	for i := 0; i < 3; i++ {
+		d := &Data{Name: NewName(fmt.Sprintf("/hello/%d", i))}
+		err = SignData(ecdsaKey, d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = v.ValidateData(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if fetched != 1 {
+		t.Fatalf("expect 1 certificate fetch, got %d", fetched)
+	}
+}
+
+func TestValidatorChainLoop(t *testing.T) {
+	v := NewValidatorConfig()
+	err := v.Load([]byte(`
+rule
+{
+  for data
+  checker
+  {
+    type customized
+    key-locator
+    {
+      type name
+      regex ^<>*$
+    }
+  }
+}
+`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the certificate is signed by itself, but it is not a trust anchor.
+	cert, err := CertificateToData(ecdsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.Fetch = func(Name) (*Data, error) {
+		return cert, nil
+	}
+	d := &Data{Name: NewName("/hello")}
+	err = SignData(ecdsaKey, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = v.ValidateData(d)
+	if err != ErrChainLoop {
+		t.Fatalf("expect %v, got %v", ErrChainLoop, err)
+	}
+
+	v.MaxDepth = 0
+	err = v.ValidateData(d)
+	if err != ErrChainTooLong {
+		t.Fatalf("expect %v, got %v", ErrChainTooLong, err)
+	}
+}
+
+func TestValidatorExpired(t *testing.T) {
+	anchor, err := CertificateToData(rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	public, err := ecdsaKey.Public()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	cert, err := IssueCertificate(&Certificate{
+		Name:      ecdsaKey.Locator(),
+		IssuerID:  []byte("alice"),
+		Version:   1,
+		PublicKey: public,
+		NotBefore: now.Add(-2 * time.Hour),
+		NotAfter:  now.Add(-time.Hour),
+	}, rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v := NewValidatorConfig()
+	err = v.Load([]byte(`
+rule
+{
+  for data
+  checker
+  {
+    type customized
+    key-locator
+    {
+      type name
+      regex ^<>*$
+    }
+  }
+}
+`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = v.AddTrustAnchor(anchor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.Fetch = func(Name) (*Data, error) {
+		return cert, nil
+	}
+	d := &Data{Name: NewName("/hello")}
+	err = SignData(ecdsaKey, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		skew time.Duration
+		want error
+	}{
+		{0, ErrCertificateExpired},
+		{30 * time.Minute, ErrCertificateExpired},
+		{2 * time.Hour, nil},
+	} {
+		v.ClockSkew = test.skew
+		err = v.ValidateData(d)
+		if err != test.want {
+			t.Fatalf("expect %v, got %v", test.want, err)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package ndn
+
+import (
+	"runtime"
+	"sync"
+)
+
+// VerifyResult is the verdict of a data packet from VerifyPool.
+type VerifyResult struct {
+	Data *Data
+	Err  error
+}
+
+// VerifyPool validates data packets in parallel with a bounded number of workers.
+//
+// Signature verification is CPU-bound, so high-rate consumers can
+// spread it across cores instead of validating in the read loop.
+type VerifyPool struct {
+	Validator
+	jobs chan verifyJob
+	wg   sync.WaitGroup
+}
+
+type verifyJob struct {
+	d    *Data
+	done func(*Data, error)
+}
+
+// NewVerifyPool starts workers that validate data packets with v.
+//
+// If workers is not positive, runtime.NumCPU() workers are started.
+func NewVerifyPool(v Validator, workers int) *VerifyPool {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	p := &VerifyPool{
+		Validator: v,
+		jobs:      make(chan verifyJob, workers),
+	}
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer p.wg.Done()
+			for job := range p.jobs {
+				job.done(job.d, p.ValidateData(job.d))
+			}
+		}()
+	}
+	return p
+}
+
+// Submit queues d for validation, and done is invoked by a worker with the verdict.
+//
+// Submit blocks if all workers are busy and the queue is full.
+func (p *VerifyPool) Submit(d *Data, done func(*Data, error)) {
+	p.jobs <- verifyJob{
+		d:    d,
+		done: done,
+	}
+}
+
+// Verify queues d for validation, and the verdict is delivered to the returned channel.
+func (p *VerifyPool) Verify(d *Data) <-chan VerifyResult {
+	ch := make(chan VerifyResult, 1)
+	p.Submit(d, func(d *Data, err error) {
+		ch <- VerifyResult{
+			Data: d,
+			Err:  err,
+		}
+	})
+	return ch
+}
+
+// Close waits until queued packets are validated, and stops workers.
+//
+// Submit must not be invoked after Close.
+func (p *VerifyPool) Close() {
+	close(p.jobs)
+	p.wg.Wait()
+}
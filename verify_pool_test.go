@@ -0,0 +1,67 @@
+package ndn
+
+import (
+	"fmt"
+	"testing"
+)
+
+type keyValidator struct {
+	Key
+}
+
+func (v keyValidator) ValidateData(d *Data) error {
+	return VerifyData(v.Key, d)
+}
+
+func (v keyValidator) ValidateInterest(*Interest) error {
+	return ErrNotSupported
+}
+
+func TestVerifyPool(t *testing.T) {
+	p := NewVerifyPool(keyValidator{ecdsaKey}, 4)
+	defer p.Close()
+
+	var results []<-chan VerifyResult
+	for i := 0; i < 16; i++ {
+		d := &Data{
+			Name: NewName(fmt.Sprintf("/A/%d", i)),
+		}
+		err := SignData(ecdsaKey, d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i%2 == 1 {
+			d.Content = []byte("modified")
+		}
+		results = append(results, p.Verify(d))
+	}
+	for i, ch := range results {
+		result := <-ch
+		if want := fmt.Sprintf("/A/%d", i); result.Data.Name.String() != want {
+			t.Fatalf("expect %s, got %v", want, result.Data.Name)
+		}
+		if (result.Err == nil) != (i%2 == 0) {
+			t.Fatalf("expect valid == %v, got %v", i%2 == 0, result.Err)
+		}
+	}
+}
+
+func BenchmarkVerifyPool(b *testing.B) {
+	d := new(Data)
+	err := SignData(ecdsaKey, d)
+	if err != nil {
+		b.Fatal(err)
+	}
+	p := NewVerifyPool(keyValidator{ecdsaKey}, 0)
+	defer p.Close()
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			result := <-p.Verify(d)
+			if result.Err != nil {
+				b.Fatal(result.Err)
+			}
+		}
+	})
+}
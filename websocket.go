@@ -0,0 +1,374 @@
+package ndn
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Errors introduced by WebSocket transport.
+var (
+	ErrWebSocketHandshake = errors.New("invalid websocket handshake")
+	ErrWebSocketFrame     = errors.New("invalid websocket frame")
+)
+
+// websocketGUID is appended to Sec-WebSocket-Key in RFC 6455.
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// websocketMaxMessageSize bounds the memory used by one message.
+const websocketMaxMessageSize = 1 << 20
+
+// WebSocket opcodes.
+const (
+	wsOpContinuation = 0x0
+	wsOpText         = 0x1
+	wsOpBinary       = 0x2
+	wsOpClose        = 0x8
+	wsOpPing         = 0x9
+	wsOpPong         = 0xA
+)
+
+func websocketAccept(key string) string {
+	h := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+// wsConn carries one packet in one binary message.
+//
+// It is used with NewDatagramFace.
+type wsConn struct {
+	net.Conn
+	r *bufio.Reader
+	// client frames are masked.
+	client bool
+	wm     sync.Mutex
+}
+
+// Read reads one message.
+//
+// If b is too small, the rest of the message is discarded.
+// Control frames are handled while waiting for the message.
+func (c *wsConn) Read(b []byte) (int, error) {
+	var msg []byte
+	for {
+		fin, op, payload, err := c.readFrame()
+		if err != nil {
+			return 0, err
+		}
+		switch op {
+		case wsOpClose:
+			c.writeFrame(wsOpClose, nil)
+			return 0, io.EOF
+		case wsOpPing:
+			err = c.writeFrame(wsOpPong, payload)
+			if err != nil {
+				return 0, err
+			}
+			continue
+		case wsOpPong:
+			continue
+		case wsOpContinuation, wsOpText, wsOpBinary:
+		default:
+			return 0, ErrWebSocketFrame
+		}
+		msg = append(msg, payload...)
+		if len(msg) > websocketMaxMessageSize {
+			return 0, ErrWebSocketFrame
+		}
+		if fin {
+			return copy(b, msg), nil
+		}
+	}
+}
+
+func (c *wsConn) readFrame() (fin bool, op byte, payload []byte, err error) {
+	var h [2]byte
+	_, err = io.ReadFull(c.r, h[:])
+	if err != nil {
+		return
+	}
+	fin = h[0]&0x80 != 0
+	op = h[0] & 0x0F
+	n := uint64(h[1] & 0x7F)
+	switch n {
+	case 126:
+		var ext [2]byte
+		_, err = io.ReadFull(c.r, ext[:])
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		_, err = io.ReadFull(c.r, ext[:])
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+	if err != nil {
+		return
+	}
+	if n > websocketMaxMessageSize {
+		err = ErrWebSocketFrame
+		return
+	}
+	var mask [4]byte
+	masked := h[1]&0x80 != 0
+	if masked {
+		_, err = io.ReadFull(c.r, mask[:])
+		if err != nil {
+			return
+		}
+	}
+	payload = make([]byte, n)
+	_, err = io.ReadFull(c.r, payload)
+	if err != nil {
+		return
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return
+}
+
+func (c *wsConn) writeFrame(op byte, payload []byte) error {
+	buf := make([]byte, 0, 14+len(payload))
+	buf = append(buf, 0x80|op)
+	var maskBit byte
+	if c.client {
+		maskBit = 0x80
+	}
+	switch n := len(payload); {
+	case n < 126:
+		buf = append(buf, maskBit|byte(n))
+	case n <= 0xFFFF:
+		buf = append(buf, maskBit|126, byte(n>>8), byte(n))
+	default:
+		buf = append(buf, maskBit|127)
+		buf = binary.BigEndian.AppendUint64(buf, uint64(n))
+	}
+	if c.client {
+		var mask [4]byte
+		_, err := rand.Read(mask[:])
+		if err != nil {
+			return err
+		}
+		buf = append(buf, mask[:]...)
+		for i, b := range payload {
+			buf = append(buf, b^mask[i%4])
+		}
+	} else {
+		buf = append(buf, payload...)
+	}
+	c.wm.Lock()
+	defer c.wm.Unlock()
+	_, err := c.Conn.Write(buf)
+	return err
+}
+
+// Write sends b in one binary message.
+func (c *wsConn) Write(b []byte) (int, error) {
+	err := c.writeFrame(wsOpBinary, b)
+	if err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// Close sends a close frame before closing the connection.
+func (c *wsConn) Close() error {
+	c.SetWriteDeadline(time.Now().Add(time.Second))
+	c.writeFrame(wsOpClose, nil)
+	return c.Conn.Close()
+}
+
+// DialWebSocket connects to a WebSocket endpoint, such as
+// NFD WebSocket channel ws://localhost:9696 or a wss:// hub.
+//
+// Every packet is carried in one binary message.
+// opts are passed to NewDatagramFace.
+func DialWebSocket(rawurl string, opts ...FaceOption) (Face, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	var conn net.Conn
+	switch u.Scheme {
+	case "ws":
+		conn, err = net.Dial("tcp", websocketHost(u, "80"))
+	case "wss":
+		conn, err = tls.Dial("tcp", websocketHost(u, "443"), &tls.Config{
+			ServerName: u.Hostname(),
+		})
+	default:
+		return nil, ErrNotSupported
+	}
+	if err != nil {
+		return nil, err
+	}
+	ws, err := websocketClientHandshake(conn, u)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return NewDatagramFace(ws, opts...), nil
+}
+
+// websocketHost returns host:port of u with the default port.
+func websocketHost(u *url.URL, port string) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+func websocketClientHandshake(conn net.Conn, u *url.URL) (*wsConn, error) {
+	var nonce [16]byte
+	_, err := rand.Read(nonce[:])
+	if err != nil {
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(nonce[:])
+	req := &http.Request{
+		Method: http.MethodGet,
+		URL:    u,
+		Host:   u.Host,
+		Header: http.Header{
+			"Upgrade":               {"websocket"},
+			"Connection":            {"Upgrade"},
+			"Sec-Websocket-Key":     {key},
+			"Sec-Websocket-Version": {"13"},
+		},
+	}
+	err = req.Write(conn)
+	if err != nil {
+		return nil, err
+	}
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols ||
+		!strings.EqualFold(resp.Header.Get("Upgrade"), "websocket") ||
+		resp.Header.Get("Sec-Websocket-Accept") != websocketAccept(key) {
+		return nil, ErrWebSocketHandshake
+	}
+	return &wsConn{
+		Conn:   conn,
+		r:      r,
+		client: true,
+	}, nil
+}
+
+// WebSocketHandler accepts WebSocket connections, and serves each connection
+// as a face, so that browser clients such as NDNts can reach a producer
+// without a forwarder.
+type WebSocketHandler struct {
+	// Serve handles a face and its incoming interests.
+	// The face is closed after Serve returns.
+	Serve func(f Face, recv <-chan *Interest)
+
+	// CheckOrigin decides whether a browser page can connect.
+	// If it is nil, all origins are accepted.
+	CheckOrigin func(r *http.Request) bool
+}
+
+// headerContains checks whether a comma-separated header has token.
+func headerContains(h http.Header, key, token string) bool {
+	for _, v := range strings.Split(h.Get(key), ",") {
+		if strings.EqualFold(strings.TrimSpace(v), token) {
+			return true
+		}
+	}
+	return false
+}
+
+// websocketKey validates an upgrade request, and returns its Sec-WebSocket-Key.
+func websocketKey(r *http.Request) (string, error) {
+	key := r.Header.Get("Sec-Websocket-Key")
+	if r.Method != http.MethodGet ||
+		!headerContains(r.Header, "Upgrade", "websocket") ||
+		!headerContains(r.Header, "Connection", "upgrade") ||
+		r.Header.Get("Sec-Websocket-Version") != "13" || key == "" {
+		return "", ErrWebSocketHandshake
+	}
+	return key, nil
+}
+
+// websocketResponse is the response to an upgrade request with key.
+func websocketResponse(key string) string {
+	return "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + websocketAccept(key) + "\r\n\r\n"
+}
+
+// websocketServerHandshake upgrades conn without http.Server, and
+// does not check the origin.
+func websocketServerHandshake(conn net.Conn) (*wsConn, error) {
+	r := bufio.NewReader(conn)
+	req, err := http.ReadRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	key, err := websocketKey(req)
+	if err != nil {
+		io.WriteString(conn, "HTTP/1.1 400 Bad Request\r\nConnection: close\r\n\r\n")
+		return nil, err
+	}
+	_, err = io.WriteString(conn, websocketResponse(key))
+	if err != nil {
+		return nil, err
+	}
+	return &wsConn{
+		Conn: conn,
+		r:    r,
+	}, nil
+}
+
+// ServeHTTP implements http.Handler.
+func (h *WebSocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key, err := websocketKey(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if h.CheckOrigin != nil && !h.CheckOrigin(r) {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, ErrNotSupported.Error(), http.StatusInternalServerError)
+		return
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return
+	}
+	_, err = rw.WriteString(websocketResponse(key))
+	if err == nil {
+		err = rw.Flush()
+	}
+	if err != nil {
+		conn.Close()
+		return
+	}
+	recv := make(chan *Interest)
+	f := NewDatagramFace(&wsConn{
+		Conn: conn,
+		r:    rw.Reader,
+	}, WithInterestQueue(recv))
+	defer f.Close()
+	h.Serve(f, recv)
+}
@@ -0,0 +1,152 @@
+package ndn
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestWebSocketFrame(t *testing.T) {
+	c1, c2 := net.Pipe()
+	client := &wsConn{Conn: c1, r: bufio.NewReader(c1), client: true}
+	server := &wsConn{Conn: c2, r: bufio.NewReader(c2)}
+	defer client.Close()
+	defer server.Close()
+
+	for _, size := range []int{10, 1000, 70000} {
+		msg := bytes.Repeat([]byte{'A'}, size)
+		go client.Write(msg)
+		buf := make([]byte, 100000)
+		n, err := server.Read(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(buf[:n], msg) {
+			t.Fatalf("expect %d bytes, got %d", size, n)
+		}
+	}
+
+	// ping is answered while the client waits for a message
+	errc := make(chan error, 1)
+	go func() {
+		err := server.writeFrame(wsOpPing, []byte("ping"))
+		if err != nil {
+			errc <- err
+			return
+		}
+		_, op, payload, err := server.readFrame()
+		if err == nil && (op != wsOpPong || string(payload) != "ping") {
+			err = fmt.Errorf("expect pong, got %d %q", op, payload)
+		}
+		if err == nil {
+			_, err = server.Write([]byte("ok"))
+		}
+		errc <- err
+	}()
+	buf := make([]byte, 10)
+	n, err := client.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(buf[:n]) != "ok" {
+		t.Fatalf("expect ok, got %q", buf[:n])
+	}
+	err = <-errc
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWebSocketClientHandshake(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("Sec-WebSocket-Key")
+		if r.Header.Get("Upgrade") != "websocket" || key == "" {
+			http.Error(w, "bad handshake", http.StatusBadRequest)
+			return
+		}
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", websocketAccept(key))
+		rw.Flush()
+		ws := &wsConn{Conn: conn, r: rw.Reader}
+		defer ws.Close()
+		// echo
+		buf := make([]byte, maxPacketSize)
+		n, err := ws.Read(buf)
+		if err != nil {
+			return
+		}
+		ws.Write(buf[:n])
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse("ws" + ts.URL[len("http"):] + "/ndn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := net.Dial("tcp", u.Host)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ws, err := websocketClientHandshake(conn, u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+	_, err = ws.Write([]byte("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, maxPacketSize)
+	n, err := ws.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(buf[:n]) != "hello" {
+		t.Fatalf("expect hello, got %q", buf[:n])
+	}
+}
+
+func TestWebSocketHandler(t *testing.T) {
+	served := make(chan struct{})
+	ts := httptest.NewServer(&WebSocketHandler{
+		Serve: func(f Face, recv <-chan *Interest) {
+			for range recv {
+			}
+			close(served)
+		},
+	})
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expect %d, got %d", http.StatusBadRequest, resp.StatusCode)
+	}
+
+	u, err := url.Parse("ws" + ts.URL[len("http"):])
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := net.Dial("tcp", u.Host)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ws, err := websocketClientHandshake(conn, u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the face is done after the client leaves
+	ws.Close()
+	<-served
+}